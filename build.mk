@@ -42,6 +42,11 @@ go-index:
 	@echo ":: Generating Go symbols index..." && \
 	$(GO) run ./tools/pkgindexer index -o $(UI)/public/data/go-index.json $(OPTS)
 
+.PHONY: go-exports
+go-exports:
+	@echo ":: Generating Go standard library export data..." && \
+	$(GO) run ./tools/pkgindexer exports -o $(UI)/public/data/go-exports.tar.gz $(OPTS)
+
 .PHONY:check-yarn
 check-yarn:
 	$(call check_tool,$(YARN),'YARN')
@@ -73,7 +78,7 @@ analyzer.wasm:
 wasm: wasm_exec.js analyzer.wasm 
 
 .PHONY: build
-build: check-go check-yarn clean preinstall gen build-server wasm go-index imports-index build-ui
+build: check-go check-yarn clean preinstall gen build-server wasm go-index go-exports imports-index build-ui
 	@echo ":: Copying assets..." && \
 	cp -rfv ./data $(TARGET)/data && \
 	mv -v $(UI)/build $(TARGET)/public && \
@@ -81,7 +86,7 @@ build: check-go check-yarn clean preinstall gen build-server wasm go-index impor
 
 # WASM and generated JSON files are build on CI outside of Docker to speedup build.
 .PHONY: ci-assets
-ci-assets: wasm go-index imports-index
+ci-assets: wasm go-index go-exports imports-index
 
 .PHONY:gen
 gen:
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/x1unix/go-playground/pkg/playground"
+)
+
+type compileFlags struct {
+	outFile         string
+	goVersion       string
+	target          string
+	compilerOptions string
+}
+
+func newCmdCompile(g *globalFlags) *cobra.Command {
+	f := new(compileFlags)
+	cmd := &cobra.Command{
+		Use:   "compile [dir] [-o file]",
+		Short: "Build a WebAssembly program",
+		Long:  "Builds a WebAssembly program from a directory and downloads it. Current directory is used by default.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := playground.ReadDir(dirFromArgs(args))
+			if err != nil {
+				return err
+			}
+
+			client := g.client()
+			rsp, err := client.Compile(cmd.Context(), playground.FilesPayload{
+				Files:           files,
+				CompilerOptions: f.compilerOptions,
+				GoVersion:       f.goVersion,
+				Target:          f.target,
+			})
+			if err != nil {
+				return err
+			}
+
+			if rsp.CompilerOutput != "" {
+				_, _ = io.WriteString(cmd.ErrOrStderr(), rsp.CompilerOutput)
+			}
+
+			outFile := f.outFile
+			if outFile == "" {
+				outFile = rsp.FileName
+			}
+
+			artifact, err := client.GetArtifact(cmd.Context(), rsp.FileName)
+			if err != nil {
+				return fmt.Errorf("failed to download artifact: %w", err)
+			}
+
+			defer artifact.Close()
+			if err := writeFile(outFile, artifact); err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), outFile)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&f.outFile, "output", "o", "", "Output file. Artifact file name is used by default")
+	cmd.Flags().StringVar(&f.goVersion, "go-version", "", "Go version used to build a program")
+	cmd.Flags().StringVar(&f.target, "target", "", "WebAssembly build target (js or wasip1)")
+	cmd.Flags().StringVar(&f.compilerOptions, "compiler-options", "", "Extra compiler flags")
+	return cmd
+}
+
+func writeFile(name string, r io.Reader) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %q: %w", name, err)
+	}
+
+	return f.Close()
+}
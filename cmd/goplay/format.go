@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/x1unix/go-playground/pkg/goplay"
+	"github.com/x1unix/go-playground/pkg/playground"
+)
+
+type formatFlags struct {
+	backend   string
+	formatter string
+	style     string
+	local     string
+	write     bool
+	list      bool
+}
+
+func newCmdFormat(g *globalFlags) *cobra.Command {
+	f := new(formatFlags)
+	cmd := &cobra.Command{
+		Use:   "format [dir]",
+		Short: "Format files",
+		Long: "Formats files in a directory and prints result to stdout. Current directory is used by default.\n\n" +
+			"Code is formatted using goimports style unless other style is specified.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := dirFromArgs(args)
+			files, err := playground.ReadDir(dir)
+			if err != nil {
+				return err
+			}
+
+			rsp, err := g.client().Format(cmd.Context(), files, playground.FormatOptions{
+				Backend:     f.backend,
+				Formatter:   f.formatter,
+				Style:       f.style,
+				LocalPrefix: f.local,
+			})
+			if err != nil {
+				return err
+			}
+
+			formatted := rsp.Files
+			changed := changedFiles(files, formatted)
+			if f.list {
+				for _, name := range slices.Sorted(maps.Keys(changed)) {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), name)
+				}
+			}
+
+			if f.write {
+				return playground.WriteDir(dir, changed)
+			}
+
+			if f.list {
+				if len(changed) > 0 {
+					return fmt.Errorf("%d file(s) are not formatted", len(changed))
+				}
+
+				return nil
+			}
+
+			return printFiles(cmd.OutOrStdout(), formatted)
+		},
+	}
+
+	cmd.Flags().StringVarP(&f.backend, "backend", "b", "", "Go Playground backend used to format code (goprev or gotip)")
+	cmd.Flags().StringVar(&f.formatter, "formatter", "", "Formatter provider (upstream or local). Uses server default if empty")
+	cmd.Flags().StringVarP(&f.style, "style", "s", "", "Formatting style (gofmt, gofmt-s, goimports or gofumpt). Uses goimports by default")
+	cmd.Flags().StringVar(&f.local, "local", "", "Comma-separated list of import path prefixes to group after third-party imports")
+	cmd.Flags().BoolVarP(&f.write, "write", "w", false, "Write result to source files instead of stdout")
+	cmd.Flags().BoolVarP(&f.list, "list", "l", false, "List files whose formatting differs and fail if -w is not set")
+	return cmd
+}
+
+// changedFiles returns files which contents differ from original files.
+func changedFiles(orig, formatted map[string]string) map[string]string {
+	changed := make(map[string]string, len(formatted))
+	for name, data := range formatted {
+		if src, ok := orig[name]; !ok || src != data {
+			changed[name] = data
+		}
+	}
+
+	return changed
+}
+
+// printFiles writes files to output in Go Playground multi-file format.
+func printFiles(w io.Writer, files map[string]string) error {
+	fileSet := goplay.NewFileSet(goplay.MaxSnippetSize)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if err := fileSet.Add(name, []byte(files[name])); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.Copy(w, fileSet.Reader())
+	return err
+}
@@ -0,0 +1,102 @@
+// Command goplay is a command line client for the playground server API.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/x1unix/go-playground/pkg/playground"
+)
+
+const (
+	urlEnvVar      = "GOPLAY_URL"
+	defaultTimeout = 2 * time.Minute
+)
+
+type globalFlags struct {
+	url     string
+	timeout time.Duration
+}
+
+func (f globalFlags) client() *playground.Client {
+	return playground.NewClient(&http.Client{Timeout: f.timeout}, f.url)
+}
+
+func newCmdRoot() *cobra.Command {
+	f := new(globalFlags)
+	cmd := &cobra.Command{
+		SilenceUsage: true,
+		Use:          "goplay <command> [flags]",
+		Short:        "Go playground command line client",
+		Long:         "Tool to run, format, share and build Go programs using a playground server",
+	}
+
+	defaultURL := os.Getenv(urlEnvVar)
+	if defaultURL == "" {
+		defaultURL = playground.DefaultURL
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&f.url, "url", "u", defaultURL, "Playground server URL. Can be set using $"+urlEnvVar,
+	)
+	cmd.PersistentFlags().DurationVar(
+		&f.timeout, "timeout", defaultTimeout, "Request timeout",
+	)
+
+	cmd.AddCommand(newCmdRun(f))
+	cmd.AddCommand(newCmdFormat(f))
+	cmd.AddCommand(newCmdShare(f))
+	cmd.AddCommand(newCmdGet(f))
+	cmd.AddCommand(newCmdCompile(f))
+	return cmd
+}
+
+// exitCodeError is returned when program run by a server exited with non-zero code.
+type exitCodeError int
+
+func (err exitCodeError) Error() string {
+	return fmt.Sprintf("exit status %d", int(err))
+}
+
+// ExitCode returns process exit code.
+//
+// Programs terminated without exit code (e.g. by timeout) are reported as failed.
+func (err exitCodeError) ExitCode() int {
+	if err <= 0 {
+		return 1
+	}
+
+	return int(err)
+}
+
+// dirFromArgs returns source directory from positional arguments.
+//
+// Current directory is used if argument is omitted.
+func dirFromArgs(args []string) string {
+	if len(args) == 0 {
+		return "."
+	}
+
+	return args[0]
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	if err := newCmdRoot().ExecuteContext(ctx); err != nil {
+		var exitErr exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/x1unix/go-playground/pkg/goplay"
+	"github.com/x1unix/go-playground/pkg/playground"
+)
+
+type runFlags struct {
+	backend         string
+	vet             bool
+	compilerOptions string
+	goVersion       string
+	target          string
+}
+
+func newCmdRun(g *globalFlags) *cobra.Command {
+	f := new(runFlags)
+	cmd := &cobra.Command{
+		Use:   "run [dir]",
+		Short: "Run a program",
+		Long: "Runs a program from a directory and prints its output. Current directory is used by default.\n\n" +
+			"Exits with program exit code if program failed.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := playground.ReadDir(dirFromArgs(args))
+			if err != nil {
+				return err
+			}
+
+			rsp, err := g.client().Run(cmd.Context(), playground.FilesPayload{
+				Files:           files,
+				CompilerOptions: f.compilerOptions,
+				GoVersion:       f.goVersion,
+				Target:          f.target,
+			}, playground.RunOptions{
+				Backend: f.backend,
+				Vet:     f.vet,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := printEvents(cmd.OutOrStdout(), cmd.ErrOrStderr(), rsp.Events); err != nil {
+				return err
+			}
+
+			if rsp.Status != 0 {
+				// Program output already ends with exit status, like "go run" does.
+				cmd.SilenceErrors = true
+				return exitCodeError(rsp.Status)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&f.backend, "backend", "b", "", "Run backend (goprev, gotip, local or wasm-server). Uses current Go version by default")
+	cmd.Flags().BoolVar(&f.vet, "vet", false, "Run go vet before running a program")
+	cmd.Flags().StringVar(&f.compilerOptions, "compiler-options", "", "Extra compiler flags for server-side backends")
+	cmd.Flags().StringVar(&f.goVersion, "go-version", "", "Go version for wasm-server backend")
+	cmd.Flags().StringVar(&f.target, "target", "", "WebAssembly build target for wasm-server backend (js or wasip1). Uses wasip1 by default")
+	return cmd
+}
+
+// printEvents writes program output to stdout and stderr.
+func printEvents(stdout, stderr io.Writer, events []*goplay.CompileEvent) error {
+	for _, e := range events {
+		dst := stdout
+		if e.Kind == playground.EventKindStderr {
+			dst = stderr
+		}
+
+		if _, err := io.WriteString(dst, e.Message); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
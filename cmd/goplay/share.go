@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/x1unix/go-playground/pkg/playground"
+)
+
+type shareFlags struct {
+	backend         string
+	vet             bool
+	compilerOptions string
+}
+
+func newCmdShare(g *globalFlags) *cobra.Command {
+	f := new(shareFlags)
+	cmd := &cobra.Command{
+		Use:   "share [dir]",
+		Short: "Share files as a snippet",
+		Long:  "Saves files from a directory as a snippet and prints snippet link. Current directory is used by default.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := playground.ReadDir(dirFromArgs(args))
+			if err != nil {
+				return err
+			}
+
+			client := g.client()
+			snippetID, err := client.Share(cmd.Context(), playground.FilesPayload{
+				Files:           files,
+				CompilerOptions: f.compilerOptions,
+				Backend:         f.backend,
+				Vet:             f.vet,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), client.SnippetURL(snippetID))
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&f.backend, "backend", "b", "", "Run backend saved with a snippet")
+	cmd.Flags().BoolVar(&f.vet, "vet", false, "Enable go vet for a snippet")
+	cmd.Flags().StringVar(&f.compilerOptions, "compiler-options", "", "Compiler flags saved with a snippet")
+	return cmd
+}
+
+func newCmdGet(g *globalFlags) *cobra.Command {
+	var outDir string
+	cmd := &cobra.Command{
+		Use:   "get <snippet-id> [-o dir]",
+		Short: "Get shared snippet",
+		Long:  "Prints shared snippet files to stdout or writes them to a directory.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snippet, err := g.client().GetSnippet(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if outDir != "" {
+				return playground.WriteDir(outDir, snippet.Files)
+			}
+
+			return printFiles(cmd.OutOrStdout(), snippet.Files)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outDir, "output", "o", "", "Directory to write snippet files")
+	return cmd
+}
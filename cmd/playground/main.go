@@ -10,24 +10,45 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/x1unix/foundation/app"
 	"github.com/x1unix/go-playground/internal/builder"
 	"github.com/x1unix/go-playground/internal/builder/storage"
 	"github.com/x1unix/go-playground/internal/config"
+	"github.com/x1unix/go-playground/internal/formatter"
+	"github.com/x1unix/go-playground/internal/metrics"
+	"github.com/x1unix/go-playground/internal/modinfo"
+	"github.com/x1unix/go-playground/internal/pkgindex/modindex"
+	"github.com/x1unix/go-playground/internal/sandbox"
 	"github.com/x1unix/go-playground/internal/server"
 	"github.com/x1unix/go-playground/internal/server/backendinfo"
 	"github.com/x1unix/go-playground/internal/server/webutil"
+	"github.com/x1unix/go-playground/internal/snippets"
+	"github.com/x1unix/go-playground/internal/wasmrun"
 	"github.com/x1unix/go-playground/pkg/goplay"
+	"github.com/x1unix/go-playground/pkg/goproxy"
 	"github.com/x1unix/go-playground/pkg/util/cmdutil"
 	"github.com/x1unix/go-playground/pkg/util/osutil"
-	_ "go.uber.org/automaxprocs"
+	"go.uber.org/automaxprocs/maxprocs"
 	"go.uber.org/zap"
 )
 
+const (
+	// sandboxMaxOpenFiles is max number of open files for programs executed by local backend.
+	sandboxMaxOpenFiles = 256
+
+	// sandboxMaxProcesses is max number of processes and threads for programs executed by local backend.
+	sandboxMaxProcesses = 128
+)
+
 // Version is server version symbol. Should be replaced by linker during build
 var Version = "testing"
 
 func main() {
+	// Handle sandbox helper process startup for local run backend.
+	sandbox.Init()
+
 	cfg, err := config.FromEnv(config.FromFlags())
 	if err != nil {
 		cmdutil.FatalOnError(err)
@@ -40,6 +61,11 @@ func main() {
 	zap.ReplaceGlobals(logger)
 	defer logger.Sync() //nolint:errcheck
 
+	// Set explicitly instead of on import to keep sandbox helper process output clean.
+	if _, err := maxprocs.Set(maxprocs.Logger(logger.Sugar().Infof)); err != nil {
+		logger.Warn("failed to set GOMAXPROCS", zap.Error(err))
+	}
+
 	if err := cfg.Validate(); err != nil {
 		logger.Fatal("invalid server configuration", zap.Error(err))
 	}
@@ -53,7 +79,7 @@ func start(logger *zap.Logger, cfg *config.Config) error {
 	logger.Info("Starting service",
 		zap.String("version", Version), zap.Any("config", cfg))
 
-	store, err := storage.NewLocalStorage(logger, cfg.Build.BuildDir)
+	store, err := newArtifactStore(logger, cfg.Build)
 	if err != nil {
 		return err
 	}
@@ -61,23 +87,101 @@ func start(logger *zap.Logger, cfg *config.Config) error {
 	ctx, _ := app.GetApplicationContext()
 	wg := &sync.WaitGroup{}
 
+	var (
+		metricsRegistry *prometheus.Registry
+		appMetrics      *metrics.Metrics
+	)
+	if cfg.Metrics.Enabled {
+		metricsRegistry = prometheus.NewRegistry()
+		metricsRegistry.MustRegister(
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		)
+		appMetrics = metrics.New(metricsRegistry)
+	}
+
 	// Initialize services
 	playgroundClient := goplay.NewClient(cfg.Playground.PlaygroundURL, goplay.DefaultUserAgent,
 		cfg.Playground.ConnectTimeout)
+	playgroundClient.WrapTransport(appMetrics.InstrumentRoundTripper)
+
+	toolchains, err := builder.LoadToolchains(ctx, cfg.Build.GoRoots)
+	if err != nil {
+		return fmt.Errorf("failed to load Go toolchains: %w", err)
+	}
+
+	logger.Info("Loaded Go toolchains", zap.Strings("versions", toolchains.Versions()))
 	buildCfg := builder.BuildEnvironmentConfig{
+		Toolchains:                   toolchains,
 		KeepGoModCache:               cfg.Build.SkipModuleCleanup,
 		IncludedEnvironmentVariables: osutil.SelectEnvironmentVariables(cfg.Build.BypassEnvVarsList...),
+		Pool: builder.PoolConfig{
+			MaxWorkers:         cfg.Build.MaxParallelBuilds,
+			MaxQueueSize:       cfg.Build.BuildQueueSize,
+			MaxClientQueueSize: cfg.Build.ClientBuildQueueSize,
+		},
 	}
 	logger.Debug("Loaded list of environment variables used by compiler",
 		zap.Any("vars", buildCfg.IncludedEnvironmentVariables))
-	buildSvc := builder.NewBuildService(zap.L(), buildCfg, store)
+	buildSvc := builder.NewBuildService(zap.L(), buildCfg, store).WithMetrics(appMetrics)
 
 	// Start cleanup service
 	if !cfg.Build.SkipModuleCleanup {
-		cleanupSvc := builder.NewCleanupDispatchService(zap.L(), cfg.Build.CleanupInterval, buildSvc, store)
+		cleanupSvc := builder.NewCleanupDispatchService(zap.L(), cfg.Build.CleanupInterval, buildSvc, store).
+			WithMetrics(appMetrics)
 		go cleanupSvc.Start(ctx)
 	}
 
+	var sandboxSvc *sandbox.Service
+	if cfg.Sandbox.Enabled {
+		sandboxSvc, err = sandbox.NewService(zap.L(), sandbox.Config{
+			WorkDir:          cfg.Build.BuildDir,
+			BuildTimeout:     cfg.Build.GoBuildTimeout,
+			RunTimeout:       cfg.Sandbox.RunTimeout,
+			MaxOutputSize:    cfg.Sandbox.MaxOutputSize,
+			BuildEnvironment: buildCfg.IncludedEnvironmentVariables,
+			BuildPool:        buildSvc.Pool(),
+			UID:              cfg.Sandbox.UID,
+			GID:              cfg.Sandbox.GID,
+			Limits: sandbox.Limits{
+				CPUTime:   cfg.Sandbox.RunTimeout,
+				Memory:    cfg.Sandbox.MaxMemory,
+				FileSize:  uint64(cfg.Sandbox.MaxOutputSize),
+				OpenFiles: sandboxMaxOpenFiles,
+				Processes: sandboxMaxProcesses,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to initialize local run backend: %w", err)
+		}
+	}
+
+	var wasmExecutor *wasmrun.Executor
+	if cfg.WasmRun.Enabled {
+		wasmExecutor = wasmrun.NewExecutor(zap.L(), wasmrun.Config{
+			RunTimeout:    cfg.WasmRun.RunTimeout,
+			MaxMemory:     cfg.WasmRun.MaxMemory,
+			MaxOutputSize: cfg.WasmRun.MaxOutputSize,
+		})
+	}
+
+	snippetStore, err := newSnippetStore(cfg.Snippets, playgroundClient)
+	if err != nil {
+		return err
+	}
+
+	moduleProxy, err := goproxy.NewProxyList(http.DefaultClient, goproxy.ProxyConfig{
+		Proxies: cfg.Modules.ProxyURL,
+		Private: cfg.Modules.Private,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid Go modules proxy config: %w", err)
+	}
+
+	modulesSvc := modindex.NewService(zap.L(), moduleProxy, filepath.Join(cfg.Build.BuildDir, "module-index"))
+	moduleVersionsSvc := modinfo.NewService(moduleProxy, cfg.Modules.CacheTTL)
+
+	localFormatter := newFormatter(logger, cfg)
 	backendsInfoSvc := backendinfo.NewBackendVersionService(zap.L(), playgroundClient, backendinfo.ServiceConfig{
 		CacheFile: filepath.Join(cfg.Build.BuildDir, "go-versions.json"),
 		TTL:       backendinfo.DefaultVersionCacheTTL,
@@ -93,13 +197,32 @@ func start(logger *zap.Logger, cfg *config.Config) error {
 	server.NewAPIv1Handler(svcCfg, playgroundClient, buildSvc, backendsInfoSvc).
 		Mount(apiRouter)
 
+	trustedProxies, err := cfg.HTTP.TrustedProxyNetworks()
+	if err != nil {
+		return err
+	}
+
 	apiv2Router := apiRouter.PathPrefix("/v2").Subrouter()
 	server.NewAPIv2Handler(server.APIv2HandlerConfig{
-		Client:       playgroundClient,
-		Builder:      buildSvc,
-		BuildTimeout: cfg.Build.GoBuildTimeout,
+		Client:            playgroundClient,
+		Builder:           buildSvc,
+		Snippets:          snippetStore,
+		BuildTimeout:      cfg.Build.GoBuildTimeout,
+		TrustedProxies:    trustedProxies,
+		Sandbox:           sandboxSvc,
+		WasmExecutor:      wasmExecutor,
+		Formatter:         localFormatter,
+		DefaultFormatter:  cfg.Format.Provider,
+		FormatLocalPrefix: cfg.Format.LocalPrefix,
+		Modules:           modulesSvc,
+		ModuleVersions:    moduleVersionsSvc,
 	}).Mount(apiv2Router)
 
+	if metricsRegistry != nil {
+		r.Path(cfg.Metrics.Path).
+			Handler(metrics.Handler(metricsRegistry))
+	}
+
 	// Web UI routes
 	tplVars := server.TemplateArguments{
 		GoogleTagID: cfg.Services.GoogleAnalyticsID,
@@ -138,6 +261,63 @@ func start(logger *zap.Logger, cfg *config.Config) error {
 	return nil
 }
 
+type artifactStore interface {
+	storage.StoreProvider
+	builder.Cleaner
+}
+
+func newArtifactStore(logger *zap.Logger, cfg config.BuildConfig) (artifactStore, error) {
+	local, err := storage.NewLocalStorage(logger, cfg.BuildDir, storage.EvictionPolicy{
+		MaxAge:  cfg.CacheMaxAge,
+		MaxSize: cfg.CacheMaxSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.ArtifactStorageURL == "" {
+		return local, nil
+	}
+
+	blobs, err := storage.NewBlobStoreFromURL(cfg.ArtifactStorageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize shared artifact storage: %w", err)
+	}
+
+	return storage.NewSharedStorage(logger, local, blobs), nil
+}
+
+func newSnippetStore(cfg config.SnippetsConfig, client *goplay.Client) (snippets.Store, error) {
+	if cfg.Store != snippets.StoreTypeLocal {
+		return snippets.NewPlaygroundStore(client), nil
+	}
+
+	store, err := snippets.NewLocalStore(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize local snippet store: %w", err)
+	}
+
+	return store, nil
+}
+
+// newFormatter returns local code formatter.
+//
+// Returns nil if standard library index can't be loaded, Go Playground is used to format code in this case.
+func newFormatter(logger *zap.Logger, cfg *config.Config) *formatter.Formatter {
+	indexFile := cfg.Format.StdlibIndexFile
+	if indexFile == "" {
+		indexFile = filepath.Join(cfg.HTTP.AssetsDir, "data", "go-index.json")
+	}
+
+	stdlib, err := formatter.LoadStdlibIndex(indexFile)
+	if err != nil {
+		logger.Warn("Failed to load Go packages index, local formatter is disabled", zap.Error(err))
+		return nil
+	}
+
+	return formatter.NewFormatter(stdlib)
+}
+
 func startHttpServer(ctx context.Context, wg *sync.WaitGroup, server *http.Server) error {
 	logger := zap.S()
 	go func() {
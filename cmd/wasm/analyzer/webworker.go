@@ -4,15 +4,43 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"strings"
+	"sync"
 	"syscall/js"
 
+	"golang.org/x/tools/go/analysis"
+
 	"github.com/x1unix/go-playground/internal/analyzer/check"
+	"github.com/x1unix/go-playground/internal/pkgindex/index"
 	"github.com/x1unix/go-playground/pkg/worker"
+	"typefox.dev/lsp"
+)
+
+var (
+	// checkerLock guards checker, as packages importer isn't safe for concurrent use.
+	checkerLock sync.Mutex
+
+	importer  types.Importer
+	analyzers = check.DefaultAnalyzers()
+	packages  check.PackageIndex
+
+	// checker reports only syntax errors until export data is loaded.
+	checker = check.NewChecker(nil)
 )
 
 func main() {
 	worker.ExportAndStart(worker.Exports{
-		"analyzeCode": analyzeCode,
+		"analyzeCode":      analyzeCode,
+		"analyzeFiles":     analyzeFiles,
+		"codeActions":      codeActions,
+		"fileCodeActions":  fileCodeActions,
+		"loadExportData":   loadExportData,
+		"loadPackageIndex": loadPackageIndex,
+		"setAnalyzers":     setAnalyzers,
 	})
 }
 
@@ -22,5 +50,175 @@ func analyzeCode(this js.Value, args worker.Args) (interface{}, error) {
 		return nil, err
 	}
 
-	return check.Check(code)
+	checkerLock.Lock()
+	defer checkerLock.Unlock()
+	return checker.Check(code)
+}
+
+// analyzeFiles checks project files passed as object with file contents by path.
+//
+// Problems are reported by file path.
+func analyzeFiles(this js.Value, args worker.Args) (interface{}, error) {
+	var files jsFiles
+	if err := args.Bind(&files); err != nil {
+		return nil, err
+	}
+
+	checkerLock.Lock()
+	defer checkerLock.Unlock()
+	return checker.CheckFiles(files)
+}
+
+// codeActions returns list of quick fixes for problems in a passed range.
+func codeActions(this js.Value, args worker.Args) (interface{}, error) {
+	var (
+		code string
+		rng  jsRange
+	)
+	if err := args.Bind(&code, &rng); err != nil {
+		return nil, err
+	}
+
+	checkerLock.Lock()
+	defer checkerLock.Unlock()
+	return checker.CodeActions(code, lsp.Range(rng))
+}
+
+// fileCodeActions returns list of quick fixes for problems in a passed range of a project file.
+//
+// Project files are passed in the same format as to analyzeFiles.
+func fileCodeActions(this js.Value, args worker.Args) (interface{}, error) {
+	var (
+		files    jsFiles
+		fileName string
+		rng      jsRange
+	)
+	if err := args.Bind(&files, &fileName, &rng); err != nil {
+		return nil, err
+	}
+
+	checkerLock.Lock()
+	defer checkerLock.Unlock()
+	return checker.FileCodeActions(files, fileName, lsp.Range(rng))
+}
+
+// loadExportData loads standard library export data archive to enable type checking.
+//
+// Archive is generated by "pkgindexer exports" command.
+func loadExportData(this js.Value, args worker.Args) (interface{}, error) {
+	var archive []byte
+	if err := args.Bind(&archive); err != nil {
+		return nil, err
+	}
+
+	data, err := check.ReadExportData(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+
+	checkerLock.Lock()
+	defer checkerLock.Unlock()
+	importer = data.Importer()
+	resetChecker()
+	return nil, nil
+}
+
+// loadPackageIndex loads Go index file to suggest missing imports.
+//
+// Index is generated by "pkgindexer index" command.
+func loadPackageIndex(this js.Value, args worker.Args) (interface{}, error) {
+	var data string
+	if err := args.Bind(&data); err != nil {
+		return nil, err
+	}
+
+	var indexFile index.GoIndexFile
+	if err := json.Unmarshal([]byte(data), &indexFile); err != nil {
+		return nil, err
+	}
+
+	checkerLock.Lock()
+	defer checkerLock.Unlock()
+	packages = check.NewPackageIndex(indexFile.Packages.Names, indexFile.Packages.Paths)
+	checker.WithPackageIndex(packages)
+	return nil, nil
+}
+
+// setAnalyzers accepts comma-separated list of enabled vet analyzers.
+//
+// Empty list disables vet checks.
+func setAnalyzers(this js.Value, args worker.Args) (interface{}, error) {
+	var list string
+	if err := args.Bind(&list); err != nil {
+		return nil, err
+	}
+
+	var enabled []*analysis.Analyzer
+	if list = strings.TrimSpace(list); list != "" {
+		var err error
+		enabled, err = check.LookupAnalyzers(strings.Split(list, ",")...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	checkerLock.Lock()
+	defer checkerLock.Unlock()
+	analyzers = enabled
+	if importer != nil {
+		resetChecker()
+	}
+
+	return nil, nil
+}
+
+// resetChecker recreates checker with current settings.
+//
+// Should be called with acquired checkerLock.
+func resetChecker() {
+	checker = check.NewChecker(importer, analyzers...).WithPackageIndex(packages)
+}
+
+// jsRange is LSP range passed from JS as a plain object.
+type jsRange lsp.Range
+
+func (r *jsRange) UnmarshalValue(val js.Value) error {
+	if val.Type() != js.TypeObject {
+		return worker.NewTypeError(js.TypeObject, val.Type())
+	}
+
+	r.Start = jsPosition(val.Get("start"))
+	r.End = jsPosition(val.Get("end"))
+	return nil
+}
+
+func jsPosition(val js.Value) lsp.Position {
+	return lsp.Position{
+		Line:      uint32(val.Get("line").Int()),
+		Character: uint32(val.Get("character").Int()),
+	}
+}
+
+// jsFiles is a map of file contents by path passed from JS as a plain object.
+type jsFiles map[string]string
+
+func (f *jsFiles) UnmarshalValue(val js.Value) error {
+	if val.Type() != js.TypeObject {
+		return worker.NewTypeError(js.TypeObject, val.Type())
+	}
+
+	keys := js.Global().Get("Object").Call("keys", val)
+	files := make(jsFiles, keys.Length())
+	for i := range keys.Length() {
+		name := keys.Index(i).String()
+		contents := val.Get(name)
+		if contents.Type() != js.TypeString {
+			return fmt.Errorf("file %q: %w", name, worker.NewTypeError(js.TypeString, contents.Type()))
+		}
+
+		files[name] = contents.String()
+	}
+
+	*f = files
+	return nil
 }
@@ -4,14 +4,19 @@ go 1.26.0
 
 require (
 	github.com/TheZeroSlave/zapsentry v1.10.0
+	github.com/alicebob/miniredis/v2 v2.37.0
 	github.com/avast/retry-go v3.0.0+incompatible
 	github.com/gorilla/mux v1.7.3
 	github.com/hashicorp/go-set/v3 v3.0.0
 	github.com/kelseyhightower/envconfig v1.4.0
+	github.com/minio/minio-go/v7 v7.0.97
 	github.com/pkg/errors v0.8.1
+	github.com/prometheus/client_golang v1.22.0
+	github.com/redis/go-redis/v9 v9.9.0
 	github.com/samber/lo v1.38.1
 	github.com/spf13/cobra v1.8.1
-	github.com/stretchr/testify v1.8.2
+	github.com/stretchr/testify v1.10.0
+	github.com/tetratelabs/wazero v1.10.1
 	github.com/tevino/abool v0.0.0-20170917061928-9b9efcf221b5
 	github.com/vmihailenco/msgpack/v5 v5.4.1
 	github.com/x1unix/foundation v1.0.0
@@ -19,24 +24,51 @@ require (
 	go.uber.org/mock v0.4.0
 	go.uber.org/zap v1.21.0
 	golang.org/x/exp v0.0.0-20230321023759-10a507213a29
-	golang.org/x/sync v0.7.0
-	golang.org/x/time v0.0.0-20201208040808-7e3f01d25324
+	golang.org/x/mod v0.25.0
+	golang.org/x/sync v0.15.0
+	golang.org/x/sys v0.40.0
+	golang.org/x/tools v0.33.0
+	mvdan.cc/gofumpt v0.8.0
 )
 
 require (
 	github.com/benbjohnson/clock v1.1.0 // indirect
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
 	github.com/getsentry/sentry-go v0.13.0 // indirect
+	github.com/go-ini/ini v1.67.0 // indirect
+	github.com/google/go-cmp v0.7.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/klauspost/cpuid/v2 v2.2.11 // indirect
+	github.com/klauspost/crc32 v1.3.0 // indirect
+	github.com/kylelemons/godebug v1.1.0 // indirect
+	github.com/minio/crc64nvme v1.1.0 // indirect
+	github.com/minio/md5-simd v1.1.2 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/philhofer/fwd v1.2.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_model v0.6.1 // indirect
+	github.com/prometheus/common v0.62.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	github.com/rs/xid v1.6.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/tinylib/msgp v1.3.0 // indirect
 	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.8.0 // indirect
+	golang.org/x/crypto v0.38.0 // indirect
 	golang.org/x/exp/event v0.0.0-20260112195511-716be5621a96 // indirect
 	golang.org/x/exp/jsonrpc2 v0.0.0-20260212183809-81e46e3db34a // indirect
-	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/text v0.26.0 // indirect
 	golang.org/x/xerrors v0.0.0-20240903120638-7835f813f4da // indirect
+	google.golang.org/protobuf v1.36.5 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	typefox.dev/lsp v0.0.3 // indirect
 )
@@ -0,0 +1,226 @@
+package check
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"typefox.dev/lsp"
+)
+
+// PackageIndex is a list of import paths by package name.
+type PackageIndex map[string][]string
+
+// NewPackageIndex builds package index from list of package names and import paths.
+//
+// Both slices should have the same length.
+func NewPackageIndex(names, importPaths []string) PackageIndex {
+	index := make(PackageIndex, len(names))
+	for i, name := range names {
+		index[name] = append(index[name], importPaths[i])
+	}
+
+	return index
+}
+
+// CodeActions returns list of quick fixes for problems in a passed range.
+//
+// Edits are keyed by file name.
+func (c *Checker) CodeActions(src string, rng lsp.Range) ([]lsp.CodeAction, error) {
+	return c.FileCodeActions(map[string]string{mainFileName: src}, mainFileName, rng)
+}
+
+// FileCodeActions returns list of quick fixes for problems in a passed range of a file.
+//
+// Files map has the same format as in CheckFiles. Edits are keyed by file name.
+func (c *Checker) FileCodeActions(files map[string]string, fileName string, rng lsp.Range) ([]lsp.CodeAction, error) {
+	diags, _, err := c.diagnoseFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []lsp.CodeAction
+	for _, d := range diags {
+		if d.fileName == fileName && rangesOverlap(d.marker.Range, rng) {
+			actions = append(actions, d.fixes...)
+		}
+	}
+
+	return actions, nil
+}
+
+func (c *Checker) typeErrorToDiagnostic(f *ast.File, err types.Error) diagnostic {
+	d := diagnostic{
+		fileName: err.Fset.Position(err.Pos).Filename,
+		marker:   typeErrorToMarker(f, err),
+	}
+
+	// Error position is unknown, so there is nothing to fix.
+	if f == nil {
+		return d
+	}
+
+	switch {
+	case err.Soft && strings.Contains(err.Msg, " imported ") && strings.HasSuffix(err.Msg, "not used"):
+		if fix, ok := removeImportFix(err.Fset, f, err.Pos, d.marker); ok {
+			d.fixes = append(d.fixes, fix)
+		}
+	case isUndefinedError(err):
+		d.fixes = c.addImportFixes(err.Fset, f, err.Pos, d.marker)
+	}
+
+	return d
+}
+
+// addImportFixes suggests imports for an undefined package name in a selector expression.
+func (c *Checker) addImportFixes(fset *token.FileSet, f *ast.File, pos token.Pos, marker lsp.Diagnostic) []lsp.CodeAction {
+	if len(c.packages) == 0 {
+		return nil
+	}
+
+	var pkgName string
+	ast.Inspect(f, func(n ast.Node) bool {
+		if pkgName != "" || n == nil || n.Pos() > pos || n.End() <= pos {
+			return false
+		}
+
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok && ident.Pos() == pos {
+				pkgName = ident.Name
+				return false
+			}
+		}
+
+		return true
+	})
+
+	importPaths := c.packages[pkgName]
+	fixes := make([]lsp.CodeAction, 0, len(importPaths))
+	for _, importPath := range importPaths {
+		edit := addImportEdit(fset, f, importPath)
+		changes := fileChanges(fset, f, edit)
+		fixes = append(fixes, newQuickFix("Add import "+strconv.Quote(importPath), marker, changes))
+	}
+
+	if len(fixes) == 1 {
+		fixes[0].IsPreferred = true
+	}
+
+	return fixes
+}
+
+func addImportEdit(fset *token.FileSet, f *ast.File, importPath string) lsp.TextEdit {
+	spec := strconv.Quote(importPath)
+	var decl *ast.GenDecl
+	for _, d := range f.Decls {
+		if genDecl, ok := d.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			decl = genDecl
+			break
+		}
+	}
+
+	switch {
+	case decl == nil:
+		return insertEdit(fset, f.Name.End(), "\n\nimport "+spec)
+	case !decl.Lparen.IsValid():
+		return insertEdit(fset, decl.End(), "\nimport "+spec)
+	case fset.Position(decl.Rparen).Column == 1:
+		return insertEdit(fset, decl.Rparen, "\t"+spec+"\n")
+	default:
+		return insertEdit(fset, decl.Rparen, "\n\t"+spec+"\n")
+	}
+}
+
+// removeImportFix returns a fix which removes unused import spec at a position.
+func removeImportFix(fset *token.FileSet, f *ast.File, pos token.Pos, marker lsp.Diagnostic) (lsp.CodeAction, bool) {
+	for _, d := range f.Decls {
+		decl, ok := d.(*ast.GenDecl)
+		if !ok || decl.Tok != token.IMPORT {
+			continue
+		}
+
+		i := slices.IndexFunc(decl.Specs, func(spec ast.Spec) bool {
+			return spec.Pos() <= pos && pos < spec.End()
+		})
+		if i == -1 {
+			continue
+		}
+
+		// Remove the whole declaration if it contains only a single spec.
+		var node ast.Node = decl
+		if len(decl.Specs) > 1 {
+			node = decl.Specs[i]
+		}
+
+		edit := lsp.TextEdit{Range: linesRange(fset, node.Pos(), node.End())}
+		return newQuickFix("Remove unused import", marker, fileChanges(fset, f, edit)), true
+	}
+
+	return lsp.CodeAction{}, false
+}
+
+// newQuickFix returns a new quick fix action. Changes are keyed by file name.
+func newQuickFix(title string, marker lsp.Diagnostic, changes map[lsp.DocumentURI][]lsp.TextEdit) lsp.CodeAction {
+	return lsp.CodeAction{
+		Title:       title,
+		Kind:        lsp.QuickFix,
+		Diagnostics: []lsp.Diagnostic{marker},
+		Edit:        &lsp.WorkspaceEdit{Changes: changes},
+	}
+}
+
+func fileChanges(fset *token.FileSet, f *ast.File, edits ...lsp.TextEdit) map[lsp.DocumentURI][]lsp.TextEdit {
+	fileName := fset.File(f.Pos()).Name()
+	return map[lsp.DocumentURI][]lsp.TextEdit{
+		lsp.DocumentURI(fileName): edits,
+	}
+}
+
+func insertEdit(fset *token.FileSet, pos token.Pos, text string) lsp.TextEdit {
+	return lsp.TextEdit{
+		Range:   editRange(fset, pos, pos),
+		NewText: text,
+	}
+}
+
+// editRange returns text edit range between two positions.
+//
+// Unlike posRange, empty range is preserved to allow insertions.
+func editRange(fset *token.FileSet, start, end token.Pos) lsp.Range {
+	if !end.IsValid() {
+		end = start
+	}
+
+	startPos, endPos := fset.Position(start), fset.Position(end)
+	return lsp.Range{
+		Start: lsp.Position{
+			Line:      normalizeLSPPosition(startPos.Line - 1),
+			Character: normalizeLSPPosition(startPos.Column - 1),
+		},
+		End: lsp.Position{
+			Line:      normalizeLSPPosition(endPos.Line - 1),
+			Character: normalizeLSPPosition(endPos.Column - 1),
+		},
+	}
+}
+
+// linesRange returns range of whole lines between two positions including trailing line break.
+func linesRange(fset *token.FileSet, start, end token.Pos) lsp.Range {
+	startPos := fset.Position(start)
+	endPos := fset.Position(end)
+	return lsp.Range{
+		Start: lsp.Position{Line: normalizeLSPPosition(startPos.Line - 1)},
+		End:   lsp.Position{Line: normalizeLSPPosition(endPos.Line)},
+	}
+}
+
+func rangesOverlap(a, b lsp.Range) bool {
+	return !positionLess(a.End, b.Start) && !positionLess(b.End, a.Start)
+}
+
+func positionLess(a, b lsp.Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
@@ -1,23 +1,126 @@
 package check
 
 import (
-	"errors"
-	"go/parser"
-	"go/scanner"
+	"go/ast"
 	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"typefox.dev/lsp"
 )
 
+// mainFileName is a name of checked file.
+const mainFileName = "main.go"
+
+// typesSizes is type sizes of Go Playground target platform.
+var typesSizes = types.SizesFor("gc", "amd64")
+
+// Checker checks Go code for syntax and type errors.
+type Checker struct {
+	importer  types.Importer
+	analyzers []*analysis.Analyzer
+	packages  PackageIndex
+}
+
+// NewChecker returns a new checker which uses passed importer to resolve imported packages.
+//
+// Type checking is skipped if importer is nil.
+// Passed vet analyzers run only if code has no type errors.
+func NewChecker(importer types.Importer, analyzers ...*analysis.Analyzer) *Checker {
+	return &Checker{importer: importer, analyzers: analyzers}
+}
+
+// WithPackageIndex sets list of known packages used to suggest missing imports.
+func (c *Checker) WithPackageIndex(packages PackageIndex) *Checker {
+	c.packages = packages
+	return c
+}
+
 // Check checks Go code and returns check result
 func Check(src string) (*Result, error) {
-	fset := token.NewFileSet()
-	_, err := parser.ParseFile(fset, "main.go", src, parser.DeclarationErrors)
-	if err == nil {
-		return &Result{HasErrors: false}, nil
+	return NewChecker(nil).Check(src)
+}
+
+// Check checks Go code and returns check result.
+//
+// Type errors are reported only if code doesn't contain syntax errors.
+func (c *Checker) Check(src string) (*Result, error) {
+	diags, hasErrors, err := c.diagnose(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var markers []lsp.Diagnostic
+	for _, d := range diags {
+		markers = append(markers, d.marker)
+	}
+
+	return &Result{HasErrors: hasErrors, Markers: markers}, nil
+}
+
+// diagnostic is a reported problem with a list of fixes.
+type diagnostic struct {
+	fileName string
+	marker   lsp.Diagnostic
+	fixes    []lsp.CodeAction
+}
+
+func (c *Checker) diagnose(src string) ([]diagnostic, bool, error) {
+	return c.diagnoseFiles(map[string]string{mainFileName: src})
+}
+
+// typeCheck type-checks package files.
+//
+// Module path is used to report import errors of project packages.
+func (c *Checker) typeCheck(fset *token.FileSet, files []*ast.File, modPath string, importer types.Importer) (*types.Package, *types.Info, []types.Error) {
+	var typeErrs []types.Error
+	info := &types.Info{
+		Types:        make(map[ast.Expr]types.TypeAndValue),
+		Defs:         make(map[*ast.Ident]types.Object),
+		Uses:         make(map[*ast.Ident]types.Object),
+		Implicits:    make(map[ast.Node]types.Object),
+		Instances:    make(map[*ast.Ident]types.Instance),
+		Selections:   make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:       make(map[ast.Node]*types.Scope),
+		FileVersions: make(map[*ast.File]string),
 	}
 
-	if errList, ok := errors.AsType[scanner.ErrorList](err); ok {
-		return &Result{HasErrors: true, Markers: errorsListToMarkers(errList)}, nil
+	cfg := types.Config{
+		Sizes:    typesSizes,
+		Importer: importer,
+		Error: func(err error) {
+			typeErr, ok := err.(types.Error)
+			if !ok || isExternalImportError(typeErr, modPath) {
+				return
+			}
+
+			typeErrs = append(typeErrs, typeErr)
+		},
 	}
 
-	return nil, err
+	// Errors are collected by error handler.
+	pkg, _ := cfg.Check(files[0].Name.Name, fset, files, info)
+	return pkg, info, typeErrs
+}
+
+// isExternalImportError returns whether error is caused by a non-local package missing in export data.
+//
+// Such errors are ignored as export data contains only standard library packages.
+// Type checker suppresses errors on access to members of unresolved packages.
+//
+// Import errors of project packages, like import cycles or missing subpackages, are reported.
+func isExternalImportError(err types.Error, modPath string) bool {
+	importPath, ok := strings.CutPrefix(err.Msg, "could not import ")
+	if !ok {
+		return false
+	}
+
+	importPath, _, _ = strings.Cut(importPath, " ")
+	return !isLocalImportPath(importPath, modPath)
+}
+
+// isLocalImportPath returns whether import path belongs to a module.
+func isLocalImportPath(importPath, modPath string) bool {
+	return importPath == modPath || strings.HasPrefix(importPath, modPath+"/")
 }
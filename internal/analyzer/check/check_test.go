@@ -1,9 +1,19 @@
 package check
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"typefox.dev/lsp"
 )
 
 func TestCheck(t *testing.T) {
@@ -13,3 +23,535 @@ func TestCheck(t *testing.T) {
 	require.True(t, got.HasErrors)
 	require.NotEmpty(t, got.Markers)
 }
+
+func TestChecker_Check(t *testing.T) {
+	checker := NewChecker(testExportData(t, "fmt", "strings").Importer())
+	cases := map[string]struct {
+		src    string
+		expect []lsp.Diagnostic
+	}{
+		"valid code": {
+			src: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n",
+		},
+		"undefined identifier": {
+			src: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(foo.Bar())\n}\n",
+			expect: []lsp.Diagnostic{
+				{
+					Severity: lsp.SeverityError,
+					Message:  "undefined: foo",
+					Range:    testRange(5, 13, 5, 16),
+				},
+			},
+		},
+		"type mismatch": {
+			src: "package main\n\nimport \"strings\"\n\nfunc main() {\n\tvar n int = strings.ToUpper(\"a\")\n\t_ = n\n}\n",
+			expect: []lsp.Diagnostic{
+				{
+					Severity: lsp.SeverityError,
+					Message:  `cannot use strings.ToUpper("a") (value of type string) as int value in variable declaration`,
+					Range:    testRange(5, 13, 5, 33),
+				},
+			},
+		},
+		"unused import and variable": {
+			src: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tx := 1\n}\n",
+			expect: []lsp.Diagnostic{
+				{
+					Severity: lsp.SeverityError,
+					Message:  `"fmt" imported and not used`,
+					Range:    testRange(2, 7, 2, 12),
+					Tags:     []lsp.DiagnosticTag{lsp.Unnecessary},
+				},
+				{
+					Severity: lsp.SeverityError,
+					Message:  "declared and not used: x",
+					Range:    testRange(5, 1, 5, 2),
+					Tags:     []lsp.DiagnosticTag{lsp.Unnecessary},
+				},
+			},
+		},
+		"ignore unknown packages": {
+			src: "package main\n\nimport \"example.com/foo\"\n\nfunc main() {\n\tfoo.Bar()\n}\n",
+		},
+		"non-ASCII columns": {
+			src: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"привет😀\", foo)\n}\n",
+			expect: []lsp.Diagnostic{
+				{
+					Severity: lsp.SeverityError,
+					Message:  "undefined: foo",
+					Range:    testRange(5, 25, 5, 28),
+				},
+			},
+		},
+		"non-ASCII syntax error": {
+			src: "package main\n\nfunc main() {\n\t_ = \"ü\" + )\n}\n",
+			expect: []lsp.Diagnostic{
+				{
+					Severity: lsp.SeverityError,
+					Message:  "expected operand, found ')'",
+					Range:    testRange(3, 11, 3, 12),
+				},
+				{
+					Severity: lsp.SeverityError,
+					Message:  "expected ';', found 'EOF'",
+					Range:    testRange(4, 2, 4, 3),
+				},
+			},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, err := checker.Check(c.src)
+			require.NoError(t, err)
+			require.Equal(t, len(c.expect) > 0, got.HasErrors)
+			require.ElementsMatch(t, c.expect, got.Markers)
+		})
+	}
+}
+
+func TestChecker_Check_Vet(t *testing.T) {
+	importer := testExportData(t, "fmt", "sync").Importer()
+	src := "package main\n\nimport (\n\t\"fmt\"\n\t\"sync\"\n)\n\n" +
+		"func lock(m sync.Mutex) {}\n\n" +
+		"func main() {\n\tfmt.Printf(\"%d\", \"foo\")\n\tx := 1\n\tif true {\n\t\tx := 2\n\t\t_ = x\n\t}\n\t_ = x\n}\n"
+
+	cases := map[string]struct {
+		analyzers []string
+		expect    []lsp.Diagnostic
+	}{
+		"selected analyzers": {
+			analyzers: []string{"printf", "copylocks"},
+			expect: []lsp.Diagnostic{
+				{
+					Severity: lsp.SeverityWarning,
+					Source:   "copylocks",
+					Message:  "lock passes lock by value: sync.Mutex",
+					Range:    testRange(7, 12, 7, 22),
+				},
+				{
+					Severity: lsp.SeverityWarning,
+					Source:   "printf",
+					Message:  "fmt.Printf format %d has arg \"foo\" of wrong type string",
+					Range:    testRange(10, 1, 10, 24),
+				},
+			},
+		},
+		"shadow": {
+			analyzers: []string{"shadow"},
+			expect: []lsp.Diagnostic{
+				{
+					Severity: lsp.SeverityWarning,
+					Source:   "shadow",
+					Message:  `declaration of "x" shadows declaration at line 12`,
+					Range:    testRange(13, 2, 13, 3),
+				},
+			},
+		},
+		"disabled": {},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			analyzers, err := LookupAnalyzers(c.analyzers...)
+			require.NoError(t, err)
+
+			got, err := NewChecker(importer, analyzers...).Check(src)
+			require.NoError(t, err)
+			require.False(t, got.HasErrors)
+			require.Equal(t, c.expect, got.Markers)
+		})
+	}
+
+	t.Run("default analyzers", func(t *testing.T) {
+		got, err := NewChecker(importer, DefaultAnalyzers()...).Check(src)
+		require.NoError(t, err)
+		require.Len(t, got.Markers, 2)
+	})
+
+	t.Run("unknown analyzer", func(t *testing.T) {
+		_, err := LookupAnalyzers("foo")
+		require.EqualError(t, err, `unknown analyzer "foo"`)
+	})
+}
+
+func TestChecker_CheckFiles(t *testing.T) {
+	importer := testExportData(t, "fmt").Importer()
+	cases := map[string]struct {
+		files      map[string]string
+		noImporter bool
+		expectErr  bool
+		expect     map[string][]lsp.Diagnostic
+	}{
+		"valid project": {
+			files: map[string]string{
+				"go.mod":      "module example.com/foo\n\ngo 1.26\n",
+				"main.go":     "package main\n\nimport \"example.com/foo/bar\"\n\nfunc main() {\n\tbar.Hello(name)\n}\n",
+				"vars.go":     "package main\n\nconst name = \"gopher\"\n",
+				"bar/bar.go":  "package bar\n\nimport \"fmt\"\n\nfunc Hello(name string) {\n\tfmt.Println(name)\n}\n",
+				"bar/util.go": "package bar\n\nfunc init() {}\n",
+			},
+			expect: map[string][]lsp.Diagnostic{},
+		},
+		"default module path": {
+			files: map[string]string{
+				"main.go":    "package main\n\nimport \"app/bar\"\n\nfunc main() {\n\tbar.Hello(1)\n}\n",
+				"bar/bar.go": "package bar\n\nfunc Hello(name string) {}\n",
+			},
+			expectErr: true,
+			expect: map[string][]lsp.Diagnostic{
+				"main.go": {
+					{
+						Severity: lsp.SeverityError,
+						Message:  "cannot use 1 (untyped int constant) as string value in argument to bar.Hello",
+						Range:    testRange(5, 11, 5, 12),
+					},
+				},
+			},
+		},
+		"syntax errors": {
+			files: map[string]string{
+				"main.go":    "package main\n\nfunc main() {\n",
+				"bar/bar.go": "package bar\n\nfunc Hello( {}\n",
+			},
+			expectErr: true,
+			expect: map[string][]lsp.Diagnostic{
+				"main.go": {
+					{
+						Severity: lsp.SeverityError,
+						Message:  "expected '}', found 'EOF'",
+						Range:    testRange(2, 14, 2, 15),
+					},
+				},
+				"bar/bar.go": {
+					{
+						Severity: lsp.SeverityError,
+						Message:  "expected ')', found '{'",
+						Range:    testRange(2, 12, 2, 13),
+					},
+				},
+			},
+		},
+		"mismatched package": {
+			noImporter: true,
+			files: map[string]string{
+				"main.go":      "package main\n\nfunc main() {}\n",
+				"foo.go":       "package foo\n",
+				"main_test.go": "package main_test\n",
+			},
+			expectErr: true,
+			expect: map[string][]lsp.Diagnostic{
+				"main.go": {
+					{
+						Severity: lsp.SeverityError,
+						Message:  "found packages foo (foo.go) and main (main.go) in .",
+						Range:    testRange(0, 8, 0, 12),
+					},
+				},
+			},
+		},
+		"import cycle": {
+			files: map[string]string{
+				"go.mod":     "module example.com/foo\n",
+				"main.go":    "package main\n\nimport \"example.com/foo/bar\"\n\nfunc main() {\n\tbar.Hello()\n}\n",
+				"bar/bar.go": "package bar\n\nimport \"example.com/foo/baz\"\n\nfunc Hello() {\n\tbaz.Hello()\n}\n",
+				"baz/baz.go": "package baz\n\nimport \"example.com/foo/bar\"\n\nfunc Hello() {\n\tbar.Hello()\n}\n",
+			},
+			expectErr: true,
+			expect: map[string][]lsp.Diagnostic{
+				"baz/baz.go": {
+					{
+						Severity: lsp.SeverityError,
+						Message:  "could not import example.com/foo/bar (import cycle not allowed: example.com/foo/bar)",
+						Range:    testRange(2, 7, 2, 28),
+					},
+				},
+			},
+		},
+		"missing local package": {
+			files: map[string]string{
+				"main.go":    "package main\n\nimport (\n\t\"app/baz\"\n\t\"example.com/foo\"\n)\n\nfunc main() {\n\tbaz.Hello()\n\tfoo.Hello()\n}\n",
+				"bar/bar.go": "package bar\n",
+			},
+			expectErr: true,
+			expect: map[string][]lsp.Diagnostic{
+				"main.go": {
+					{
+						Severity: lsp.SeverityError,
+						Message:  `could not import app/baz (package "app/baz" not found)`,
+						Range:    testRange(3, 1, 3, 10),
+					},
+				},
+			},
+		},
+		"duplicate declarations": {
+			files: map[string]string{
+				"main.go":  "package main\n\ntype T struct{}\n\nfunc (T) Foo() {}\n\nfunc main() {}\n",
+				"other.go": "package main\n\nvar main = 1\n\nfunc (*T) Foo() {}\n\nfunc (T) Bar() {}\n",
+			},
+			expectErr: true,
+			expect: map[string][]lsp.Diagnostic{
+				"other.go": {
+					{
+						Severity: lsp.SeverityError,
+						Message:  "main redeclared in this block (other declaration at main.go:7:6)",
+						Range:    testRange(2, 4, 2, 8),
+					},
+					{
+						Severity: lsp.SeverityError,
+						Message:  "Foo redeclared in this block (other declaration at main.go:5:10)",
+						Range:    testRange(4, 10, 4, 13),
+					},
+				},
+			},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			checker := NewChecker(importer)
+			if c.noImporter {
+				checker = NewChecker(nil)
+			}
+
+			got, err := checker.CheckFiles(c.files)
+			require.NoError(t, err)
+			require.Equal(t, c.expectErr, got.HasErrors)
+			require.Equal(t, c.expect, got.Markers)
+		})
+	}
+}
+
+func TestChecker_typeErrorToDiagnostic(t *testing.T) {
+	t.Run("error without file", func(t *testing.T) {
+		checker := NewChecker(nil).WithPackageIndex(NewPackageIndex([]string{"fmt"}, []string{"fmt"}))
+		got := checker.typeErrorToDiagnostic(nil, types.Error{
+			Fset: token.NewFileSet(),
+			Pos:  token.NoPos,
+			Msg:  "undefined: fmt",
+		})
+
+		require.Equal(t, diagnostic{
+			marker: lsp.Diagnostic{
+				Severity: lsp.SeverityError,
+				Message:  "undefined: fmt",
+			},
+		}, got)
+	})
+}
+
+func TestChecker_CodeActions(t *testing.T) {
+	importer := testExportData(t, "fmt", "os", "strings").Importer()
+	packages := NewPackageIndex(
+		[]string{"fmt", "strings", "rand", "rand"},
+		[]string{"fmt", "strings", "math/rand", "crypto/rand"},
+	)
+
+	cases := map[string]struct {
+		src       string
+		rng       lsp.Range
+		analyzers []string
+		expect    map[string]string
+	}{
+		"remove unused import": {
+			src: "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() {\n\tfmt.Println()\n}\n",
+			rng: testRange(4, 1, 4, 1),
+			expect: map[string]string{
+				"Remove unused import": "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println()\n}\n",
+			},
+		},
+		"remove single unused import": {
+			src: "package main\n\nimport \"os\"\n\nfunc main() {}\n",
+			rng: testRange(2, 7, 2, 11),
+			expect: map[string]string{
+				"Remove unused import": "package main\n\n\nfunc main() {}\n",
+			},
+		},
+		"add missing import": {
+			src: "package main\n\nimport (\n\t\"fmt\"\n)\n\nfunc main() {\n\tfmt.Println(strings.ToUpper(\"a\"))\n}\n",
+			rng: testRange(7, 13, 7, 20),
+			expect: map[string]string{
+				`Add import "strings"`: "package main\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n)\n\nfunc main() {\n\tfmt.Println(strings.ToUpper(\"a\"))\n}\n",
+			},
+		},
+		"add import without imports": {
+			src: "package main\n\nfunc main() {\n\tfmt.Println()\n}\n",
+			rng: testRange(3, 1, 3, 1),
+			expect: map[string]string{
+				`Add import "fmt"`: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println()\n}\n",
+			},
+		},
+		"add import after single import": {
+			src: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(rand.Int())\n}\n",
+			rng: testRange(5, 13, 5, 17),
+			expect: map[string]string{
+				`Add import "math/rand"`:   "package main\n\nimport \"fmt\"\nimport \"math/rand\"\n\nfunc main() {\n\tfmt.Println(rand.Int())\n}\n",
+				`Add import "crypto/rand"`: "package main\n\nimport \"fmt\"\nimport \"crypto/rand\"\n\nfunc main() {\n\tfmt.Println(rand.Int())\n}\n",
+			},
+		},
+		"unknown package": {
+			src:    "package main\n\nfunc main() {\n\tfoo.Bar()\n}\n",
+			rng:    testRange(3, 1, 3, 1),
+			expect: map[string]string{},
+		},
+		"out of range": {
+			src:    "package main\n\nimport \"os\"\n\nfunc main() {}\n",
+			rng:    testRange(4, 0, 4, 0),
+			expect: map[string]string{},
+		},
+		"vet suggested fix": {
+			src:       "package main\n\nfunc main() {\n\tx := 1\n\tx = x\n}\n",
+			rng:       testRange(4, 1, 4, 1),
+			analyzers: []string{"assign"},
+			expect: map[string]string{
+				"Remove self-assignment": "package main\n\nfunc main() {\n\tx := 1\n\t\n}\n",
+			},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			analyzers, err := LookupAnalyzers(c.analyzers...)
+			require.NoError(t, err)
+
+			checker := NewChecker(importer, analyzers...).WithPackageIndex(packages)
+			actions, err := checker.CodeActions(c.src, c.rng)
+			require.NoError(t, err)
+
+			got := make(map[string]string, len(actions))
+			for _, action := range actions {
+				require.Equal(t, lsp.QuickFix, action.Kind)
+				require.Len(t, action.Diagnostics, 1)
+				require.NotNil(t, action.Edit)
+				got[action.Title] = applyTextEdits(t, c.src, action.Edit.Changes[mainFileName])
+			}
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
+
+func TestChecker_FileCodeActions(t *testing.T) {
+	importer := testExportData(t, "fmt", "strings").Importer()
+	packages := NewPackageIndex([]string{"strings"}, []string{"strings"})
+	files := map[string]string{
+		"go.mod":     "module example.com/foo\n\ngo 1.26\n",
+		"main.go":    "package main\n\nimport \"example.com/foo/bar\"\n\nfunc main() {\n\tbar.Hello(strings.ToUpper(\"a\"))\n}\n",
+		"bar/bar.go": "package bar\n\nimport \"fmt\"\n\nfunc Hello(name string) {\n\tprintln(strings.ToLower(name))\n}\n",
+	}
+
+	cases := map[string]struct {
+		fileName string
+		rng      lsp.Range
+		expect   map[string]string
+	}{
+		"add missing import": {
+			fileName: "main.go",
+			rng:      testRange(5, 11, 5, 11),
+			expect: map[string]string{
+				`Add import "strings"`: "package main\n\nimport \"example.com/foo/bar\"\nimport \"strings\"\n\nfunc main() {\n\tbar.Hello(strings.ToUpper(\"a\"))\n}\n",
+			},
+		},
+		"fix subpackage": {
+			fileName: "bar/bar.go",
+			rng:      testRange(2, 0, 5, 20),
+			expect: map[string]string{
+				"Remove unused import": "package bar\n\n\nfunc Hello(name string) {\n\tprintln(strings.ToLower(name))\n}\n",
+				`Add import "strings"`: "package bar\n\nimport \"fmt\"\nimport \"strings\"\n\nfunc Hello(name string) {\n\tprintln(strings.ToLower(name))\n}\n",
+			},
+		},
+		"other file range": {
+			fileName: "main.go",
+			rng:      testRange(2, 0, 2, 0),
+			expect:   map[string]string{},
+		},
+	}
+
+	checker := NewChecker(importer).WithPackageIndex(packages)
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			actions, err := checker.FileCodeActions(files, c.fileName, c.rng)
+			require.NoError(t, err)
+
+			got := make(map[string]string, len(actions))
+			for _, action := range actions {
+				require.NotNil(t, action.Edit)
+				require.Len(t, action.Edit.Changes, 1)
+				got[action.Title] = applyTextEdits(t, files[c.fileName], action.Edit.Changes[lsp.DocumentURI(c.fileName)])
+			}
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
+
+func TestExportData(t *testing.T) {
+	data := ExportData{
+		"fmt":      []byte("foo"),
+		"net/http": []byte("bar"),
+	}
+
+	buff := new(bytes.Buffer)
+	require.NoError(t, WriteExportData(buff, data))
+
+	got, err := ReadExportData(buff)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+}
+
+func testRange(startLine, startCol, endLine, endCol uint32) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: startLine, Character: startCol},
+		End:   lsp.Position{Line: endLine, Character: endCol},
+	}
+}
+
+func testExportData(t *testing.T, pkgs ...string) ExportData {
+	t.Helper()
+	args := append([]string{"list", "-export", "-json=ImportPath,Export"}, pkgs...)
+	out, err := exec.Command("go", args...).Output()
+	require.NoError(t, err)
+
+	data := make(ExportData, len(pkgs))
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var pkg struct {
+			ImportPath string
+			Export     string
+		}
+
+		err := dec.Decode(&pkg)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+
+		data[pkg.ImportPath], err = os.ReadFile(pkg.Export)
+		require.NoError(t, err)
+	}
+
+	return data
+}
+
+// applyTextEdits applies non-overlapping edits sorted by position to a source.
+func applyTextEdits(t *testing.T, src string, edits []lsp.TextEdit) string {
+	t.Helper()
+	lines := strings.SplitAfter(src, "\n")
+	offset := func(pos lsp.Position) int {
+		require.Less(t, int(pos.Line), len(lines))
+		n := 0
+		for _, line := range lines[:pos.Line] {
+			n += len(line)
+		}
+		return n + int(pos.Character)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, edit := range edits {
+		start, end := offset(edit.Range.Start), offset(edit.Range.End)
+		sb.WriteString(src[last:start])
+		sb.WriteString(edit.NewText)
+		last = end
+	}
+	sb.WriteString(src[last:])
+	return sb.String()
+}
@@ -0,0 +1,100 @@
+package check
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// exportFileExt is extension of package export data file in archive.
+const exportFileExt = ".a"
+
+// ExportData is a collection of compiled packages export data by import path.
+//
+// Export data is used to type-check code without package sources, as Go standard
+// library sources aren't available in a browser.
+type ExportData map[string][]byte
+
+// Importer returns a new packages importer which reads packages from export data.
+func (d ExportData) Importer() types.Importer {
+	return importer.ForCompiler(token.NewFileSet(), "gc", func(importPath string) (io.ReadCloser, error) {
+		data, ok := d[importPath]
+		if !ok {
+			return nil, fmt.Errorf("package %q not found", importPath)
+		}
+
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// ReadExportData reads export data from a gzip-compressed tar archive.
+func ReadExportData(r io.Reader) (ExportData, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress export data: %w", err)
+	}
+
+	defer gr.Close()
+	data := make(ExportData)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read export data: %w", err)
+		}
+
+		importPath, ok := strings.CutSuffix(hdr.Name, exportFileExt)
+		if !ok || hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		buff := make([]byte, hdr.Size)
+		if _, err := io.ReadFull(tr, buff); err != nil {
+			return nil, fmt.Errorf("failed to read export data of %q: %w", importPath, err)
+		}
+
+		data[importPath] = buff
+	}
+
+	return data, nil
+}
+
+// WriteExportData writes export data as a gzip-compressed tar archive.
+func WriteExportData(w io.Writer, data ExportData) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, importPath := range slices.Sorted(maps.Keys(data)) {
+		contents := data[importPath]
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     importPath + exportFileExt,
+			Size:     int64(len(contents)),
+			Mode:     0644,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := tw.Write(contents); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
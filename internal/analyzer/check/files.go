@@ -0,0 +1,387 @@
+package check
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"go/types"
+	"iter"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"typefox.dev/lsp"
+
+	"github.com/x1unix/go-playground/internal/pkgindex/docutil"
+)
+
+const (
+	goModFileName = "go.mod"
+
+	// defaultModulePath is module name used by builder if no go.mod provided.
+	defaultModulePath = "app"
+
+	testFileSuffix = "_test.go"
+	testPkgSuffix  = "_test"
+)
+
+// FilesResult is multiple files check result.
+type FilesResult struct {
+	// HasErrors is error status
+	HasErrors bool `json:"hasErrors"`
+
+	// Markers is a list of problems by file path.
+	Markers map[string][]lsp.Diagnostic `json:"markers"`
+}
+
+// CheckFiles checks a set of Go files and returns check result.
+//
+// Files map has the same format as files in a shared snippet.
+func CheckFiles(files map[string]string) (*FilesResult, error) {
+	return NewChecker(nil).CheckFiles(files)
+}
+
+// CheckFiles checks a set of Go files which might contain multiple packages.
+//
+// Files are grouped into packages by directory. Subpackages are imported using module path from go.mod file.
+// Type errors are reported only if files don't contain syntax errors.
+func (c *Checker) CheckFiles(files map[string]string) (*FilesResult, error) {
+	diags, hasErrors, err := c.diagnoseFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	markers := make(map[string][]lsp.Diagnostic)
+	for _, d := range diags {
+		markers[d.fileName] = append(markers[d.fileName], d.marker)
+	}
+
+	return &FilesResult{HasErrors: hasErrors, Markers: markers}, nil
+}
+
+// sourcePackage is a set of files in a single directory which belong to the same package.
+type sourcePackage struct {
+	importPath string
+	dir        string
+	files      []*ast.File
+
+	checked bool
+	pkg     *types.Package
+}
+
+// projectCheck holds state of multiple files check.
+type projectCheck struct {
+	checker  *Checker
+	fset     *token.FileSet
+	modPath  string
+	packages map[string]*sourcePackage
+
+	// duplicates is a set of duplicate declarations positions already reported by declaration check.
+	duplicates map[token.Pos]struct{}
+
+	diags     []diagnostic
+	hasErrors bool
+}
+
+func (c *Checker) diagnoseFiles(files map[string]string) ([]diagnostic, bool, error) {
+	p := &projectCheck{
+		checker:    c,
+		fset:       token.NewFileSet(),
+		modPath:    modulePath(files[goModFileName]),
+		packages:   make(map[string]*sourcePackage),
+		duplicates: make(map[token.Pos]struct{}),
+	}
+
+	if err := p.check(files); err != nil {
+		return nil, false, err
+	}
+
+	toUTF16Ranges(p.diags, files)
+	return p.diags, p.hasErrors, nil
+}
+
+// check parses and checks all Go files and collects diagnostics.
+func (p *projectCheck) check(files map[string]string) error {
+	parsed, err := p.parseFiles(files)
+	if err != nil || p.hasErrors {
+		return err
+	}
+
+	p.groupPackages(parsed)
+	importPaths := slices.Sorted(maps.Keys(p.packages))
+	for _, importPath := range importPaths {
+		p.checkDeclarations(p.packages[importPath])
+	}
+
+	if p.checker.importer == nil {
+		return nil
+	}
+
+	for _, importPath := range importPaths {
+		if err := p.checkPackage(p.packages[importPath]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseFiles parses Go files in sorted order and reports syntax errors.
+func (p *projectCheck) parseFiles(files map[string]string) ([]*ast.File, error) {
+	parsed := make([]*ast.File, 0, len(files))
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if path.Ext(name) != ".go" {
+			continue
+		}
+
+		f, err := parser.ParseFile(p.fset, name, files[name], parser.DeclarationErrors)
+		if err == nil {
+			parsed = append(parsed, f)
+			continue
+		}
+
+		errList, ok := errors.AsType[scanner.ErrorList](err)
+		if !ok {
+			return nil, err
+		}
+
+		p.hasErrors = true
+		for _, marker := range errorsListToMarkers(errList) {
+			p.diags = append(p.diags, diagnostic{fileName: name, marker: marker})
+		}
+	}
+
+	return parsed, nil
+}
+
+// groupPackages groups files by directory and reports files with mismatched package clause.
+//
+// External test files are grouped into a separate package.
+func (p *projectCheck) groupPackages(files []*ast.File) {
+	for _, f := range files {
+		name := p.fset.File(f.Pos()).Name()
+		dir := path.Dir(name)
+		importPath := p.modPath
+		if dir != "." {
+			importPath = path.Join(p.modPath, dir)
+		}
+
+		if strings.HasSuffix(name, testFileSuffix) && strings.HasSuffix(f.Name.Name, testPkgSuffix) {
+			importPath += testPkgSuffix
+		}
+
+		pkg, ok := p.packages[importPath]
+		if !ok {
+			p.packages[importPath] = &sourcePackage{
+				importPath: importPath,
+				dir:        dir,
+				files:      []*ast.File{f},
+			}
+			continue
+		}
+
+		first := pkg.files[0]
+		if first.Name.Name == f.Name.Name {
+			pkg.files = append(pkg.files, f)
+			continue
+		}
+
+		p.hasErrors = true
+		p.diags = append(p.diags, diagnostic{
+			fileName: name,
+			marker: lsp.Diagnostic{
+				Severity: lsp.SeverityError,
+				Range:    posRange(p.fset, f.Name.Pos(), f.Name.End()),
+				Message: fmt.Sprintf(
+					"found packages %s (%s) and %s (%s) in %s",
+					first.Name.Name, path.Base(p.fset.File(first.Pos()).Name()),
+					f.Name.Name, path.Base(name), dir,
+				),
+			},
+		})
+	}
+}
+
+// checkDeclarations reports package-level declarations which are declared in multiple files.
+//
+// Duplicates in a single file are reported by parser.
+func (p *projectCheck) checkDeclarations(pkg *sourcePackage) {
+	declared := make(map[string]*ast.Ident)
+	for _, f := range pkg.files {
+		for key, ident := range topLevelDecls(f) {
+			other, ok := declared[key]
+			if !ok {
+				declared[key] = ident
+				continue
+			}
+
+			otherPos := p.fset.Position(other.Pos())
+			p.hasErrors = true
+			p.duplicates[ident.Pos()] = struct{}{}
+			p.diags = append(p.diags, diagnostic{
+				fileName: p.fset.File(f.Pos()).Name(),
+				marker: lsp.Diagnostic{
+					Severity: lsp.SeverityError,
+					Range:    posRange(p.fset, ident.Pos(), ident.End()),
+					Message: fmt.Sprintf(
+						"%s redeclared in this block (other declaration at %s:%d:%d)",
+						ident.Name, otherPos.Filename, otherPos.Line, otherPos.Column,
+					),
+				},
+			})
+		}
+	}
+}
+
+// checkPackage type-checks a package and runs vet analyzers if package has no type errors.
+//
+// Local packages imported by a package are checked first.
+func (p *projectCheck) checkPackage(pkg *sourcePackage) error {
+	if pkg.checked {
+		return nil
+	}
+
+	// Set before check to break import cycles.
+	pkg.checked = true
+	files := make(map[string]*ast.File, len(pkg.files))
+	for _, f := range pkg.files {
+		files[p.fset.File(f.Pos()).Name()] = f
+	}
+
+	typesPkg, info, typeErrs := p.checker.typeCheck(p.fset, pkg.files, p.modPath, importerFunc(p.importPackage))
+	pkg.pkg = typesPkg
+
+	for i, typeErr := range typeErrs {
+		if p.isReportedDuplicate(typeErrs, i) {
+			continue
+		}
+
+		f := files[typeErr.Fset.Position(typeErr.Pos).Filename]
+		p.diags = append(p.diags, p.checker.typeErrorToDiagnostic(f, typeErr))
+	}
+
+	if len(typeErrs) > 0 {
+		p.hasErrors = true
+		return nil
+	}
+
+	if len(p.checker.analyzers) == 0 {
+		return nil
+	}
+
+	diags, err := newVetPass(p.fset, pkg.files, typesPkg, info, typesSizes).run(p.checker.analyzers)
+	if err != nil {
+		return err
+	}
+
+	p.diags = append(p.diags, diags...)
+	return nil
+}
+
+// isReportedDuplicate returns whether type error is a duplicate declaration error
+// already reported by checkDeclarations.
+//
+// Type checker reports details as separate errors prefixed with tab.
+func (p *projectCheck) isReportedDuplicate(typeErrs []types.Error, i int) bool {
+	for ; i >= 0; i-- {
+		typeErr := typeErrs[i]
+		if !strings.HasPrefix(typeErr.Msg, "\t") {
+			_, ok := p.duplicates[typeErr.Pos]
+			return ok
+		}
+	}
+
+	return false
+}
+
+// importPackage imports local package from a project or falls back to checker's importer.
+func (p *projectCheck) importPackage(importPath string) (*types.Package, error) {
+	pkg, ok := p.packages[importPath]
+	if !ok || strings.HasSuffix(importPath, testPkgSuffix) {
+		return p.checker.importer.Import(importPath)
+	}
+
+	if pkg.checked && pkg.pkg == nil {
+		return nil, fmt.Errorf("import cycle not allowed: %s", importPath)
+	}
+
+	if err := p.checkPackage(pkg); err != nil {
+		return nil, err
+	}
+
+	return pkg.pkg, nil
+}
+
+type importerFunc func(importPath string) (*types.Package, error)
+
+func (fn importerFunc) Import(importPath string) (*types.Package, error) {
+	return fn(importPath)
+}
+
+// topLevelDecls returns identifiers of all package-level declarations in a file by declaration name.
+//
+// Methods are prefixed with receiver type name.
+// Blank identifiers and init functions are skipped as they can be declared multiple times.
+func topLevelDecls(f *ast.File) iter.Seq2[string, *ast.Ident] {
+	return func(yield func(string, *ast.Ident) bool) {
+		emit := func(key string, ident *ast.Ident) bool {
+			return ident.Name == "_" || yield(key, ident)
+		}
+
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				switch {
+				case decl.Recv != nil:
+					if !emit(docutil.ReceiverTypeName(decl.Recv)+"."+decl.Name.Name, decl.Name) {
+						return
+					}
+				case decl.Name.Name != "init":
+					if !emit(decl.Name.Name, decl.Name) {
+						return
+					}
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if !emit(spec.Name.Name, spec.Name) {
+							return
+						}
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							if !emit(name.Name, name) {
+								return
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// modulePath returns module path from go.mod file contents.
+func modulePath(goMod string) string {
+	s := bufio.NewScanner(strings.NewReader(goMod))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || fields[0] != "module" {
+			continue
+		}
+
+		if modPath, err := strconv.Unquote(fields[1]); err == nil {
+			return modPath
+		}
+
+		return fields[1]
+	}
+
+	return defaultModulePath
+}
@@ -1,8 +1,13 @@
-// Package check checks provided Go code and reports syntax errors
+// Package check checks provided Go code and reports syntax and type errors
 package check
 
 import (
+	"go/ast"
 	"go/scanner"
+	"go/token"
+	"go/types"
+	"strings"
+	"unicode/utf16"
 
 	"typefox.dev/lsp"
 )
@@ -28,6 +33,158 @@ func errorsListToMarkers(errList scanner.ErrorList) []lsp.Diagnostic {
 	return markers
 }
 
+// typeErrorToMarker returns diagnostic for a type error in a file.
+//
+// Zero range is used if error doesn't belong to any file.
+func typeErrorToMarker(f *ast.File, err types.Error) lsp.Diagnostic {
+	marker := lsp.Diagnostic{
+		Severity: lsp.SeverityError,
+		Message:  err.Msg,
+	}
+
+	if f != nil {
+		marker.Range = nodeRange(err.Fset, f, err.Pos, isUndefinedError(err))
+	}
+
+	// Soft errors are unused variables and imports.
+	if err.Soft {
+		marker.Tags = []lsp.DiagnosticTag{lsp.Unnecessary}
+	}
+
+	return marker
+}
+
+func isUndefinedError(err types.Error) bool {
+	return strings.HasPrefix(err.Msg, "undefined: ")
+}
+
+// nodeRange returns range of the outermost expression which starts at a passed position.
+//
+// Type checker reports only start position of an error.
+// If identOnly is true, range of identifier at a position is returned.
+func nodeRange(fset *token.FileSet, f *ast.File, pos token.Pos, identOnly bool) lsp.Range {
+	end := token.NoPos
+	ast.Inspect(f, func(n ast.Node) bool {
+		if end != token.NoPos || n == nil || n.Pos() > pos || n.End() <= pos {
+			return false
+		}
+
+		if _, ok := n.(ast.Expr); !ok || n.Pos() != pos {
+			return true
+		}
+
+		if _, isIdent := n.(*ast.Ident); isIdent || !identOnly {
+			end = n.End()
+			return false
+		}
+
+		return true
+	})
+
+	start := fset.Position(pos)
+	if end == token.NoPos {
+		return positionsToRange(start, start)
+	}
+
+	return positionsToRange(start, fset.Position(end))
+}
+
+// posRange returns range between two positions.
+//
+// End position is optional.
+func posRange(fset *token.FileSet, start, end token.Pos) lsp.Range {
+	startPos := fset.Position(start)
+	if !end.IsValid() {
+		return positionsToRange(startPos, startPos)
+	}
+
+	return positionsToRange(startPos, fset.Position(end))
+}
+
+// positionsToRange returns range between two positions.
+//
+// Columns are byte offsets and have to be converted to UTF-16 using toUTF16Ranges.
+func positionsToRange(start, end token.Position) lsp.Range {
+	if start == end {
+		end.Column++
+	}
+
+	return lsp.Range{
+		Start: lsp.Position{
+			Line:      normalizeLSPPosition(start.Line - 1),
+			Character: normalizeLSPPosition(start.Column - 1),
+		},
+		End: lsp.Position{
+			Line:      normalizeLSPPosition(end.Line - 1),
+			Character: normalizeLSPPosition(end.Column - 1),
+		},
+	}
+}
+
+// toUTF16Ranges converts byte columns of diagnostics and fixes to UTF-16 code units used by LSP.
+func toUTF16Ranges(diags []diagnostic, files map[string]string) {
+	for i := range diags {
+		d := &diags[i]
+		d.marker.Range = toUTF16Range(files[d.fileName], d.marker.Range)
+		for _, fix := range d.fixes {
+			for j := range fix.Diagnostics {
+				fix.Diagnostics[j].Range = toUTF16Range(files[d.fileName], fix.Diagnostics[j].Range)
+			}
+
+			if fix.Edit == nil {
+				continue
+			}
+
+			for uri, edits := range fix.Edit.Changes {
+				for j := range edits {
+					edits[j].Range = toUTF16Range(files[string(uri)], edits[j].Range)
+				}
+			}
+		}
+	}
+}
+
+func toUTF16Range(src string, rng lsp.Range) lsp.Range {
+	return lsp.Range{
+		Start: toUTF16Position(src, rng.Start),
+		End:   toUTF16Position(src, rng.End),
+	}
+}
+
+// toUTF16Position converts byte column of a position to UTF-16 code units.
+func toUTF16Position(src string, pos lsp.Position) lsp.Position {
+	line, ok := sourceLine(src, int(pos.Line))
+	if !ok {
+		return pos
+	}
+
+	col := min(int(pos.Character), len(line))
+	var n uint32
+	for _, r := range line[:col] {
+		n += uint32(utf16.RuneLen(r))
+	}
+
+	// Keep columns past the end of line, e.g. end of range which points to line break.
+	pos.Character = n + uint32(int(pos.Character)-col)
+	return pos
+}
+
+// sourceLine returns line of a source by 0-based index without a line break.
+func sourceLine(src string, index int) (string, bool) {
+	for i := 0; ; i++ {
+		line, rest, found := strings.Cut(src, "\n")
+		if i == index {
+			return line, true
+		}
+
+		if !found {
+			return "", false
+		}
+
+		src = rest
+	}
+}
+
 func normalizeLSPPosition(value int) uint32 {
 	if value < 0 {
 		return 0
@@ -0,0 +1,273 @@
+package check
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/appends"
+	"golang.org/x/tools/go/analysis/passes/assign"
+	"golang.org/x/tools/go/analysis/passes/atomic"
+	"golang.org/x/tools/go/analysis/passes/bools"
+	"golang.org/x/tools/go/analysis/passes/composite"
+	"golang.org/x/tools/go/analysis/passes/copylock"
+	"golang.org/x/tools/go/analysis/passes/defers"
+	"golang.org/x/tools/go/analysis/passes/errorsas"
+	"golang.org/x/tools/go/analysis/passes/hostport"
+	"golang.org/x/tools/go/analysis/passes/httpresponse"
+	"golang.org/x/tools/go/analysis/passes/ifaceassert"
+	"golang.org/x/tools/go/analysis/passes/loopclosure"
+	"golang.org/x/tools/go/analysis/passes/lostcancel"
+	"golang.org/x/tools/go/analysis/passes/nilfunc"
+	"golang.org/x/tools/go/analysis/passes/printf"
+	"golang.org/x/tools/go/analysis/passes/shadow"
+	"golang.org/x/tools/go/analysis/passes/shift"
+	"golang.org/x/tools/go/analysis/passes/sigchanyzer"
+	"golang.org/x/tools/go/analysis/passes/slog"
+	"golang.org/x/tools/go/analysis/passes/stdmethods"
+	"golang.org/x/tools/go/analysis/passes/stringintconv"
+	"golang.org/x/tools/go/analysis/passes/structtag"
+	"golang.org/x/tools/go/analysis/passes/testinggoroutine"
+	"golang.org/x/tools/go/analysis/passes/tests"
+	"golang.org/x/tools/go/analysis/passes/timeformat"
+	"golang.org/x/tools/go/analysis/passes/unmarshal"
+	"golang.org/x/tools/go/analysis/passes/unreachable"
+	"golang.org/x/tools/go/analysis/passes/unsafeptr"
+	"golang.org/x/tools/go/analysis/passes/unusedresult"
+	"golang.org/x/tools/go/analysis/passes/waitgroup"
+	"typefox.dev/lsp"
+)
+
+// Analyzers is a list of all supported vet analyzers.
+var Analyzers = []*analysis.Analyzer{
+	appends.Analyzer,
+	assign.Analyzer,
+	atomic.Analyzer,
+	bools.Analyzer,
+	composite.Analyzer,
+	copylock.Analyzer,
+	defers.Analyzer,
+	errorsas.Analyzer,
+	hostport.Analyzer,
+	httpresponse.Analyzer,
+	ifaceassert.Analyzer,
+	loopclosure.Analyzer,
+	lostcancel.Analyzer,
+	nilfunc.Analyzer,
+	printf.Analyzer,
+	shadow.Analyzer,
+	shift.Analyzer,
+	sigchanyzer.Analyzer,
+	slog.Analyzer,
+	stdmethods.Analyzer,
+	stringintconv.Analyzer,
+	structtag.Analyzer,
+	testinggoroutine.Analyzer,
+	tests.Analyzer,
+	timeformat.Analyzer,
+	unmarshal.Analyzer,
+	unreachable.Analyzer,
+	unsafeptr.Analyzer,
+	unusedresult.Analyzer,
+	waitgroup.Analyzer,
+}
+
+// DefaultAnalyzers returns list of analyzers enabled by default.
+//
+// Returns the same set of analyzers as "go vet" command.
+func DefaultAnalyzers() []*analysis.Analyzer {
+	result := make([]*analysis.Analyzer, 0, len(Analyzers))
+	for _, a := range Analyzers {
+		// Shadow analyzer is too noisy and isn't a part of "go vet".
+		if a != shadow.Analyzer {
+			result = append(result, a)
+		}
+	}
+
+	return result
+}
+
+// LookupAnalyzers returns analyzers by name.
+func LookupAnalyzers(names ...string) ([]*analysis.Analyzer, error) {
+	result := make([]*analysis.Analyzer, 0, len(names))
+	for _, name := range names {
+		a := findAnalyzer(name)
+		if a == nil {
+			return nil, fmt.Errorf("unknown analyzer %q", name)
+		}
+
+		result = append(result, a)
+	}
+
+	return result, nil
+}
+
+func findAnalyzer(name string) *analysis.Analyzer {
+	for _, a := range Analyzers {
+		if a.Name == name {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// vetPass runs analyzers over a single type-checked package.
+type vetPass struct {
+	fset    *token.FileSet
+	files   []*ast.File
+	pkg     *types.Package
+	info    *types.Info
+	sizes   types.Sizes
+	results map[*analysis.Analyzer]any
+	facts   map[factKey]analysis.Fact
+}
+
+type factKey struct {
+	obj types.Object
+	pkg *types.Package
+	typ reflect.Type
+}
+
+func newVetPass(fset *token.FileSet, files []*ast.File, pkg *types.Package, info *types.Info, sizes types.Sizes) *vetPass {
+	return &vetPass{
+		fset:    fset,
+		files:   files,
+		pkg:     pkg,
+		info:    info,
+		sizes:   sizes,
+		results: make(map[*analysis.Analyzer]any),
+		facts:   make(map[factKey]analysis.Fact),
+	}
+}
+
+// run runs analyzers with their dependencies and returns reported diagnostics.
+func (p *vetPass) run(analyzers []*analysis.Analyzer) ([]diagnostic, error) {
+	var results []diagnostic
+	for _, a := range analyzers {
+		diags, err := p.runAnalyzer(a)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range diags {
+			results = append(results, vetDiagnosticToDiagnostic(p.fset, a, d))
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i].marker.Range.Start, results[j].marker.Range.Start
+		return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+	})
+	return results, nil
+}
+
+func (p *vetPass) runAnalyzer(a *analysis.Analyzer) ([]analysis.Diagnostic, error) {
+	for _, req := range a.Requires {
+		if _, ok := p.results[req]; ok {
+			continue
+		}
+
+		if _, err := p.runAnalyzer(req); err != nil {
+			return nil, err
+		}
+	}
+
+	var diags []analysis.Diagnostic
+	pass := &analysis.Pass{
+		Analyzer:   a,
+		Fset:       p.fset,
+		Files:      p.files,
+		Pkg:        p.pkg,
+		TypesInfo:  p.info,
+		TypesSizes: p.sizes,
+		ResultOf:   p.results,
+		Report: func(d analysis.Diagnostic) {
+			diags = append(diags, d)
+		},
+		ReadFile: func(filename string) ([]byte, error) {
+			return nil, fmt.Errorf("can't read file %q", filename)
+		},
+		ImportObjectFact: func(obj types.Object, fact analysis.Fact) bool {
+			return p.importFact(factKey{obj: obj, typ: reflect.TypeOf(fact)}, fact)
+		},
+		ImportPackageFact: func(pkg *types.Package, fact analysis.Fact) bool {
+			return p.importFact(factKey{pkg: pkg, typ: reflect.TypeOf(fact)}, fact)
+		},
+		ExportObjectFact: func(obj types.Object, fact analysis.Fact) {
+			p.facts[factKey{obj: obj, typ: reflect.TypeOf(fact)}] = fact
+		},
+		ExportPackageFact: func(fact analysis.Fact) {
+			p.facts[factKey{pkg: p.pkg, typ: reflect.TypeOf(fact)}] = fact
+		},
+		AllPackageFacts: func() []analysis.PackageFact {
+			var facts []analysis.PackageFact
+			for k, fact := range p.facts {
+				if k.pkg != nil {
+					facts = append(facts, analysis.PackageFact{Package: k.pkg, Fact: fact})
+				}
+			}
+			return facts
+		},
+		AllObjectFacts: func() []analysis.ObjectFact {
+			var facts []analysis.ObjectFact
+			for k, fact := range p.facts {
+				if k.obj != nil {
+					facts = append(facts, analysis.ObjectFact{Object: k.obj, Fact: fact})
+				}
+			}
+			return facts
+		},
+	}
+
+	result, err := a.Run(pass)
+	if err != nil {
+		return nil, fmt.Errorf("analyzer %q failed: %w", a.Name, err)
+	}
+
+	p.results[a] = result
+	return diags, nil
+}
+
+func (p *vetPass) importFact(key factKey, dst analysis.Fact) bool {
+	fact, ok := p.facts[key]
+	if !ok {
+		return false
+	}
+
+	reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(fact).Elem())
+	return true
+}
+
+func vetDiagnosticToDiagnostic(fset *token.FileSet, a *analysis.Analyzer, d analysis.Diagnostic) diagnostic {
+	marker := lsp.Diagnostic{
+		Severity: lsp.SeverityWarning,
+		Source:   a.Name,
+		Message:  d.Message,
+		Range:    posRange(fset, d.Pos, d.End),
+	}
+
+	fixes := make([]lsp.CodeAction, 0, len(d.SuggestedFixes))
+	for _, fix := range d.SuggestedFixes {
+		changes := make(map[lsp.DocumentURI][]lsp.TextEdit)
+		for _, edit := range fix.TextEdits {
+			uri := lsp.DocumentURI(fset.Position(edit.Pos).Filename)
+			changes[uri] = append(changes[uri], lsp.TextEdit{
+				Range:   editRange(fset, edit.Pos, edit.End),
+				NewText: string(edit.NewText),
+			})
+		}
+
+		fixes = append(fixes, newQuickFix(fix.Message, marker, changes))
+	}
+
+	return diagnostic{
+		fileName: fset.Position(d.Pos).Filename,
+		marker:   marker,
+		fixes:    fixes,
+	}
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/tevino/abool"
 	"go.uber.org/zap"
+
+	"github.com/x1unix/go-playground/internal/metrics"
 )
 
 type Cleaner interface {
@@ -21,6 +23,7 @@ type CleanupDispatchService struct {
 	logger    *zap.Logger
 	interval  time.Duration
 	cleaners  []Cleaner
+	metrics   *metrics.Metrics
 }
 
 func NewCleanupDispatchService(logger *zap.Logger, interval time.Duration, cleaners ...Cleaner) *CleanupDispatchService {
@@ -31,6 +34,12 @@ func NewCleanupDispatchService(logger *zap.Logger, interval time.Duration, clean
 	}
 }
 
+// WithMetrics enables cleanup job duration metrics.
+func (c *CleanupDispatchService) WithMetrics(m *metrics.Metrics) *CleanupDispatchService {
+	c.metrics = m
+	return c
+}
+
 func (c *CleanupDispatchService) Start(ctx context.Context) {
 	t := time.NewTicker(c.interval)
 	defer t.Stop()
@@ -65,7 +74,10 @@ func (c *CleanupDispatchService) dispatchCleanup(ctx context.Context) {
 		wg.Add(1)
 		go func(cleaner Cleaner) {
 			defer wg.Done()
-			if err := cleaner.Clean(jobCtx); err != nil {
+			cleanerStartTime := time.Now()
+			err := cleaner.Clean(jobCtx)
+			c.metrics.ObserveCleanup(cleaner.CleanJobName(), time.Since(cleanerStartTime), err)
+			if err != nil {
 				if errors.Is(err, context.Canceled) {
 					return
 				}
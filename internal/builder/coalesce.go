@@ -0,0 +1,105 @@
+package builder
+
+import (
+	"context"
+	"sync"
+
+	"github.com/x1unix/go-playground/internal/builder/storage"
+)
+
+type buildFunc = func(ctx context.Context) (*Result, error)
+
+type buildCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	result *Result
+	err    error
+}
+
+// buildGroup coalesces concurrent builds of the same artifact into a single compiler run.
+//
+// Unlike singleflight.Group, each caller waits for a result using its own context.
+// Shared build is cancelled only when all callers are gone.
+type buildGroup struct {
+	lock  sync.Mutex
+	calls map[storage.ArtifactID]*buildCall
+}
+
+func newBuildGroup() *buildGroup {
+	return &buildGroup{
+		calls: make(map[storage.ArtifactID]*buildCall),
+	}
+}
+
+// Do runs a build function or waits for a result of already running build with the same artifact ID.
+//
+// Returned flag reports whether result was shared with another caller.
+func (g *buildGroup) Do(ctx context.Context, id storage.ArtifactID, fn buildFunc) (*Result, bool, error) {
+	g.lock.Lock()
+	call, shared := g.calls[id]
+	if !shared {
+		call = g.start(ctx, id, fn)
+	}
+	call.waiters++
+	g.lock.Unlock()
+
+	select {
+	case <-call.done:
+		if call.result == nil {
+			return nil, shared, call.err
+		}
+
+		// Copy result as callers might modify it.
+		result := *call.result
+		return &result, shared, call.err
+	case <-ctx.Done():
+		g.lock.Lock()
+		call.waiters--
+		if call.waiters == 0 {
+			// Abandoned build can't be joined anymore.
+			call.cancel()
+			g.forget(id, call)
+		}
+		g.lock.Unlock()
+		return nil, shared, ctx.Err()
+	}
+}
+
+func (g *buildGroup) start(ctx context.Context, id storage.ArtifactID, fn buildFunc) *buildCall {
+	// Build should outlive the caller which started it, but keep its deadline.
+	var (
+		buildCtx context.Context
+		cancel   context.CancelFunc
+	)
+	if deadline, ok := ctx.Deadline(); ok {
+		buildCtx, cancel = context.WithDeadline(context.WithoutCancel(ctx), deadline)
+	} else {
+		buildCtx, cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+
+	call := &buildCall{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	g.calls[id] = call
+
+	go func() {
+		defer cancel()
+		call.result, call.err = fn(buildCtx)
+
+		g.lock.Lock()
+		g.forget(id, call)
+		g.lock.Unlock()
+		close(call.done)
+	}()
+
+	return call
+}
+
+func (g *buildGroup) forget(id storage.ArtifactID, call *buildCall) {
+	if g.calls[id] == call {
+		delete(g.calls, id)
+	}
+}
@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildGroup_Do(t *testing.T) {
+	t.Run("share result between concurrent callers", func(t *testing.T) {
+		g := newBuildGroup()
+		release := make(chan struct{})
+		started := make(chan struct{})
+		var calls atomic.Int32
+
+		fn := func(ctx context.Context) (*Result, error) {
+			calls.Add(1)
+			close(started)
+			<-release
+			return &Result{FileName: "foo.wasm"}, nil
+		}
+
+		var (
+			wg      sync.WaitGroup
+			results [2]*Result
+			shared  [2]bool
+		)
+		for i := range results {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if i > 0 {
+					<-started
+				}
+
+				var err error
+				results[i], shared[i], err = g.Do(context.Background(), "foo", fn)
+				require.NoError(t, err)
+			}(i)
+		}
+
+		<-started
+		require.Eventually(t, func() bool {
+			g.lock.Lock()
+			defer g.lock.Unlock()
+			return g.calls["foo"] != nil && g.calls["foo"].waiters == 2
+		}, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, int32(1), calls.Load())
+		require.Equal(t, [2]bool{false, true}, shared)
+		require.Equal(t, results[0], results[1])
+		require.NotSame(t, results[0], results[1])
+		require.Empty(t, g.calls)
+	})
+
+	t.Run("waiter cancellation doesn't affect other callers", func(t *testing.T) {
+		g := newBuildGroup()
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		fn := func(ctx context.Context) (*Result, error) {
+			close(started)
+			select {
+			case <-release:
+				return &Result{FileName: "foo.wasm"}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		firstErr := make(chan error)
+		go func() {
+			_, _, err := g.Do(ctx, "foo", fn)
+			firstErr <- err
+		}()
+
+		<-started
+		secondResult := make(chan *Result)
+		go func() {
+			res, _, err := g.Do(context.Background(), "foo", fn)
+			require.NoError(t, err)
+			secondResult <- res
+		}()
+
+		require.Eventually(t, func() bool {
+			g.lock.Lock()
+			defer g.lock.Unlock()
+			return g.calls["foo"].waiters == 2
+		}, time.Second, time.Millisecond)
+
+		cancel()
+		require.ErrorIs(t, <-firstErr, context.Canceled)
+
+		close(release)
+		require.Equal(t, &Result{FileName: "foo.wasm"}, <-secondResult)
+	})
+
+	t.Run("cancel build when all callers are gone", func(t *testing.T) {
+		g := newBuildGroup()
+		buildErr := make(chan error, 1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		fn := func(buildCtx context.Context) (*Result, error) {
+			cancel()
+			<-buildCtx.Done()
+			buildErr <- buildCtx.Err()
+			return nil, buildCtx.Err()
+		}
+
+		_, _, err := g.Do(ctx, "foo", fn)
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, <-buildErr, context.Canceled)
+	})
+}
@@ -6,12 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"syscall"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/x1unix/go-playground/internal/builder/storage"
+	"github.com/x1unix/go-playground/internal/metrics"
 	"github.com/x1unix/go-playground/pkg/util/osutil"
 )
 
@@ -21,8 +23,6 @@ const defaultGoModName = "app"
 // predefinedBuildVars is list of environment vars which contain build values
 var predefinedBuildVars = osutil.EnvironmentVariables{
 	"CGO_ENABLED": "0",
-	"GOOS":        "js",
-	"GOARCH":      "wasm",
 	"HOME":        os.Getenv("HOME"),
 }
 
@@ -34,6 +34,12 @@ type Result struct {
 	// CompilerOutput is stderr output produced by the compiler.
 	CompilerOutput string
 
+	// Diagnostics is a list of compiler annotations parsed from compiler output.
+	Diagnostics []Diagnostic
+
+	// Target is build target.
+	Target BuildTarget
+
 	// IsTest indicates whether binary is a test file
 	IsTest bool
 
@@ -44,6 +50,20 @@ type Result struct {
 	HasFuzz bool
 }
 
+// ArtifactID returns artifact ID which can be used to fetch built program.
+func (r Result) ArtifactID() storage.ArtifactID {
+	return storage.ArtifactID(strings.TrimSuffix(r.FileName, "."+storage.ExtWasm))
+}
+
+// setCompilerOutput sets compiler output and diagnostics parsed from it.
+//
+// Output is expected to contain only workspace-relative paths, so cached and fresh builds
+// produce the same result.
+func (r *Result) setCompilerOutput(output string) {
+	r.CompilerOutput = output
+	r.Diagnostics = ParseDiagnostics(output, "", SeverityInfo)
+}
+
 // BuildEnvironmentConfig is BuildService environment configuration.
 type BuildEnvironmentConfig struct {
 	// IncludedEnvironmentVariables is a list included environment variables for build.
@@ -51,6 +71,14 @@ type BuildEnvironmentConfig struct {
 
 	// KeepGoModCache disables Go modules cache cleanup.
 	KeepGoModCache bool
+
+	// Pool is parallel builds limit configuration.
+	Pool PoolConfig
+
+	// Toolchains is a list of available Go toolchains.
+	//
+	// The first toolchain is used by default. Host toolchain is used if list is empty.
+	Toolchains Toolchains
 }
 
 // BuildService is WASM build service
@@ -59,24 +87,59 @@ type BuildService struct {
 	config    BuildEnvironmentConfig
 	storage   storage.StoreProvider
 	cmdRunner CommandRunner
+	builds    *buildGroup
+	pool      *BuildPool
+	metrics   *metrics.Metrics
 }
 
 // NewBuildService is BuildService constructor
+//
+// Number of parallel builds is not limited if pool config is empty.
 func NewBuildService(log *zap.Logger, cfg BuildEnvironmentConfig, store storage.StoreProvider) BuildService {
+	var pool *BuildPool
+	if cfg.Pool.MaxWorkers > 0 {
+		pool = NewBuildPool(cfg.Pool)
+	}
+
+	if len(cfg.Toolchains) == 0 {
+		cfg.Toolchains = Toolchains{HostToolchain()}
+	}
+
 	return BuildService{
 		log:       log.Named("builder"),
 		config:    cfg,
 		storage:   store,
 		cmdRunner: OSCommandRunner{},
+		builds:    newBuildGroup(),
+		pool:      pool,
 	}
 }
 
-func (s BuildService) getEnvironmentVariables() []string {
+// WithMetrics returns a copy of build service which reports build metrics.
+func (s BuildService) WithMetrics(m *metrics.Metrics) BuildService {
+	s.metrics = m
+	return s
+}
+
+// Pool returns a pool which limits number of parallel builds.
+//
+// Returns nil if number of parallel builds isn't limited.
+func (s BuildService) Pool() *BuildPool {
+	return s.pool
+}
+
+// Toolchains returns list of available Go toolchains.
+func (s BuildService) Toolchains() Toolchains {
+	return s.config.Toolchains
+}
+
+func (s BuildService) getEnvironmentVariables(targetVars osutil.EnvironmentVariables) []string {
+	buildVars := predefinedBuildVars.Concat(targetVars)
 	if len(s.config.IncludedEnvironmentVariables) == 0 {
-		return predefinedBuildVars.Join()
+		return buildVars.Join()
 	}
 
-	return s.config.IncludedEnvironmentVariables.Concat(predefinedBuildVars).Join()
+	return s.config.IncludedEnvironmentVariables.Concat(buildVars).Join()
 }
 
 // GetArtifact returns artifact by id
@@ -90,6 +153,16 @@ func (s BuildService) GetArtifact(id storage.ArtifactID) (storage.ReadCloseSizer
 
 // Build compiles Go source to WASM and returns result
 func (s BuildService) Build(ctx context.Context, files map[string][]byte, opts BuildOptions) (*Result, error) {
+	toolchain, err := s.config.Toolchains.Find(opts.GoVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := ParseBuildTarget(string(opts.Target))
+	if err != nil {
+		return nil, err
+	}
+
 	projInfo, err := detectProjectType(files)
 	if err != nil {
 		return nil, err
@@ -97,16 +170,21 @@ func (s BuildService) Build(ctx context.Context, files map[string][]byte, opts B
 
 	// Go module is required to build project
 	if _, ok := files["go.mod"]; !ok {
-		files["go.mod"] = generateGoMod(defaultGoModName)
+		files["go.mod"] = generateGoMod(defaultGoModName, toolchain.goModVersion())
 	}
 
-	aid, err := storage.GetArtifactID(files, opts.CompilerOptions...)
+	aid, err := storage.GetArtifactID(files, storage.ArtifactParams{
+		GoVersion:       toolchain.Version,
+		Target:          string(target),
+		CompilerOptions: opts.CompilerOptions,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	result := &Result{
 		FileName:     aid.Ext(storage.ExtWasm),
+		Target:       target,
 		IsTest:       projInfo.projectType == projectTypeTest,
 		HasBenchmark: projInfo.hasBenchmark,
 		HasFuzz:      projInfo.hasFuzz,
@@ -118,18 +196,48 @@ func (s BuildService) Build(ctx context.Context, files map[string][]byte, opts B
 		return nil, err
 	}
 
+	s.metrics.ObserveCacheLookup(cached != nil)
+
 	if cached != nil {
 		compilerOutput := string(cached.CompilerOutput)
 		_ = cached.Contents.Close()
 		if compilerOutput == "" && len(opts.CompilerOptions) > 0 {
 			s.log.Debug("cached artifact missing compiler output sidecar, rebuilding", zap.Stringer("artifact", aid))
 		} else {
-			result.CompilerOutput = compilerOutput
+			result.setCompilerOutput(compilerOutput)
 			s.log.Debug("build cached, returning cached file", zap.Stringer("artifact", aid))
 			return result, nil
 		}
 	}
 
+	// Identical concurrent builds share the same workspace, so only one compiler run is allowed.
+	result, shared, err := s.builds.Do(ctx, aid, func(ctx context.Context) (*Result, error) {
+		env := buildEnv{toolchain: toolchain, target: target}
+		return s.buildArtifact(ctx, aid, files, projInfo, env, result, opts)
+	})
+	if shared {
+		s.log.Debug("joined concurrent build", zap.Stringer("artifact", aid))
+	}
+
+	return result, err
+}
+
+func (s BuildService) buildArtifact(ctx context.Context, aid storage.ArtifactID, files map[string][]byte, projInfo projectInfo, env buildEnv, result *Result, opts BuildOptions) (*Result, error) {
+	if s.pool != nil {
+		release, position, err := s.pool.Acquire(ctx, opts.ClientID, opts.OnQueued)
+		if err != nil {
+			if errors.Is(err, ErrBuildQueueFull) {
+				s.metrics.ObserveQueueRejection()
+			}
+			return nil, err
+		}
+
+		defer release()
+		if position > 0 {
+			s.log.Debug("build was queued", zap.Stringer("artifact", aid), zap.Int("position", position))
+		}
+	}
+
 	workspace, err := s.storage.CreateWorkspace(aid, files)
 	if err != nil {
 		if errors.Is(err, syscall.ENOSPC) {
@@ -139,11 +247,14 @@ func (s BuildService) Build(ctx context.Context, files map[string][]byte, opts B
 		return nil, err
 	}
 
-	result.CompilerOutput, err = s.buildSource(ctx, projInfo, workspace, opts)
+	startTime := time.Now()
+	compilerOutput, err := s.buildSource(ctx, projInfo, env, workspace, opts)
+	s.metrics.ObserveBuild(projInfo.kind(), time.Since(startTime), err)
 	if err != nil {
 		return result, err
 	}
 
+	result.setCompilerOutput(trimWorkDir(compilerOutput, workspace.WorkDir))
 	if err := s.storage.SetArtifact(aid, &storage.Artifact{
 		CompilerOutput: []byte(result.CompilerOutput),
 	}); err != nil {
@@ -154,9 +265,9 @@ func (s BuildService) Build(ctx context.Context, files map[string][]byte, opts B
 	return result, err
 }
 
-func (s BuildService) buildSource(ctx context.Context, projInfo projectInfo, workspace *storage.Workspace, opts BuildOptions) (string, error) {
+func (s BuildService) buildSource(ctx context.Context, projInfo projectInfo, env buildEnv, workspace *storage.Workspace, opts BuildOptions) (string, error) {
 	// Populate go.mod and go.sum files.
-	if _, err := s.runGoTool(ctx, workspace.WorkDir, "mod", "tidy"); err != nil {
+	if _, err := s.runGoTool(ctx, env, workspace.WorkDir, "mod", "tidy"); err != nil {
 		return "", err
 	}
 
@@ -164,7 +275,7 @@ func (s BuildService) buildSource(ctx context.Context, projInfo projectInfo, wor
 		args := []string{"build"}
 		args = append(args, opts.CompilerOptions...)
 		args = append(args, "-o", workspace.BinaryPath, ".")
-		return s.runGoTool(ctx, workspace.WorkDir, args...)
+		return s.runGoTool(ctx, env, workspace.WorkDir, args...)
 	}
 
 	args := []string{"test"}
@@ -177,7 +288,7 @@ func (s BuildService) buildSource(ctx context.Context, projInfo projectInfo, wor
 
 	args = append(args, opts.CompilerOptions...)
 	args = append(args, "-c", "-o", workspace.BinaryPath)
-	return s.runGoTool(ctx, workspace.WorkDir, args...)
+	return s.runGoTool(ctx, env, workspace.WorkDir, args...)
 }
 
 
@@ -194,10 +305,10 @@ func (s BuildService) handleNoSpaceLeft() {
 	}
 }
 
-func (s BuildService) runGoTool(ctx context.Context, workDir string, args ...string) (string, error) {
-	cmd := newGoToolCommand(ctx, args...)
+func (s BuildService) runGoTool(ctx context.Context, env buildEnv, workDir string, args ...string) (string, error) {
+	cmd := env.toolchain.command(ctx, args...)
 	cmd.Dir = workDir
-	cmd.Env = s.getEnvironmentVariables()
+	cmd.Env = append(s.getEnvironmentVariables(env.target.environ()), env.toolchain.environ()...)
 	buff := &bytes.Buffer{}
 	cmd.Stderr = buff
 
@@ -207,7 +318,7 @@ func (s BuildService) runGoTool(ctx context.Context, workDir string, args ...str
 			zap.Error(err), zap.Strings("cmd", cmd.Args), zap.Stringer("stderr", buff),
 		)
 
-		return "", formatBuildError(ctx, err, buff)
+		return "", formatBuildError(ctx, err, buff, workDir)
 	}
 
 	return buff.String(), nil
@@ -227,8 +338,9 @@ func (s BuildService) Clean(ctx context.Context) error {
 		return nil
 	}
 
-	cmd := newGoToolCommand(ctx, "clean", "-modcache", "-cache", "-testcache", "-fuzzcache")
-	cmd.Env = s.getEnvironmentVariables()
+	toolchain := s.config.Toolchains.Default()
+	cmd := toolchain.command(ctx, "clean", "-modcache", "-cache", "-testcache", "-fuzzcache")
+	cmd.Env = append(s.getEnvironmentVariables(nil), toolchain.environ()...)
 	buff := &bytes.Buffer{}
 	cmd.Stderr = buff
 
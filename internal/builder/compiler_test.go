@@ -115,6 +115,7 @@ func TestBuildService_Build(t *testing.T) {
 		skip         bool
 		files        map[string][]byte
 		options      BuildOptions
+		toolchains   Toolchains
 		cmdRunner    func(t *testing.T, ctrl *gomock.Controller) CommandRunner
 		wantErr      string
 		wantResult   func(files map[string][]byte, options BuildOptions) *Result
@@ -135,12 +136,114 @@ func TestBuildService_Build(t *testing.T) {
 				}, nil
 			},
 		},
+		"unsupported go version": {
+			wantErr: "unsupported Go version: 1.10",
+			files: map[string][]byte{
+				"main.go": []byte("package main\nfunc main() {}\n"),
+			},
+			options: BuildOptions{GoVersion: "1.10"},
+			store: func(t *testing.T, _ map[string][]byte) (storage.StoreProvider, func() error) {
+				return testStorage{}, nil
+			},
+		},
+		"build using selected toolchain": {
+			files: map[string][]byte{
+				"main.go": []byte("package main\nfunc main() {}\n"),
+			},
+			options: BuildOptions{GoVersion: "1.22.5"},
+			toolchains: Toolchains{
+				HostToolchain(),
+				{Version: "1.22.5", GOROOT: "/opt/go1.22"},
+			},
+			store: func(t *testing.T, _ map[string][]byte) (storage.StoreProvider, func() error) {
+				return testStorage{
+					createWorkspace: func(id storage.ArtifactID, entries map[string][]byte) (*storage.Workspace, error) {
+						require.Equal(t, "module app\ngo 1.22", string(entries["go.mod"]))
+						return &storage.Workspace{
+							WorkDir:    "/tmp",
+							BinaryPath: "test.wasm",
+						}, nil
+					},
+				}, nil
+			},
+			cmdRunner: func(t *testing.T, ctrl *gomock.Controller) CommandRunner {
+				checkToolchain := func(cmd *exec.Cmd) error {
+					require.Equal(t, "/opt/go1.22/bin/go", cmd.Path)
+					require.Subset(t, cmd.Env, []string{"GOROOT=/opt/go1.22", "GOTOOLCHAIN=local"})
+					return nil
+				}
+
+				m := NewMockCommandRunner(ctrl)
+				m.EXPECT().RunCommand(testutil.MatchCommand("go", "mod", "tidy")).DoAndReturn(checkToolchain)
+				m.EXPECT().RunCommand(testutil.MatchCommand("go", "build", "-o", "test.wasm", ".")).DoAndReturn(checkToolchain)
+				return m
+			},
+			wantResult: func(files map[string][]byte, options BuildOptions) *Result {
+				return &Result{
+					Target:   TargetJS,
+					FileName: mustArtifactID(t, files, options).String() + ".wasm",
+				}
+			},
+		},
+		"unsupported target": {
+			wantErr: `unsupported build target "wasip2"`,
+			files: map[string][]byte{
+				"main.go": []byte("package main\nfunc main() {}\n"),
+			},
+			options: BuildOptions{Target: "wasip2"},
+			store: func(t *testing.T, _ map[string][]byte) (storage.StoreProvider, func() error) {
+				return testStorage{}, nil
+			},
+		},
+		"wasi target build": {
+			files: map[string][]byte{
+				"main.go": []byte("package main\nimport \"os\"\nfunc main() { os.ReadFile(\"foo\") }\n"),
+				"go.mod":  []byte("module foo"),
+			},
+			options: BuildOptions{Target: TargetWASI},
+			store: func(t *testing.T, files map[string][]byte) (storage.StoreProvider, func() error) {
+				return testStorage{
+					getArtifact: func(id storage.ArtifactID) (*storage.Artifact, error) {
+						// Artifact ID should differ from js/wasm build of the same sources.
+						require.NotEqual(t, mustArtifactID(t, files, BuildOptions{}), id)
+						require.Equal(t, mustArtifactID(t, files, BuildOptions{Target: TargetWASI}), id)
+						return nil, storage.ErrNotExists
+					},
+					createWorkspace: func(id storage.ArtifactID, entries map[string][]byte) (*storage.Workspace, error) {
+						return &storage.Workspace{
+							WorkDir:    "/tmp",
+							BinaryPath: "test.wasm",
+						}, nil
+					},
+				}, nil
+			},
+			cmdRunner: func(t *testing.T, ctrl *gomock.Controller) CommandRunner {
+				checkEnv := func(cmd *exec.Cmd) error {
+					env := osutil.SplitEnvironmentValues(cmd.Env)
+					require.Equal(t, "wasip1", env["GOOS"])
+					require.Equal(t, "wasm", env["GOARCH"])
+					return nil
+				}
+
+				m := NewMockCommandRunner(ctrl)
+				m.EXPECT().RunCommand(testutil.MatchCommand("go", "mod", "tidy")).DoAndReturn(checkEnv)
+				m.EXPECT().RunCommand(testutil.MatchCommand("go", "build", "-o", "test.wasm", ".")).DoAndReturn(checkEnv)
+				return m
+			},
+			wantResult: func(files map[string][]byte, options BuildOptions) *Result {
+				return &Result{
+					FileName: mustArtifactID(t, files, options).String() + ".wasm",
+					Target:   TargetWASI,
+				}
+			},
+		},
 		"cached build": {
 			files: map[string][]byte{
 				"file.go": []byte("test"),
 			},
 			wantResult: func(files map[string][]byte, _ BuildOptions) *Result {
 				return &Result{
+					Target:   TargetJS,
 					FileName: mustArtifactID(t, files, BuildOptions{}).String() + ".wasm",
 				}
 			},
@@ -160,7 +263,7 @@ func TestBuildService_Build(t *testing.T) {
 				"foo.go": []byte("test"),
 			},
 			store: func(t *testing.T, files map[string][]byte) (storage.StoreProvider, func() error) {
-				s, err := storage.NewLocalStorage(zaptest.NewLogger(t), tempDir)
+				s, err := storage.NewLocalStorage(zaptest.NewLogger(t), tempDir, storage.EvictionPolicy{})
 				require.NoError(t, err)
 				return s, func() error {
 					return os.RemoveAll(tempDir)
@@ -207,11 +310,47 @@ func TestBuildService_Build(t *testing.T) {
 			},
 			wantResult: func(files map[string][]byte, options BuildOptions) *Result {
 				return &Result{
+					Target:         TargetJS,
 					FileName:       mustArtifactID(t, files, options).String() + ".wasm",
 					CompilerOutput: "compiler diagnostics\n",
 				}
 			},
 		},
+		"build error exposes diagnostics": {
+			wantErr: "./main.go:2:14: missing return",
+			files: map[string][]byte{
+				"main.go": []byte("package main\nfunc foo() int {}\n"),
+				"go.mod":  []byte("module foo"),
+			},
+			store: func(t *testing.T, _ map[string][]byte) (storage.StoreProvider, func() error) {
+				return testStorage{
+					createWorkspace: func(id storage.ArtifactID, entries map[string][]byte) (*storage.Workspace, error) {
+						return &storage.Workspace{
+							WorkDir:    "/tmp",
+							BinaryPath: "test.wasm",
+						}, nil
+					},
+				}, nil
+			},
+			cmdRunner: func(t *testing.T, ctrl *gomock.Controller) CommandRunner {
+				m := NewMockCommandRunner(ctrl)
+				m.EXPECT().RunCommand(testutil.MatchCommand("go", "mod", "tidy")).Return(nil)
+				m.EXPECT().
+					RunCommand(testutil.MatchCommand("go", "build", "-o", "test.wasm", ".")).
+					DoAndReturn(func(cmd *exec.Cmd) error {
+						_, _ = io.WriteString(cmd.Stderr, "# foo\n./main.go:2:14: missing return\n")
+						return errors.New("exit status 1")
+					})
+				return m
+			},
+			onErrorCheck: func(t *testing.T, err error) {
+				buildErr := new(BuildError)
+				require.ErrorAs(t, err, &buildErr)
+				require.Equal(t, []Diagnostic{
+					{File: "main.go", Line: 2, Column: 14, Severity: SeverityError, Message: "missing return"},
+				}, buildErr.Diagnostics())
+			},
+		},
 		"compiler options use cache when available": {
 			files: map[string][]byte{
 				"main.go": []byte("package main\nfunc main() {}\n"),
@@ -239,11 +378,88 @@ func TestBuildService_Build(t *testing.T) {
 			},
 			wantResult: func(files map[string][]byte, options BuildOptions) *Result {
 				return &Result{
+					Target:         TargetJS,
 					FileName:       mustArtifactID(t, files, options).String() + ".wasm",
 					CompilerOutput: "escape analysis\n",
 				}
 			},
 		},
+		"compiler output is stored with workspace-relative paths": {
+			files: map[string][]byte{
+				"main.go": []byte("package main\nfunc main() {}\n"),
+				"go.mod":  []byte("module foo"),
+			},
+			options: BuildOptions{
+				CompilerOptions: []string{"-gcflags", "-m"},
+			},
+			store: func(t *testing.T, _ map[string][]byte) (storage.StoreProvider, func() error) {
+				return testStorage{
+					setArtifact: func(id storage.ArtifactID, e *storage.Artifact) error {
+						require.Equal(t, "./main.go:2:6: can inline main\n", string(e.CompilerOutput))
+						return nil
+					},
+					createWorkspace: func(id storage.ArtifactID, entries map[string][]byte) (*storage.Workspace, error) {
+						return &storage.Workspace{
+							WorkDir:    "/tmp/workspace",
+							BinaryPath: "test.wasm",
+						}, nil
+					},
+				}, nil
+			},
+			cmdRunner: func(t *testing.T, ctrl *gomock.Controller) CommandRunner {
+				m := NewMockCommandRunner(ctrl)
+				m.EXPECT().RunCommand(testutil.MatchCommand("go", "mod", "tidy")).Return(nil)
+				m.EXPECT().
+					RunCommand(testutil.MatchCommand("go", "build", "-gcflags", "-m", "-o", "test.wasm", ".")).
+					DoAndReturn(func(cmd *exec.Cmd) error {
+						_, err := io.WriteString(cmd.Stderr, "/tmp/workspace/main.go:2:6: can inline main\n")
+						return err
+					})
+				return m
+			},
+			wantResult: func(files map[string][]byte, options BuildOptions) *Result {
+				return &Result{
+					Target:         TargetJS,
+					FileName:       mustArtifactID(t, files, options).String() + ".wasm",
+					CompilerOutput: "./main.go:2:6: can inline main\n",
+					Diagnostics: []Diagnostic{
+						{File: "main.go", Line: 2, Column: 6, Severity: SeverityInfo, Message: "can inline main"},
+					},
+				}
+			},
+		},
+		"cached compiler output diagnostics": {
+			files: map[string][]byte{
+				"main.go": []byte("package main\nfunc main() {}\n"),
+				"go.mod":  []byte("module foo"),
+			},
+			options: BuildOptions{
+				CompilerOptions: []string{"-gcflags", "-m"},
+			},
+			store: func(t *testing.T, files map[string][]byte) (storage.StoreProvider, func() error) {
+				return testStorage{
+					getArtifact: func(id storage.ArtifactID) (*storage.Artifact, error) {
+						return &storage.Artifact{
+							Contents:       &testReadCloser{},
+							CompilerOutput: []byte("./main.go:2:6: can inline main\n"),
+						}, nil
+					},
+				}, nil
+			},
+			cmdRunner: func(t *testing.T, ctrl *gomock.Controller) CommandRunner {
+				return NewMockCommandRunner(ctrl)
+			},
+			wantResult: func(files map[string][]byte, options BuildOptions) *Result {
+				return &Result{
+					Target:         TargetJS,
+					FileName:       mustArtifactID(t, files, options).String() + ".wasm",
+					CompilerOutput: "./main.go:2:6: can inline main\n",
+					Diagnostics: []Diagnostic{
+						{File: "main.go", Line: 2, Column: 6, Severity: SeverityInfo, Message: "can inline main"},
+					},
+				}
+			},
+		},
 		"compiler options rebuild cached artifact when compiler output sidecar is missing": {
 			files: map[string][]byte{
 				"main.go": []byte("package main\nfunc main() {}\n"),
@@ -287,6 +503,7 @@ func TestBuildService_Build(t *testing.T) {
 			},
 			wantResult: func(files map[string][]byte, options BuildOptions) *Result {
 				return &Result{
+					Target:         TargetJS,
 					FileName:       mustArtifactID(t, files, options).String() + ".wasm",
 					CompilerOutput: "escape analysis\n",
 				}
@@ -299,7 +516,7 @@ func TestBuildService_Build(t *testing.T) {
 			},
 			store: func(t *testing.T, files map[string][]byte) (storage.StoreProvider, func() error) {
 				t.Setenv("PATH", ".")
-				s, err := storage.NewLocalStorage(zaptest.NewLogger(t), tempDir)
+				s, err := storage.NewLocalStorage(zaptest.NewLogger(t), tempDir, storage.EvictionPolicy{})
 				require.NoError(t, err)
 				return s, func() error {
 					return os.RemoveAll(tempDir)
@@ -419,6 +636,7 @@ func TestBuildService_Build(t *testing.T) {
 			},
 			wantResult: func(files map[string][]byte, _ BuildOptions) *Result {
 				return &Result{
+					Target:   TargetJS,
 					FileName: mustArtifactID(t, files, BuildOptions{}).String() + ".wasm",
 					IsTest:   true,
 				}
@@ -453,6 +671,7 @@ func TestBuildService_Build(t *testing.T) {
 			},
 			wantResult: func(files map[string][]byte, _ BuildOptions) *Result {
 				return &Result{
+					Target:       TargetJS,
 					FileName:     mustArtifactID(t, files, BuildOptions{}).String() + ".wasm",
 					IsTest:       true,
 					HasBenchmark: true,
@@ -483,7 +702,7 @@ func TestBuildService_Build(t *testing.T) {
 				}()
 			}
 
-			bs := NewBuildService(zaptest.NewLogger(t), BuildEnvironmentConfig{}, store)
+			bs := NewBuildService(zaptest.NewLogger(t), BuildEnvironmentConfig{Toolchains: c.toolchains}, store)
 			if c.cmdRunner != nil {
 				bs.cmdRunner = c.cmdRunner(t, ctrl)
 			}
@@ -507,6 +726,7 @@ func TestBuildService_Build(t *testing.T) {
 func TestBuildService_getEnvironmentVariables(t *testing.T) {
 	cases := map[string]struct {
 		includedVars osutil.EnvironmentVariables
+		target       BuildTarget
 		check        func(t *testing.T, included osutil.EnvironmentVariables, result []string)
 	}{
 		"include vars": {
@@ -526,6 +746,25 @@ func TestBuildService_getEnvironmentVariables(t *testing.T) {
 				require.Equal(t, predefinedBuildVars, got)
 			},
 		},
+		"js target": {
+			target: TargetJS,
+			check: func(t *testing.T, _ osutil.EnvironmentVariables, result []string) {
+				got := osutil.SplitEnvironmentValues(result)
+				require.Equal(t, "js", got["GOOS"])
+				require.Equal(t, "wasm", got["GOARCH"])
+			},
+		},
+		"wasi target": {
+			target: TargetWASI,
+			includedVars: osutil.EnvironmentVariables{
+				"GOOS": "stub-value",
+			},
+			check: func(t *testing.T, _ osutil.EnvironmentVariables, result []string) {
+				got := osutil.SplitEnvironmentValues(result)
+				require.Equal(t, "wasip1", got["GOOS"])
+				require.Equal(t, "wasm", got["GOARCH"])
+			},
+		},
 	}
 
 	for n, c := range cases {
@@ -534,7 +773,12 @@ func TestBuildService_getEnvironmentVariables(t *testing.T) {
 				IncludedEnvironmentVariables: c.includedVars,
 			}
 			svc := NewBuildService(zaptest.NewLogger(t), cfg, nil)
-			got := svc.getEnvironmentVariables()
+			var targetVars osutil.EnvironmentVariables
+			if c.target != "" {
+				targetVars = c.target.environ()
+			}
+
+			got := svc.getEnvironmentVariables(targetVars)
 			c.check(t, c.includedVars, got)
 		})
 	}
@@ -542,7 +786,21 @@ func TestBuildService_getEnvironmentVariables(t *testing.T) {
 
 func mustArtifactID(t *testing.T, files map[string][]byte, opts BuildOptions) storage.ArtifactID {
 	t.Helper()
-	a, err := storage.GetArtifactID(files, opts.CompilerOptions...)
+	goVersion := HostToolchain().Version
+	if opts.GoVersion != "" {
+		goVersion = opts.GoVersion
+	}
+
+	target := opts.Target
+	if target == "" {
+		target = TargetJS
+	}
+
+	a, err := storage.GetArtifactID(files, storage.ArtifactParams{
+		GoVersion:       goVersion,
+		Target:          string(target),
+		CompilerOptions: opts.CompilerOptions,
+	})
 	require.NoError(t, err)
 	return a
 }
@@ -551,3 +809,8 @@ func TestBuildError_Error(t *testing.T) {
 	msg := "test"
 	require.Equal(t, msg, newBuildError(msg).Error())
 }
+
+func TestResult_ArtifactID(t *testing.T) {
+	r := Result{FileName: "5f36b2ea290645ee34d943220a14b54e.wasm"}
+	require.Equal(t, storage.ArtifactID("5f36b2ea290645ee34d943220a14b54e"), r.ArtifactID())
+}
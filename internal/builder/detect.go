@@ -31,6 +31,20 @@ type projectInfo struct {
 	hasFuzz      bool
 }
 
+// kind returns project kind name.
+func (p projectInfo) kind() string {
+	switch {
+	case p.projectType == projectTypeProgram:
+		return "program"
+	case p.hasFuzz:
+		return "fuzz"
+	case p.hasBenchmark:
+		return "bench"
+	default:
+		return "test"
+	}
+}
+
 func (p *projectInfo) sum(other projectInfo) {
 	if other.projectType == projectTypeProgram {
 		return
@@ -0,0 +1,164 @@
+package builder
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severity is diagnostic severity.
+type Severity string
+
+const (
+	// SeverityError is reported for compile errors.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is reported for go vet findings.
+	SeverityWarning Severity = "warning"
+
+	// SeverityInfo is reported for compiler annotations, like "-gcflags=-m" optimization decisions.
+	SeverityInfo Severity = "info"
+)
+
+// Diagnostic is a message reported by Go toolchain for a specific position in a source file.
+type Diagnostic struct {
+	// File is file path relative to the workspace root.
+	File string `json:"file"`
+
+	// Line is 1-based line number.
+	Line int `json:"line"`
+
+	// Column is 1-based column number in bytes.
+	//
+	// Zero value means that column is unknown.
+	Column int `json:"column,omitempty"`
+
+	// Severity is diagnostic severity.
+	Severity Severity `json:"severity"`
+
+	// Message is diagnostic message.
+	Message string `json:"message"`
+}
+
+// diagnosticRegex matches "file:line:col: message" and "file:line: message" lines.
+var diagnosticRegex = regexp.MustCompile(`^(\S[^:]*):(\d+)(?::(\d+))?: (.*)$`)
+
+// ParseDiagnostics parses go build, go test and go mod output into a list of diagnostics.
+//
+// Messages are reported with a passed severity, except go vet findings which are reported as warnings.
+// Messages without a position or referencing files outside the workspace are skipped.
+//
+// Work directory is used to convert absolute file paths to workspace-relative paths and can be empty.
+func ParseDiagnostics(output, workDir string, severity Severity) []Diagnostic {
+	var (
+		diagnostics []Diagnostic
+		isVet       bool
+		last        *Diagnostic
+	)
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			last = nil
+			continue
+		}
+
+		if header, ok := strings.CutPrefix(line, "# "); ok {
+			// go vet output is reported in a section like "# [pkg]" or "# [pkg.test]".
+			isVet = strings.HasPrefix(header, "[")
+			last = nil
+			continue
+		}
+
+		if last != nil && line[0] == '\t' {
+			// Continuation of a multi-line message.
+			last.Message += "\n" + strings.TrimSpace(line)
+			continue
+		}
+
+		last = nil
+		line, lineIsVet := strings.CutPrefix(line, "vet: ")
+		d, ok := parseDiagnostic(line, workDir)
+		if !ok {
+			continue
+		}
+
+		d.Severity = severity
+		if isVet || lineIsVet {
+			d.Severity = SeverityWarning
+		}
+
+		diagnostics = append(diagnostics, d)
+		last = &diagnostics[len(diagnostics)-1]
+	}
+
+	return diagnostics
+}
+
+func parseDiagnostic(line, workDir string) (Diagnostic, bool) {
+	m := diagnosticRegex.FindStringSubmatch(line)
+	if m == nil {
+		return Diagnostic{}, false
+	}
+
+	fileName, ok := workspaceRelativePath(m[1], workDir)
+	if !ok {
+		return Diagnostic{}, false
+	}
+
+	lineNum, err := strconv.Atoi(m[2])
+	if err != nil {
+		return Diagnostic{}, false
+	}
+
+	d := Diagnostic{
+		File:    fileName,
+		Line:    lineNum,
+		Message: m[4],
+	}
+
+	if m[3] != "" {
+		d.Column, _ = strconv.Atoi(m[3])
+	}
+
+	return d, true
+}
+
+// trimWorkDir replaces absolute paths to files inside the workspace with relative paths in Go toolchain output.
+//
+// Output with relative paths can be cached and parsed without knowing workspace location.
+func trimWorkDir(output, workDir string) string {
+	if workDir == "" {
+		return output
+	}
+
+	prefix := filepath.Clean(workDir) + string(filepath.Separator)
+	return strings.ReplaceAll(output, prefix, "./")
+}
+
+// workspaceRelativePath returns file path relative to the workspace.
+//
+// Returns false if file is outside the workspace.
+func workspaceRelativePath(fileName, workDir string) (string, bool) {
+	if filepath.IsAbs(fileName) {
+		if workDir == "" {
+			return "", false
+		}
+
+		rel, err := filepath.Rel(workDir, fileName)
+		if err != nil {
+			return "", false
+		}
+
+		fileName = rel
+	}
+
+	fileName = path.Clean(filepath.ToSlash(fileName))
+	if fileName == "." || fileName == ".." || strings.HasPrefix(fileName, "../") {
+		return "", false
+	}
+
+	return fileName, true
+}
@@ -0,0 +1,139 @@
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiagnostics(t *testing.T) {
+	cases := map[string]struct {
+		output   string
+		workDir  string
+		severity Severity
+		expect   []Diagnostic
+	}{
+		"empty output": {
+			severity: SeverityError,
+		},
+		"compile errors": {
+			severity: SeverityError,
+			output: "# app\n" +
+				"./main.go:5:2: undefined: foo\n" +
+				"./pkg/util.go:10:6: declared and not used: x\n",
+			expect: []Diagnostic{
+				{File: "main.go", Line: 5, Column: 2, Severity: SeverityError, Message: "undefined: foo"},
+				{File: "pkg/util.go", Line: 10, Column: 6, Severity: SeverityError, Message: "declared and not used: x"},
+			},
+		},
+		"test build errors": {
+			severity: SeverityError,
+			output: "# app [app.test]\n" +
+				"./main_test.go:8:9: cannot use x (variable of type int) as string value in return statement\n" +
+				"FAIL\tapp [build failed]\n",
+			expect: []Diagnostic{
+				{
+					File: "main_test.go", Line: 8, Column: 9, Severity: SeverityError,
+					Message: "cannot use x (variable of type int) as string value in return statement",
+				},
+			},
+		},
+		"vet findings": {
+			severity: SeverityError,
+			output: "# app\n" +
+				"# [app]\n" +
+				"./main_test.go:8:2: fmt.Printf format %d has arg s of wrong type string\n" +
+				"vet: ./main.go:3:8: could not import foo (invalid package name)\n",
+			expect: []Diagnostic{
+				{File: "main_test.go", Line: 8, Column: 2, Severity: SeverityWarning, Message: "fmt.Printf format %d has arg s of wrong type string"},
+				{File: "main.go", Line: 3, Column: 8, Severity: SeverityWarning, Message: "could not import foo (invalid package name)"},
+			},
+		},
+		"multi-line messages": {
+			severity: SeverityError,
+			output: "./main.go:7:4: impossible type assertion: r.(*os.File)\n" +
+				"\t*os.File does not implement io.Writer (missing method Write)\n" +
+				"./main.go:9:1: missing return\n",
+			expect: []Diagnostic{
+				{
+					File: "main.go", Line: 7, Column: 4, Severity: SeverityError,
+					Message: "impossible type assertion: r.(*os.File)\n*os.File does not implement io.Writer (missing method Write)",
+				},
+				{File: "main.go", Line: 9, Column: 1, Severity: SeverityError, Message: "missing return"},
+			},
+		},
+		"optimization annotations": {
+			severity: SeverityInfo,
+			output: "# app\n" +
+				"./main.go:5:6: can inline add\n" +
+				"./main.go:10:13: ... argument does not escape\n" +
+				"./main.go:9:2: moved to heap: x\n",
+			expect: []Diagnostic{
+				{File: "main.go", Line: 5, Column: 6, Severity: SeverityInfo, Message: "can inline add"},
+				{File: "main.go", Line: 10, Column: 13, Severity: SeverityInfo, Message: "... argument does not escape"},
+				{File: "main.go", Line: 9, Column: 2, Severity: SeverityInfo, Message: "moved to heap: x"},
+			},
+		},
+		"go mod errors": {
+			severity: SeverityError,
+			workDir:  "/tmp/workspace",
+			output: "go: errors parsing go.mod:\n" +
+				"/tmp/workspace/go.mod:3: unknown directive: foo\n",
+			expect: []Diagnostic{
+				{File: "go.mod", Line: 3, Severity: SeverityError, Message: "unknown directive: foo"},
+			},
+		},
+		"skip messages without position": {
+			severity: SeverityError,
+			output: "go: finding module for package example.com/foo\n" +
+				"app imports\n" +
+				"\texample.com/foo: cannot find module providing package example.com/foo\n",
+		},
+		"skip files outside workspace": {
+			severity: SeverityError,
+			workDir:  "/tmp/workspace",
+			output: "/usr/local/go/src/fmt/print.go:10:2: foo\n" +
+				"../other/main.go:1:1: bar\n" +
+				"/tmp/workspace/main.go:1:1: baz\n",
+			expect: []Diagnostic{
+				{File: "main.go", Line: 1, Column: 1, Severity: SeverityError, Message: "baz"},
+			},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := ParseDiagnostics(c.output, c.workDir, c.severity)
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
+
+func TestTrimWorkDir(t *testing.T) {
+	cases := map[string]struct {
+		output  string
+		workDir string
+		expect  string
+	}{
+		"no work dir": {
+			output: "/tmp/foo/main.go:3:6: can inline main\n",
+			expect: "/tmp/foo/main.go:3:6: can inline main\n",
+		},
+		"absolute paths": {
+			output:  "/tmp/foo/main.go:3:6: can inline main\n/tmp/foo/pkg/bar.go:1:1: can inline Bar\n",
+			workDir: "/tmp/foo/",
+			expect:  "./main.go:3:6: can inline main\n./pkg/bar.go:1:1: can inline Bar\n",
+		},
+		"keep paths outside workspace": {
+			output:  "/tmp/foobar/main.go:3:6: can inline main\n/usr/lib/go/src/fmt/print.go:1:1: inlining call\n",
+			workDir: "/tmp/foo",
+			expect:  "/tmp/foobar/main.go:3:6: can inline main\n/usr/lib/go/src/fmt/print.go:1:1: inlining call\n",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			require.Equal(t, c.expect, trimWorkDir(c.output, c.workDir))
+		})
+	}
+}
@@ -9,7 +9,8 @@ import (
 
 // BuildError is build error
 type BuildError struct {
-	message string
+	message     string
+	diagnostics []Diagnostic
 }
 
 // Error implements error
@@ -17,6 +18,11 @@ func (e *BuildError) Error() string {
 	return e.message
 }
 
+// Diagnostics returns list of diagnostics parsed from build output.
+func (e *BuildError) Diagnostics() []Diagnostic {
+	return e.diagnostics
+}
+
 func newBuildError(msg string, args ...any) *BuildError {
 	if len(args) > 0 {
 		msg = fmt.Sprintf(msg, args...)
@@ -50,9 +56,13 @@ func checkContextErrors(err error) (error, bool) {
 	return nil, false
 }
 
-func formatBuildError(ctx context.Context, err error, buff *bytes.Buffer) error {
+func formatBuildError(ctx context.Context, err error, buff *bytes.Buffer, workDir string) error {
 	if buff.Len() > 0 {
-		return newBuildError(buff.String())
+		output := buff.String()
+		return &BuildError{
+			message:     output,
+			diagnostics: ParseDiagnostics(output, workDir, SeverityError),
+		}
 	}
 
 	newErr, ok := checkContextErrors(err)
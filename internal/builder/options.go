@@ -7,6 +7,24 @@ import (
 
 type BuildOptions struct {
 	CompilerOptions []string
+
+	// ClientID identifies build requester for fair build queue scheduling.
+	ClientID string
+
+	// GoVersion is Go toolchain version.
+	//
+	// Default toolchain is used if value is empty.
+	GoVersion string
+
+	// Target is build target.
+	//
+	// TargetJS is used if value is empty.
+	Target BuildTarget
+
+	// OnQueued is optional handler called with a queue position when build waits for a free worker.
+	//
+	// Handler isn't called when requester joins already running build of the same program.
+	OnQueued func(position int)
 }
 
 var compilerOptionsWithValues = map[string]struct{}{
@@ -0,0 +1,223 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRetryAfter is retry delay suggested when average build time is unknown yet.
+	defaultRetryAfter = 5 * time.Second
+
+	// buildTimeSmoothing is smoothing factor of build time moving average.
+	buildTimeSmoothing = 0.2
+)
+
+// ErrBuildQueueFull is returned when build queue has no free slots.
+var ErrBuildQueueFull = errors.New("build queue is full")
+
+// QueueFullError is returned when build can't be queued.
+type QueueFullError struct {
+	// RetryAfter is suggested delay before retry.
+	RetryAfter time.Duration
+
+	reason string
+}
+
+// Error implements error interface.
+func (err QueueFullError) Error() string {
+	return fmt.Sprintf("%s, try again in %s", err.reason, err.RetryAfter.Round(time.Second))
+}
+
+// Unwrap implements error interface.
+func (err QueueFullError) Unwrap() error {
+	return ErrBuildQueueFull
+}
+
+// PoolConfig is build pool configuration.
+type PoolConfig struct {
+	// MaxWorkers is max number of parallel builds.
+	//
+	// Zero value disables pool.
+	MaxWorkers int
+
+	// MaxQueueSize is max number of builds waiting for a free worker.
+	MaxQueueSize int
+
+	// MaxClientQueueSize is max number of builds from a single client waiting for a free worker.
+	//
+	// Zero value disables limit.
+	MaxClientQueueSize int
+}
+
+type poolTicket struct {
+	clientID string
+	ready    chan struct{}
+}
+
+// BuildPool limits number of parallel builds.
+//
+// Builds exceeding the limit are queued. Free workers are distributed between
+// clients in a round-robin manner, so a single client can't occupy the whole queue.
+type BuildPool struct {
+	lock sync.Mutex
+	cfg  PoolConfig
+
+	active   int
+	queued   int
+	queues   map[string][]*poolTicket
+	clients  []string
+	avgBuild time.Duration
+}
+
+// NewBuildPool returns a new build pool.
+func NewBuildPool(cfg PoolConfig) *BuildPool {
+	return &BuildPool{
+		cfg:    cfg,
+		queues: make(map[string][]*poolTicket),
+	}
+}
+
+// Acquire waits for a free worker and returns a function to release it.
+//
+// Returned position is a position in a queue at the moment when build was queued.
+// Zero position means that build started immediately.
+//
+// Optional onQueued handler is called with a queue position before waiting for a worker.
+func (p *BuildPool) Acquire(ctx context.Context, clientID string, onQueued func(position int)) (release func(), position int, err error) {
+	p.lock.Lock()
+	if p.active < p.cfg.MaxWorkers && p.queued == 0 {
+		p.active++
+		p.lock.Unlock()
+		return p.releaseFunc(), 0, nil
+	}
+
+	if p.queued >= p.cfg.MaxQueueSize {
+		p.lock.Unlock()
+		return nil, 0, p.queueFullError("build queue is full")
+	}
+
+	if p.cfg.MaxClientQueueSize > 0 && len(p.queues[clientID]) >= p.cfg.MaxClientQueueSize {
+		p.lock.Unlock()
+		return nil, 0, p.queueFullError("too many pending builds")
+	}
+
+	ticket := &poolTicket{
+		clientID: clientID,
+		ready:    make(chan struct{}),
+	}
+	if len(p.queues[clientID]) == 0 {
+		p.clients = append(p.clients, clientID)
+	}
+	p.queues[clientID] = append(p.queues[clientID], ticket)
+	p.queued++
+	position = p.queued
+	p.lock.Unlock()
+
+	if onQueued != nil {
+		onQueued(position)
+	}
+
+	select {
+	case <-ticket.ready:
+		return p.releaseFunc(), position, nil
+	case <-ctx.Done():
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	select {
+	case <-ticket.ready:
+		// Worker was assigned concurrently with cancellation.
+		p.releaseLocked(0)
+	default:
+		p.removeTicket(ticket)
+	}
+
+	return nil, 0, ctx.Err()
+}
+
+func (p *BuildPool) releaseFunc() func() {
+	startTime := time.Now()
+	once := new(sync.Once)
+	return func() {
+		once.Do(func() {
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			p.releaseLocked(time.Since(startTime))
+		})
+	}
+}
+
+func (p *BuildPool) releaseLocked(buildTime time.Duration) {
+	p.active--
+	if buildTime > 0 {
+		if p.avgBuild == 0 {
+			p.avgBuild = buildTime
+		} else {
+			p.avgBuild += time.Duration(buildTimeSmoothing * float64(buildTime-p.avgBuild))
+		}
+	}
+
+	// Pick next client in round-robin order.
+	for p.active < p.cfg.MaxWorkers && len(p.clients) > 0 {
+		clientID := p.clients[0]
+		p.clients = p.clients[1:]
+
+		queue := p.queues[clientID]
+		ticket := queue[0]
+		if len(queue) > 1 {
+			p.queues[clientID] = queue[1:]
+			p.clients = append(p.clients, clientID)
+		} else {
+			delete(p.queues, clientID)
+		}
+
+		p.queued--
+		p.active++
+		close(ticket.ready)
+	}
+}
+
+func (p *BuildPool) removeTicket(ticket *poolTicket) {
+	queue := p.queues[ticket.clientID]
+	for i, t := range queue {
+		if t != ticket {
+			continue
+		}
+
+		queue = append(queue[:i], queue[i+1:]...)
+		p.queued--
+		break
+	}
+
+	if len(queue) > 0 {
+		p.queues[ticket.clientID] = queue
+		return
+	}
+
+	delete(p.queues, ticket.clientID)
+	for i, clientID := range p.clients {
+		if clientID == ticket.clientID {
+			p.clients = append(p.clients[:i], p.clients[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *BuildPool) queueFullError(reason string) error {
+	retryAfter := defaultRetryAfter
+	if p.avgBuild > 0 {
+		// Estimate time required to process current queue.
+		batches := p.queued/p.cfg.MaxWorkers + 1
+		retryAfter = max(time.Duration(batches)*p.avgBuild, time.Second)
+	}
+
+	return QueueFullError{
+		RetryAfter: retryAfter,
+		reason:     reason,
+	}
+}
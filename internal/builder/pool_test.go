@@ -0,0 +1,136 @@
+package builder
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPool_Acquire(t *testing.T) {
+	t.Run("start immediately if has free workers", func(t *testing.T) {
+		p := NewBuildPool(PoolConfig{MaxWorkers: 2})
+		for i := 0; i < 2; i++ {
+			release, pos, err := p.Acquire(context.Background(), "foo", nil)
+			require.NoError(t, err)
+			require.Zero(t, pos)
+			defer release()
+		}
+	})
+
+	t.Run("reject when queue is full", func(t *testing.T) {
+		p := NewBuildPool(PoolConfig{MaxWorkers: 1})
+		release, _, err := p.Acquire(context.Background(), "foo", nil)
+		require.NoError(t, err)
+		defer release()
+
+		_, _, err = p.Acquire(context.Background(), "bar", nil)
+		require.ErrorIs(t, err, ErrBuildQueueFull)
+
+		var queueErr QueueFullError
+		require.ErrorAs(t, err, &queueErr)
+		require.Equal(t, defaultRetryAfter, queueErr.RetryAfter)
+	})
+
+	t.Run("limit queued builds per client", func(t *testing.T) {
+		p := NewBuildPool(PoolConfig{MaxWorkers: 1, MaxQueueSize: 10, MaxClientQueueSize: 1})
+		release, _, err := p.Acquire(context.Background(), "foo", nil)
+		require.NoError(t, err)
+		defer release()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			_, _, _ = p.Acquire(ctx, "foo", nil)
+		}()
+		waitQueued(t, p, 1)
+
+		_, _, err = p.Acquire(context.Background(), "foo", nil)
+		require.ErrorIs(t, err, ErrBuildQueueFull)
+		require.Contains(t, err.Error(), "too many pending builds")
+	})
+
+	t.Run("remove cancelled builds from queue", func(t *testing.T) {
+		p := NewBuildPool(PoolConfig{MaxWorkers: 1, MaxQueueSize: 1})
+		release, _, err := p.Acquire(context.Background(), "foo", nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errCh := make(chan error)
+		go func() {
+			_, _, err := p.Acquire(ctx, "bar", nil)
+			errCh <- err
+		}()
+		waitQueued(t, p, 1)
+
+		cancel()
+		require.ErrorIs(t, <-errCh, context.Canceled)
+		require.Zero(t, p.queued)
+		require.Empty(t, p.queues)
+		require.Empty(t, p.clients)
+
+		release()
+		require.Zero(t, p.active)
+	})
+
+	t.Run("report queue position", func(t *testing.T) {
+		p := NewBuildPool(PoolConfig{MaxWorkers: 1, MaxQueueSize: 1})
+		release, _, err := p.Acquire(context.Background(), "foo", func(int) {
+			t.Fatal("build which started immediately shouldn't be reported as queued")
+		})
+		require.NoError(t, err)
+
+		positions := make(chan int, 1)
+		errCh := make(chan error)
+		go func() {
+			release, _, err := p.Acquire(context.Background(), "bar", func(position int) {
+				positions <- position
+			})
+			if err == nil {
+				release()
+			}
+			errCh <- err
+		}()
+
+		require.Equal(t, 1, <-positions)
+		release()
+		require.NoError(t, <-errCh)
+	})
+
+	t.Run("distribute workers between clients", func(t *testing.T) {
+		p := NewBuildPool(PoolConfig{MaxWorkers: 1, MaxQueueSize: 10})
+		release, _, err := p.Acquire(context.Background(), "init", nil)
+		require.NoError(t, err)
+
+		order := make(chan string, 3)
+		acquire := func(clientID string, expectPos int) {
+			go func() {
+				release, pos, err := p.Acquire(context.Background(), clientID, nil)
+				require.NoError(t, err)
+				require.Equal(t, expectPos, pos)
+				order <- clientID
+				release()
+			}()
+			waitQueued(t, p, expectPos)
+		}
+
+		acquire("foo", 1)
+		acquire("foo", 2)
+		acquire("bar", 3)
+		release()
+
+		require.Equal(t, "foo", <-order)
+		require.Equal(t, "bar", <-order)
+		require.Equal(t, "foo", <-order)
+	})
+}
+
+func waitQueued(t *testing.T, p *BuildPool, count int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		return p.queued == count
+	}, time.Second, time.Millisecond)
+}
@@ -28,8 +28,22 @@ func (a ArtifactID) String() string {
 	return string(a)
 }
 
-// GetArtifactID generates new artifact ID from contents and optional compiler options.
-func GetArtifactID(entries map[string][]byte, compilerOptions ...string) (ArtifactID, error) {
+// ArtifactParams is a set of optional build parameters which produce a different artifact from the same sources.
+type ArtifactParams struct {
+	// GoVersion is Go toolchain version.
+	GoVersion string
+
+	// Target is build target OS.
+	Target string
+
+	// CompilerOptions is a list of compiler flags.
+	CompilerOptions []string
+}
+
+// GetArtifactID generates new artifact ID from contents and build parameters.
+//
+// Empty parameters don't affect artifact ID.
+func GetArtifactID(entries map[string][]byte, params ArtifactParams) (ArtifactID, error) {
 	h := md5.New()
 
 	// Keys have to be sorted for constant hashing
@@ -51,9 +65,19 @@ func GetArtifactID(entries map[string][]byte, compilerOptions ...string) (Artifa
 		_, _ = h.Write(contents)
 	}
 
-	if len(compilerOptions) > 0 {
+	if params.GoVersion != "" {
+		_, _ = h.Write([]byte("\n-- .goplay-go-version --\n"))
+		_, _ = h.Write([]byte(params.GoVersion))
+	}
+
+	if params.Target != "" {
+		_, _ = h.Write([]byte("\n-- .goplay-target --\n"))
+		_, _ = h.Write([]byte(params.Target))
+	}
+
+	if len(params.CompilerOptions) > 0 {
 		_, _ = h.Write([]byte("\n-- .goplay-compiler-options --\n"))
-		_, _ = h.Write([]byte(strings.Join(compilerOptions, "\x00")))
+		_, _ = h.Write([]byte(strings.Join(params.CompilerOptions, "\x00")))
 	}
 
 	fName := hex.EncodeToString(h.Sum(nil))
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// BlobStore is a key-value storage for binary objects shared between server instances.
+type BlobStore interface {
+	// GetBlob returns blob contents by key.
+	// Returns ErrNotExists if blob doesn't exist.
+	GetBlob(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// PutBlob stores blob contents by key.
+	PutBlob(ctx context.Context, key string, r io.Reader, size int64) error
+}
+
+// NewBlobStoreFromURL returns a new blob store from a connection URL.
+//
+// Supported URL formats:
+//
+//	s3://[ACCESS_KEY:SECRET_KEY@]HOST[:PORT]/BUCKET[/PREFIX][?region=REGION&secure=false]
+//	redis://[[USER]:PASSWORD@]HOST[:PORT][/DB][?ttl=DURATION]
+//	rediss://[[USER]:PASSWORD@]HOST[:PORT][/DB][?ttl=DURATION]
+func NewBlobStoreFromURL(rawURL string) (BlobStore, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("malformed storage URL: %w", err)
+	}
+
+	switch u.Scheme {
+	case "s3":
+		return newS3BlobStoreFromURL(u)
+	case "redis", "rediss":
+		return newRedisBlobStoreFromURL(u)
+	default:
+		return nil, fmt.Errorf("unsupported storage type %q", u.Scheme)
+	}
+}
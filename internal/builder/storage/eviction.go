@@ -0,0 +1,248 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultWorkspaceMaxAge is workspace max age used when policy doesn't have age limit.
+const defaultWorkspaceMaxAge = time.Hour
+
+// EvictionPolicy is local artifact cache eviction policy.
+//
+// Zero value disables eviction and all artifacts are removed on each cleanup.
+type EvictionPolicy struct {
+	// MaxAge is max time since artifact last access.
+	//
+	// Zero value disables age limit.
+	MaxAge time.Duration
+
+	// MaxSize is max total size of artifacts in bytes.
+	//
+	// Least recently used artifacts are evicted first when limit is exceeded.
+	// Zero value disables size limit.
+	MaxSize int64
+}
+
+// IsZero returns whether policy has no limits.
+func (p EvictionPolicy) IsZero() bool {
+	return p.MaxAge == 0 && p.MaxSize == 0
+}
+
+func (p EvictionPolicy) workspaceMaxAge() time.Duration {
+	if p.MaxAge == 0 {
+		return defaultWorkspaceMaxAge
+	}
+
+	return p.MaxAge
+}
+
+// CacheStats is artifact cache usage statistics.
+type CacheStats struct {
+	// Evictions is number of evicted artifacts.
+	Evictions uint64
+}
+
+type cacheCounters struct {
+	evictions atomic.Uint64
+}
+
+func (c *cacheCounters) stats() CacheStats {
+	return CacheStats{
+		Evictions: c.evictions.Load(),
+	}
+}
+
+// accessTracker keeps last access time of cache entries.
+type accessTracker struct {
+	lock  sync.Mutex
+	items map[ArtifactID]time.Time
+}
+
+func newAccessTracker() *accessTracker {
+	return &accessTracker{items: make(map[ArtifactID]time.Time)}
+}
+
+func (t *accessTracker) touch(id ArtifactID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.items[id] = time.Now()
+}
+
+func (t *accessTracker) forget(id ArtifactID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.items, id)
+}
+
+// lastAccess returns entry last access time.
+//
+// Fallback value is used for entries created before process start.
+func (t *accessTracker) lastAccess(id ArtifactID, fallback time.Time) time.Time {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if v, ok := t.items[id]; ok {
+		return v
+	}
+
+	return fallback
+}
+
+type cacheEntry struct {
+	id         ArtifactID
+	size       int64
+	lastAccess time.Time
+	files      []string
+}
+
+// listArtifacts returns list of cached artifacts sorted by last access time.
+func (s LocalStorage) listArtifacts() ([]*cacheEntry, error) {
+	items, err := os.ReadDir(s.binDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	// Artifact consists of a binary and sidecar files with the same id prefix.
+	entries := make(map[ArtifactID]*cacheEntry, len(items))
+	for _, item := range items {
+		info, err := item.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		prefix, _, _ := strings.Cut(item.Name(), ".")
+		id := ArtifactID(prefix)
+		entry, ok := entries[id]
+		if !ok {
+			entry = &cacheEntry{id: id}
+			entries[id] = entry
+		}
+
+		entry.size += info.Size()
+		entry.files = append(entry.files, filepath.Join(s.binDir, item.Name()))
+		if info.ModTime().After(entry.lastAccess) {
+			entry.lastAccess = info.ModTime()
+		}
+	}
+
+	result := make([]*cacheEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.lastAccess = s.artifactAccess.lastAccess(entry.id, entry.lastAccess)
+		result = append(result, entry)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].lastAccess.Before(result[j].lastAccess)
+	})
+	return result, nil
+}
+
+// evict removes stale and least recently used artifacts according to eviction policy.
+func (s LocalStorage) evict(ctx context.Context) error {
+	s.useLock.Lock()
+	defer s.useLock.Unlock()
+
+	entries, err := s.listArtifacts()
+	if err != nil {
+		return err
+	}
+
+	var totalSize int64
+	for _, entry := range entries {
+		totalSize += entry.size
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		isStale := s.policy.MaxAge > 0 && now.Sub(entry.lastAccess) > s.policy.MaxAge
+		isOverflow := s.policy.MaxSize > 0 && totalSize > s.policy.MaxSize
+		if !isStale && !isOverflow {
+			// Entries are sorted by access time, so the rest are fresh too.
+			break
+		}
+
+		for _, f := range entry.files {
+			if err := os.Remove(f); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		totalSize -= entry.size
+		s.artifactAccess.forget(entry.id)
+		s.counters.evictions.Add(1)
+		s.log.Debug("evicted artifact",
+			zap.Stringer("artifact", entry.id),
+			zap.Bool("stale", isStale),
+			zap.Int64("size", entry.size),
+		)
+	}
+
+	if err := s.evictWorkspaces(ctx, now); err != nil {
+		return err
+	}
+
+	s.log.Debug("eviction finished", zap.Int64("cacheSize", totalSize), zap.Any("stats", s.Stats()))
+	return nil
+}
+
+func (s LocalStorage) evictWorkspaces(ctx context.Context, now time.Time) error {
+	items, err := os.ReadDir(s.srcDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	maxAge := s.policy.workspaceMaxAge()
+	for _, item := range items {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		info, err := item.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return err
+		}
+
+		id := ArtifactID(item.Name())
+		if now.Sub(s.workspaceAccess.lastAccess(id, info.ModTime())) <= maxAge {
+			continue
+		}
+
+		if err := os.RemoveAll(filepath.Join(s.srcDir, item.Name())); err != nil {
+			return err
+		}
+
+		s.workspaceAccess.forget(id)
+		s.log.Debug("removed stale workspace", zap.Stringer("artifact", id))
+	}
+
+	return nil
+}
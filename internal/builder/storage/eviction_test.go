@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestLocalStorage_Evict(t *testing.T) {
+	type artifact struct {
+		id         ArtifactID
+		size       int
+		lastAccess time.Duration
+	}
+
+	cases := map[string]struct {
+		policy     EvictionPolicy
+		artifacts  []artifact
+		workspaces map[ArtifactID]time.Duration
+		expect     []ArtifactID
+		expectWs   []ArtifactID
+	}{
+		"evict stale artifacts": {
+			policy: EvictionPolicy{MaxAge: time.Hour},
+			artifacts: []artifact{
+				{id: "a", size: 4, lastAccess: 2 * time.Hour},
+				{id: "b", size: 4, lastAccess: time.Minute},
+			},
+			expect: []ArtifactID{"b"},
+		},
+		"evict least recently used artifacts": {
+			policy: EvictionPolicy{MaxSize: 10},
+			artifacts: []artifact{
+				{id: "a", size: 4, lastAccess: 3 * time.Minute},
+				{id: "b", size: 4, lastAccess: time.Minute},
+				{id: "c", size: 4, lastAccess: 2 * time.Minute},
+			},
+			expect: []ArtifactID{"b", "c"},
+		},
+		"evict stale workspaces": {
+			policy: EvictionPolicy{MaxAge: time.Hour},
+			workspaces: map[ArtifactID]time.Duration{
+				"a": 2 * time.Hour,
+				"b": time.Minute,
+			},
+			expectWs: []ArtifactID{"b"},
+		},
+		"use default workspace age without age limit": {
+			policy: EvictionPolicy{MaxSize: 1024},
+			workspaces: map[ArtifactID]time.Duration{
+				"a": defaultWorkspaceMaxAge + time.Minute,
+				"b": time.Minute,
+			},
+			expectWs: []ArtifactID{"b"},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			s, err := NewLocalStorage(zaptest.NewLogger(t), t.TempDir(), c.policy)
+			require.NoError(t, err)
+
+			now := time.Now()
+			for _, a := range c.artifacts {
+				ws, err := s.CreateWorkspace(a.id, nil)
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(ws.BinaryPath, make([]byte, a.size), perm))
+				require.NoError(t, s.SetArtifact(a.id, &Artifact{}))
+				s.artifactAccess.items[a.id] = now.Add(-a.lastAccess)
+			}
+
+			for id, lastAccess := range c.workspaces {
+				_, err := s.CreateWorkspace(id, map[string][]byte{"main.go": []byte("package main")})
+				require.NoError(t, err)
+				s.workspaceAccess.items[id] = now.Add(-lastAccess)
+			}
+
+			require.NoError(t, s.Clean(context.Background()))
+
+			var got []ArtifactID
+			for _, a := range c.artifacts {
+				artifact, err := s.GetArtifact(a.id)
+				if err != nil {
+					require.ErrorIs(t, err, ErrNotExists)
+					continue
+				}
+
+				require.NoError(t, artifact.Contents.Close())
+				got = append(got, a.id)
+			}
+			require.Equal(t, c.expect, got)
+			require.Equal(t, uint64(len(c.artifacts)-len(c.expect)), s.Stats().Evictions)
+
+			var gotWs []ArtifactID
+			for id := range c.workspaces {
+				if _, err := os.Stat(filepath.Join(s.srcDir, id.String())); err == nil {
+					gotWs = append(gotWs, id)
+				}
+			}
+			require.Equal(t, c.expectWs, gotWs)
+		})
+	}
+}
@@ -21,6 +21,9 @@ const (
 
 	maxCleanTime = time.Second * 10
 	perm         = 0744
+
+	// extCompilerOutput is extension of compiler output sidecar file.
+	extCompilerOutput = "stderr"
 )
 
 type cachedFile struct {
@@ -49,10 +52,17 @@ type LocalStorage struct {
 	workDir string
 	srcDir  string
 	binDir  string
+
+	policy          EvictionPolicy
+	counters        *cacheCounters
+	artifactAccess  *accessTracker
+	workspaceAccess *accessTracker
 }
 
-// NewLocalStorage constructs new local storage
-func NewLocalStorage(log *zap.Logger, baseDir string) (ls *LocalStorage, err error) {
+// NewLocalStorage constructs new local storage.
+//
+// Storage cleanup removes artifacts according to passed eviction policy.
+func NewLocalStorage(log *zap.Logger, baseDir string, policy EvictionPolicy) (ls *LocalStorage, err error) {
 	var isDirty bool
 	logger := log.Named("storage")
 	workDir := filepath.Join(baseDir, workDirName)
@@ -79,6 +89,11 @@ func NewLocalStorage(log *zap.Logger, baseDir string) (ls *LocalStorage, err err
 		gcRun:   abool.NewBool(false),
 		binDir:  filepath.Join(workDir, binDirName),
 		srcDir:  filepath.Join(workDir, srcDirName),
+
+		policy:          policy,
+		counters:        &cacheCounters{},
+		artifactAccess:  newAccessTracker(),
+		workspaceAccess: newAccessTracker(),
 	}, nil
 }
 
@@ -100,7 +115,7 @@ func (s LocalStorage) getOutputLocation(id ArtifactID) string {
 }
 
 func (s LocalStorage) getCompilerOutputLocation(id ArtifactID) string {
-	return filepath.Join(s.binDir, id.Ext("stderr"))
+	return filepath.Join(s.binDir, id.Ext(extCompilerOutput))
 }
 
 // GetArtifact implements StoreProvider interface.
@@ -116,6 +131,7 @@ func (s LocalStorage) GetArtifact(id ArtifactID) (*Artifact, error) {
 		return nil, err
 	}
 
+	s.artifactAccess.touch(id)
 	stat, err := f.Stat()
 	if err != nil {
 		_ = f.Close()
@@ -147,6 +163,7 @@ func (s LocalStorage) SetArtifact(id ArtifactID, e *Artifact) error {
 		return fmt.Errorf("failed to create artifact directory: %w", err)
 	}
 
+	s.artifactAccess.touch(id)
 	if len(e.CompilerOutput) == 0 {
 		if err := os.Remove(s.getCompilerOutputLocation(id)); err != nil && !os.IsNotExist(err) {
 			return err
@@ -157,6 +174,46 @@ func (s LocalStorage) SetArtifact(id ArtifactID, e *Artifact) error {
 	return os.WriteFile(s.getCompilerOutputLocation(id), e.CompilerOutput, perm)
 }
 
+// importArtifact saves artifact binary and compiler output received from another storage.
+func (s LocalStorage) importArtifact(id ArtifactID, binary io.Reader, compilerOutput []byte) error {
+	s.useLock.Lock()
+	defer s.useLock.Unlock()
+	s.dirty.Set()
+
+	if err := os.MkdirAll(s.binDir, perm); err != nil && !os.IsExist(err) {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	if len(compilerOutput) > 0 {
+		if err := os.WriteFile(s.getCompilerOutputLocation(id), compilerOutput, perm); err != nil {
+			return err
+		}
+	}
+
+	// Write to a temporary file first to not expose partially written binary to readers.
+	f, err := os.CreateTemp(s.binDir, id.Ext("tmp-*"))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, binary); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write artifact: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(f.Name(), s.getOutputLocation(id)); err != nil {
+		return err
+	}
+
+	s.artifactAccess.touch(id)
+	return nil
+}
+
 // CreateWorkspace implements storage interface
 func (s LocalStorage) CreateWorkspace(id ArtifactID, files map[string][]byte) (*Workspace, error) {
 	s.useLock.Lock()
@@ -177,6 +234,7 @@ func (s LocalStorage) CreateWorkspace(id ArtifactID, files map[string][]byte) (*
 	}
 
 	// Write entries
+	s.workspaceAccess.touch(id)
 	tmpSrcDir := filepath.Join(s.srcDir, id.String())
 	if err := os.MkdirAll(tmpSrcDir, perm); err != nil {
 		if !os.IsExist(err) {
@@ -225,6 +283,11 @@ func (s LocalStorage) CreateWorkspace(id ArtifactID, files map[string][]byte) (*
 	}, nil
 }
 
+// Stats returns artifact cache usage statistics.
+func (s LocalStorage) Stats() CacheStats {
+	return s.counters.stats()
+}
+
 func (s LocalStorage) clean() error {
 	if !s.dirty.IsSet() {
 		s.log.Debug("storage is not dirty, skipping")
@@ -268,7 +331,11 @@ func (s LocalStorage) Clean(ctx context.Context) error {
 		return err
 	}
 
-	return s.clean()
+	if s.policy.IsZero() {
+		return s.clean()
+	}
+
+	return s.evict(ctx)
 }
 
 func createParentDir(workDir, fileName string) error {
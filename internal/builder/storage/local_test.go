@@ -26,7 +26,7 @@ func TestLocalStorage_GetArtifact(t *testing.T) {
 	r := require.New(t)
 	testDir := getTestDir(t)
 	defer os.RemoveAll(testDir)
-	s, err := NewLocalStorage(zaptest.NewLogger(t), testDir)
+	s, err := NewLocalStorage(zaptest.NewLogger(t), testDir, EvictionPolicy{})
 	r.NoError(err, "failed to create test storage")
 	r.Falsef(s.dirty.IsSet(), "dirty flag is not false")
 
@@ -34,7 +34,7 @@ func TestLocalStorage_GetArtifact(t *testing.T) {
 		"test1.go":   []byte("foo"),
 		"foo/bar.go": []byte("bar"),
 	}
-	aid, err := GetArtifactID(entries)
+	aid, err := GetArtifactID(entries, ArtifactParams{})
 	require.NoError(t, err, "failed to create a test artifact ID")
 
 	// check not existing item
@@ -117,7 +117,7 @@ func TestLocalStorage_clean(t *testing.T) {
 	for n, c := range cases {
 		t.Run(n, func(t *testing.T) {
 			if c.store == nil {
-				store, err := NewLocalStorage(zaptest.NewLogger(t), c.dir)
+				store, err := NewLocalStorage(zaptest.NewLogger(t), c.dir, EvictionPolicy{})
 				require.NoError(t, err)
 				c.store = store
 			}
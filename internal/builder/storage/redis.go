@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "goplay:artifacts:"
+
+var _ BlobStore = (*RedisBlobStore)(nil)
+
+// RedisBlobStore stores blobs in Redis.
+//
+// Suitable for small deployments where artifacts fit into Redis memory.
+type RedisBlobStore struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+}
+
+// NewRedisBlobStore returns a new Redis blob store.
+//
+// Stored blobs expire after specified TTL. Zero TTL disables expiration.
+func NewRedisBlobStore(client redis.UniversalClient, ttl time.Duration) *RedisBlobStore {
+	return &RedisBlobStore{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func newRedisBlobStoreFromURL(u *url.URL) (*RedisBlobStore, error) {
+	// TTL is not a Redis client option and should be removed before parsing.
+	var ttl time.Duration
+	q := u.Query()
+	if v := q.Get("ttl"); v != "" {
+		var err error
+		ttl, err = time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis storage URL parameter %q: %w", "ttl", err)
+		}
+
+		q.Del("ttl")
+	}
+
+	connURL := *u
+	connURL.RawQuery = q.Encode()
+	opts, err := redis.ParseURL(connURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis storage URL: %w", err)
+	}
+
+	return NewRedisBlobStore(redis.NewClient(opts), ttl), nil
+}
+
+// GetBlob implements BlobStore interface.
+func (s *RedisBlobStore) GetBlob(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, err := s.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotExists
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// PutBlob implements BlobStore interface.
+func (s *RedisBlobStore) PutBlob(ctx context.Context, key string, r io.Reader, size int64) error {
+	buff := bytes.NewBuffer(make([]byte, 0, size))
+	if _, err := io.Copy(buff, r); err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, redisKeyPrefix+key, buff.Bytes(), s.ttl).Err()
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+var _ BlobStore = (*S3BlobStore)(nil)
+
+// S3Config is S3-compatible object storage connection config.
+type S3Config struct {
+	// Endpoint is storage host with optional port.
+	Endpoint string
+
+	// Region is bucket region. Optional for MinIO.
+	Region string
+
+	// Bucket is bucket name.
+	Bucket string
+
+	// Prefix is optional object key prefix.
+	Prefix string
+
+	// AccessKey is access key ID.
+	//
+	// Credentials are read from AWS_* and MINIO_* environment variables if empty.
+	AccessKey string
+
+	// SecretKey is secret access key.
+	SecretKey string
+
+	// Insecure disables TLS.
+	Insecure bool
+}
+
+// S3BlobStore stores blobs in S3-compatible object storage like AWS S3 or MinIO.
+type S3BlobStore struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3BlobStore returns a new S3-compatible blob store.
+func NewS3BlobStore(cfg S3Config) (*S3BlobStore, error) {
+	creds := credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.EnvMinio{},
+	})
+	if cfg.AccessKey != "" {
+		creds = credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        creds,
+		Secure:       !cfg.Insecure,
+		Region:       cfg.Region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return &S3BlobStore{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: cfg.Prefix,
+	}, nil
+}
+
+func newS3BlobStoreFromURL(u *url.URL) (*S3BlobStore, error) {
+	bucket, prefix, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if u.Host == "" || bucket == "" {
+		return nil, errors.New("S3 storage URL should contain endpoint and bucket name")
+	}
+
+	q := u.Query()
+	cfg := S3Config{
+		Endpoint:  u.Host,
+		Region:    q.Get("region"),
+		Bucket:    bucket,
+		Prefix:    prefix,
+		AccessKey: u.User.Username(),
+	}
+	cfg.SecretKey, _ = u.User.Password()
+
+	if v := q.Get("secure"); v != "" {
+		secure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid S3 storage URL parameter %q: %w", "secure", err)
+		}
+
+		cfg.Insecure = !secure
+	}
+
+	return NewS3BlobStore(cfg)
+}
+
+func (s *S3BlobStore) objectName(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+
+	return path.Join(s.prefix, key)
+}
+
+// GetBlob implements BlobStore interface.
+func (s *S3BlobStore) GetBlob(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, s.objectName(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Object is fetched lazily, stat forces request to check if object exists.
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		if minio.ToErrorResponse(err).Code == minio.NoSuchKey {
+			return nil, ErrNotExists
+		}
+
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// PutBlob implements BlobStore interface.
+func (s *S3BlobStore) PutBlob(ctx context.Context, key string, r io.Reader, size int64) error {
+	_, err := s.client.PutObject(ctx, s.bucket, s.objectName(key), r, size, minio.PutObjectOptions{
+		ContentType: "application/octet-stream",
+	})
+	return err
+}
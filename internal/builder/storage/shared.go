@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultBlobStoreTimeout is default timeout for shared blob store operations.
+const DefaultBlobStoreTimeout = 30 * time.Second
+
+var _ StoreProvider = (*SharedStorage)(nil)
+
+// SharedStorage is artifact storage shared between multiple server instances.
+//
+// Workspaces are created on a local disk while built artifacts are
+// uploaded to a shared blob store. Artifacts built by other instances
+// are downloaded and cached locally on first access.
+//
+// Shared storage doesn't remove artifacts from a blob store during cleanup,
+// expiration should be configured on a blob store side.
+type SharedStorage struct {
+	*LocalStorage
+
+	log     *zap.Logger
+	blobs   BlobStore
+	timeout time.Duration
+}
+
+// NewSharedStorage returns a new shared storage which uses local storage as a cache.
+func NewSharedStorage(log *zap.Logger, local *LocalStorage, blobs BlobStore) *SharedStorage {
+	return &SharedStorage{
+		LocalStorage: local,
+		log:          log.Named("storage.shared"),
+		blobs:        blobs,
+		timeout:      DefaultBlobStoreTimeout,
+	}
+}
+
+// GetArtifact implements StoreProvider interface.
+func (s *SharedStorage) GetArtifact(id ArtifactID) (*Artifact, error) {
+	artifact, err := s.LocalStorage.GetArtifact(id)
+	if !errors.Is(err, ErrNotExists) {
+		return artifact, err
+	}
+
+	if err := s.downloadArtifact(id); err != nil {
+		if !errors.Is(err, ErrNotExists) {
+			s.log.Error("failed to download artifact", zap.Stringer("artifact", id), zap.Error(err))
+		}
+
+		return nil, err
+	}
+
+	return s.LocalStorage.GetArtifact(id)
+}
+
+// SetArtifact implements StoreProvider interface.
+//
+// Artifact binary is expected to be already written into a workspace binary path.
+func (s *SharedStorage) SetArtifact(id ArtifactID, e *Artifact) error {
+	if err := s.LocalStorage.SetArtifact(id, e); err != nil {
+		return err
+	}
+
+	if err := s.uploadArtifact(id, e); err != nil {
+		s.log.Error("failed to upload artifact", zap.Stringer("artifact", id), zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s *SharedStorage) downloadArtifact(id ArtifactID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	binary, err := s.blobs.GetBlob(ctx, id.Ext(ExtWasm))
+	if err != nil {
+		return err
+	}
+
+	defer binary.Close()
+
+	var compilerOutput []byte
+	rc, err := s.blobs.GetBlob(ctx, id.Ext(extCompilerOutput))
+	switch {
+	case err == nil:
+		compilerOutput, err = io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read compiler output: %w", err)
+		}
+	case !errors.Is(err, ErrNotExists):
+		return err
+	}
+
+	s.log.Debug("downloaded artifact from shared storage", zap.Stringer("artifact", id))
+	return s.importArtifact(id, binary, compilerOutput)
+}
+
+func (s *SharedStorage) uploadArtifact(id ArtifactID, e *Artifact) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	// Compiler output is uploaded first to make sure that
+	// artifact becomes visible to other instances only when it's complete.
+	if len(e.CompilerOutput) > 0 {
+		err := s.blobs.PutBlob(ctx, id.Ext(extCompilerOutput), bytes.NewReader(e.CompilerOutput), int64(len(e.CompilerOutput)))
+		if err != nil {
+			return fmt.Errorf("failed to upload compiler output: %w", err)
+		}
+	}
+
+	f, err := os.Open(s.getOutputLocation(id))
+	if err != nil {
+		return fmt.Errorf("failed to open artifact: %w", err)
+	}
+
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if err := s.blobs.PutBlob(ctx, id.Ext(ExtWasm), f, stat.Size()); err != nil {
+		return fmt.Errorf("failed to upload artifact: %w", err)
+	}
+
+	return nil
+}
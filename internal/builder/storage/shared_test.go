@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestSharedStorage(t *testing.T) {
+	cases := map[string]struct {
+		storageURL func(t *testing.T) string
+	}{
+		"s3": {
+			storageURL: func(t *testing.T) string {
+				srv := httptest.NewServer(newFakeS3Server())
+				t.Cleanup(srv.Close)
+
+				u, _ := url.Parse(srv.URL)
+				return "s3://" + u.Host + "/artifacts/wasm?region=us-east-1&secure=false"
+			},
+		},
+		"redis": {
+			storageURL: func(t *testing.T) string {
+				srv := miniredis.RunT(t)
+				return "redis://" + srv.Addr() + "/0?ttl=1h"
+			},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			blobs, err := NewBlobStoreFromURL(c.storageURL(t))
+			require.NoError(t, err)
+
+			newInstance := func() *SharedStorage {
+				local, err := NewLocalStorage(zaptest.NewLogger(t), t.TempDir(), EvictionPolicy{})
+				require.NoError(t, err)
+				return NewSharedStorage(zaptest.NewLogger(t), local, blobs)
+			}
+
+			files := map[string][]byte{"main.go": []byte("package main")}
+			aid, err := GetArtifactID(files, ArtifactParams{})
+			require.NoError(t, err)
+
+			// Build artifact on one instance.
+			builder := newInstance()
+			ws, err := builder.CreateWorkspace(aid, files)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(ws.BinaryPath, []byte("wasm"), perm))
+			require.NoError(t, builder.SetArtifact(aid, &Artifact{CompilerOutput: []byte("escape analysis\n")}))
+
+			// Get artifact from other instance.
+			reader := newInstance()
+			_, err = reader.GetArtifact("missing")
+			require.ErrorIs(t, err, ErrNotExists)
+
+			for i := 0; i < 2; i++ {
+				artifact, err := reader.GetArtifact(aid)
+				require.NoError(t, err)
+
+				data, err := io.ReadAll(artifact.Contents)
+				require.NoError(t, err)
+				require.NoError(t, artifact.Contents.Close())
+				require.Equal(t, "wasm", string(data))
+				require.Equal(t, int64(len(data)), artifact.Contents.Size())
+				require.Equal(t, "escape analysis\n", string(artifact.CompilerOutput))
+			}
+		})
+	}
+}
+
+func TestNewBlobStoreFromURL(t *testing.T) {
+	cases := map[string]struct {
+		url string
+		err string
+	}{
+		"s3": {
+			url: "s3://key:secret@localhost:9000/bucket/prefix?secure=false",
+		},
+		"s3 without bucket": {
+			url: "s3://localhost:9000",
+			err: "S3 storage URL should contain endpoint and bucket name",
+		},
+		"redis": {
+			url: "redis://localhost:6379/1?ttl=1h",
+		},
+		"redis with invalid ttl": {
+			url: "redis://localhost:6379?ttl=foo",
+			err: `invalid Redis storage URL parameter "ttl"`,
+		},
+		"unsupported type": {
+			url: "ftp://localhost",
+			err: `unsupported storage type "ftp"`,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			_, err := NewBlobStoreFromURL(c.url)
+			if c.err != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+// fakeS3Server is a minimal in-memory S3-compatible server which supports only object get and put.
+type fakeS3Server struct {
+	lock    sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3Server() *fakeS3Server {
+	return &fakeS3Server{objects: map[string][]byte{}}
+}
+
+func (s *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := readS3Payload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.objects[r.URL.Path] = body
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		data, ok := s.objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			}
+			return
+		}
+
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// readS3Payload reads request body and decodes "aws-chunked" encoding if present.
+func readS3Payload(r *http.Request) ([]byte, error) {
+	if !strings.Contains(r.Header.Get("Content-Encoding"), "aws-chunked") &&
+		!strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+
+	var buff bytes.Buffer
+	br := bufio.NewReader(r.Body)
+	for {
+		header, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		sizeStr, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeStr, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		if size == 0 {
+			return buff.Bytes(), nil
+		}
+
+		if _, err := io.CopyN(&buff, br, size); err != nil {
+			return nil, err
+		}
+
+		// Skip chunk trailing CRLF.
+		if _, err := br.Discard(2); err != nil {
+			return nil, err
+		}
+	}
+}
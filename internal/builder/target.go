@@ -0,0 +1,54 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/x1unix/go-playground/pkg/util/osutil"
+)
+
+// BuildTarget is WebAssembly build target operating system (GOOS).
+type BuildTarget string
+
+const (
+	// TargetJS is js/wasm target.
+	//
+	// Programs use syscall/js and run in browser using wasm_exec.js glue code.
+	TargetJS BuildTarget = "js"
+
+	// TargetWASI is wasip1/wasm target.
+	//
+	// Programs use WASI Preview 1 system interface and can run in any WASI runtime.
+	TargetWASI BuildTarget = "wasip1"
+)
+
+// ParseBuildTarget parses build target name.
+//
+// TargetJS is returned if value is empty.
+func ParseBuildTarget(name string) (BuildTarget, error) {
+	switch t := BuildTarget(name); t {
+	case "":
+		return TargetJS, nil
+	case TargetJS, TargetWASI:
+		return t, nil
+	}
+
+	return "", fmt.Errorf("unsupported build target %q (allowed: %s, %s)", name, TargetJS, TargetWASI)
+}
+
+// environ returns build environment variables for a target.
+func (t BuildTarget) environ() osutil.EnvironmentVariables {
+	if t == "" {
+		t = TargetJS
+	}
+
+	return osutil.EnvironmentVariables{
+		"GOOS":   string(t),
+		"GOARCH": "wasm",
+	}
+}
+
+// buildEnv is Go tool environment used for a build.
+type buildEnv struct {
+	toolchain Toolchain
+	target    BuildTarget
+}
@@ -0,0 +1,217 @@
+package builder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const goVersionFile = "VERSION"
+
+// ErrUnsupportedGoVersion occurs when requested Go version is not available.
+var ErrUnsupportedGoVersion = errors.New("unsupported Go version")
+
+// Toolchain is Go toolchain used to build programs.
+type Toolchain struct {
+	// Version is Go version without "go" prefix.
+	Version string
+
+	// GOROOT is toolchain root directory.
+	//
+	// Empty value means that "go" binary from PATH is used.
+	GOROOT string
+}
+
+// HostToolchain returns toolchain which uses "go" binary from PATH.
+//
+// Version of Go used to build the server is reported as a toolchain version.
+func HostToolchain() Toolchain {
+	return Toolchain{Version: strings.TrimPrefix(runtime.Version(), "go")}
+}
+
+// LoadToolchain returns toolchain located at GOROOT directory.
+//
+// Toolchain version is read from VERSION file or using "go env" if file is missing.
+func LoadToolchain(ctx context.Context, goRoot string) (Toolchain, error) {
+	goRoot, err := filepath.Abs(goRoot)
+	if err != nil {
+		return Toolchain{}, err
+	}
+
+	t := Toolchain{GOROOT: goRoot}
+	version, err := readVersionFile(filepath.Join(goRoot, goVersionFile))
+	if err == nil && version != "" {
+		t.Version = version
+		return t, nil
+	}
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return t, fmt.Errorf("failed to read Go version from GOROOT %q: %w", goRoot, err)
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cmd := t.command(cmdCtx, "env", "GOVERSION")
+	buff := new(bytes.Buffer)
+	cmd.Stdout = buff
+	if err := cmd.Run(); err != nil {
+		return t, fmt.Errorf("failed to get Go version from GOROOT %q: %w", goRoot, err)
+	}
+
+	t.Version = strings.TrimPrefix(strings.TrimSpace(buff.String()), "go")
+	if t.Version == "" {
+		return t, fmt.Errorf("failed to get Go version from GOROOT %q: empty version", goRoot)
+	}
+
+	return t, nil
+}
+
+// readVersionFile reads Go version from the first line of VERSION file.
+func readVersionFile(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	if !s.Scan() {
+		return "", s.Err()
+	}
+
+	return strings.TrimPrefix(strings.TrimSpace(s.Text()), "go"), nil
+}
+
+// LanguageVersion returns Go language version (major and minor version).
+func (t Toolchain) LanguageVersion() string {
+	major, rest, _ := strings.Cut(t.Version, ".")
+	minor, _, _ := strings.Cut(rest, ".")
+
+	// Cut off pre-release suffix, e.g. "1.22rc1".
+	if i := strings.IndexFunc(minor, func(r rune) bool { return r < '0' || r > '9' }); i != -1 {
+		minor = minor[:i]
+	}
+
+	if minor == "" {
+		return major
+	}
+
+	return major + "." + minor
+}
+
+// goModVersion returns Go version for generated go.mod file.
+func (t Toolchain) goModVersion() string {
+	if t.GOROOT == "" {
+		return goVersion
+	}
+
+	return t.LanguageVersion()
+}
+
+// command returns a new Go tool command.
+func (t Toolchain) command(ctx context.Context, args ...string) *exec.Cmd {
+	if t.GOROOT == "" {
+		return newGoToolCommand(ctx, args...)
+	}
+
+	cmd := exec.CommandContext(ctx, filepath.Join(t.GOROOT, "bin", "go"), args...)
+	cmd.Env = append(os.Environ(), t.environ()...)
+	return cmd
+}
+
+// environ returns list of environment variables required to use a toolchain.
+func (t Toolchain) environ() []string {
+	if t.GOROOT == "" {
+		return nil
+	}
+
+	// Prevent automatic toolchain switch if go.mod requires newer Go version.
+	return []string{"GOROOT=" + t.GOROOT, "GOTOOLCHAIN=local"}
+}
+
+// Toolchains is a list of available Go toolchains.
+//
+// The first toolchain is used by default.
+type Toolchains []Toolchain
+
+// LoadToolchains loads toolchains from a list of GOROOT directories.
+//
+// Host toolchain is returned if list is empty.
+func LoadToolchains(ctx context.Context, goRoots []string) (Toolchains, error) {
+	if len(goRoots) == 0 {
+		return Toolchains{HostToolchain()}, nil
+	}
+
+	toolchains := make(Toolchains, 0, len(goRoots))
+	for _, goRoot := range goRoots {
+		t, err := LoadToolchain(ctx, goRoot)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := toolchains.Find(t.Version); err == nil {
+			return nil, fmt.Errorf("duplicate Go toolchain version %s (GOROOT: %q)", t.Version, t.GOROOT)
+		}
+
+		toolchains = append(toolchains, t)
+	}
+
+	return toolchains, nil
+}
+
+// Default returns default toolchain.
+func (l Toolchains) Default() Toolchain {
+	if len(l) == 0 {
+		return HostToolchain()
+	}
+
+	return l[0]
+}
+
+// Find returns toolchain by Go version.
+//
+// Version can be either full version or language version, e.g. "1.22".
+// Default toolchain is returned if version is empty.
+func (l Toolchains) Find(version string) (Toolchain, error) {
+	version = strings.TrimPrefix(version, "go")
+	if version == "" {
+		return l.Default(), nil
+	}
+
+	for _, t := range l {
+		if t.Version == version {
+			return t, nil
+		}
+	}
+
+	for _, t := range l {
+		if t.LanguageVersion() == version {
+			return t, nil
+		}
+	}
+
+	return Toolchain{}, fmt.Errorf("%w: %s", ErrUnsupportedGoVersion, version)
+}
+
+// Versions returns list of available Go versions.
+func (l Toolchains) Versions() []string {
+	if len(l) == 0 {
+		return []string{HostToolchain().Version}
+	}
+
+	versions := make([]string, 0, len(l))
+	for _, t := range l {
+		versions = append(versions, t.Version)
+	}
+
+	return versions
+}
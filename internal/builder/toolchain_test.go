@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadToolchains(t *testing.T) {
+	newGoRoot := func(t *testing.T, version string) string {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, goVersionFile), []byte(version), 0644))
+		return dir
+	}
+
+	cases := map[string]struct {
+		goRoots   func(t *testing.T) []string
+		expect    func(goRoots []string) Toolchains
+		expectErr string
+	}{
+		"use host toolchain by default": {
+			goRoots: func(t *testing.T) []string {
+				return nil
+			},
+			expect: func(_ []string) Toolchains {
+				return Toolchains{HostToolchain()}
+			},
+		},
+		"read version file": {
+			goRoots: func(t *testing.T) []string {
+				return []string{
+					newGoRoot(t, "go1.23.4\ntime 2024-12-03T22:11:27Z\n"),
+					newGoRoot(t, "go1.22.10"),
+				}
+			},
+			expect: func(goRoots []string) Toolchains {
+				return Toolchains{
+					{Version: "1.23.4", GOROOT: goRoots[0]},
+					{Version: "1.22.10", GOROOT: goRoots[1]},
+				}
+			},
+		},
+		"reject duplicates": {
+			goRoots: func(t *testing.T) []string {
+				return []string{newGoRoot(t, "go1.23.4"), newGoRoot(t, "go1.23.4")}
+			},
+			expectErr: "duplicate Go toolchain version 1.23.4",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			goRoots := c.goRoots(t)
+			got, err := LoadToolchains(context.Background(), goRoots)
+			if c.expectErr != "" {
+				require.ErrorContains(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expect(goRoots), got)
+		})
+	}
+}
+
+func TestToolchains_Find(t *testing.T) {
+	toolchains := Toolchains{
+		{Version: "1.23.4", GOROOT: "/opt/go1.23"},
+		{Version: "1.22.10", GOROOT: "/opt/go1.22"},
+		{Version: "1.24rc1", GOROOT: "/opt/go1.24"},
+	}
+
+	cases := map[string]struct {
+		version   string
+		expect    string
+		expectErr string
+	}{
+		"default":           {version: "", expect: "1.23.4"},
+		"full version":      {version: "1.22.10", expect: "1.22.10"},
+		"go prefix":         {version: "go1.22.10", expect: "1.22.10"},
+		"language version":  {version: "1.22", expect: "1.22.10"},
+		"release candidate": {version: "1.24", expect: "1.24rc1"},
+		"unsupported":       {version: "1.21", expectErr: "unsupported Go version: 1.21"},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, err := toolchains.Find(c.version)
+			if c.expectErr != "" {
+				require.ErrorIs(t, err, ErrUnsupportedGoVersion)
+				require.EqualError(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expect, got.Version)
+		})
+	}
+
+	require.Equal(t, []string{"1.23.4", "1.22.10", "1.24rc1"}, toolchains.Versions())
+}
@@ -25,6 +25,6 @@ func parseGoVersion(input string) string {
 	return ""
 }
 
-func generateGoMod(modName string) []byte {
+func generateGoMod(modName, goVersion string) []byte {
 	return []byte("module " + modName + "\ngo " + goVersion)
 }
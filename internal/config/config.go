@@ -1,15 +1,21 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"net/netip"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
 	"github.com/x1unix/go-playground/internal/announcements"
+	"github.com/x1unix/go-playground/internal/formatter"
+	"github.com/x1unix/go-playground/internal/snippets"
 	"github.com/x1unix/go-playground/pkg/goplay"
+	"github.com/x1unix/go-playground/pkg/goproxy"
 	"github.com/x1unix/go-playground/pkg/util/cmdutil"
 )
 
@@ -19,6 +25,25 @@ const (
 	DefaultIdleTimeout    = 90 * time.Second
 	DefaultGoBuildTimeout = 40 * time.Second
 	DefaultCleanInterval  = 10 * time.Minute
+	DefaultCacheMaxAge    = time.Hour
+
+	DefaultMaxParallelBuilds    = 4
+	DefaultBuildQueueSize       = 32
+	DefaultClientBuildQueueSize = 2
+
+	DefaultMetricsPath = "/metrics"
+
+	DefaultSandboxRunTimeout    = 10 * time.Second
+	DefaultSandboxMaxMemory     = 256 * 1024 * 1024
+	DefaultSandboxMaxOutputSize = 1024 * 1024
+	DefaultSandboxUID           = 65534
+	DefaultSandboxGID           = 65534
+
+	DefaultWasmRunTimeout       = 10 * time.Second
+	DefaultWasmRunMaxMemory     = 256 * 1024 * 1024
+	DefaultWasmRunMaxOutputSize = 1024 * 1024
+
+	DefaultModulesCacheTTL = 15 * time.Minute
 )
 
 type HTTPConfig struct {
@@ -36,6 +61,40 @@ type HTTPConfig struct {
 
 	// IdleTimeout is delay timeout between requests to keep connection alive.
 	IdleTimeout time.Duration `envconfig:"HTTP_IDLE_TIMEOUT"`
+
+	// TrustedProxies is a list of IP addresses or CIDR networks of reverse proxies
+	// allowed to set client address headers (X-Forwarded-For, X-Real-IP).
+	//
+	// Client address headers are ignored if list is empty.
+	TrustedProxies []string `envconfig:"APP_TRUSTED_PROXIES" json:"trustedProxies"`
+}
+
+// TrustedProxyNetworks returns parsed list of trusted proxy networks.
+//
+// IP address without a prefix length is treated as a single host network.
+func (cfg HTTPConfig) TrustedProxyNetworks() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(cfg.TrustedProxies))
+	for _, v := range cfg.TrustedProxies {
+		if !strings.Contains(v, "/") {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy address %q: %w", v, err)
+			}
+
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy network %q: %w", v, err)
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
 }
 
 func (cfg *HTTPConfig) mountFlagSet(f *flag.FlagSet) {
@@ -49,6 +108,7 @@ func (cfg *HTTPConfig) mountFlagSet(f *flag.FlagSet) {
 	f.DurationVar(&cfg.WriteTimeout, "http-write-timeout", DefaultWriteTimeout, "HTTP response write timeout")
 	f.DurationVar(&cfg.ReadTimeout, "http-read-timeout", DefaultReadTimeout, "HTTP request read timeout")
 	f.DurationVar(&cfg.IdleTimeout, "http-idle-timeout", DefaultIdleTimeout, "HTTP keep alive timeout")
+	f.Var(cmdutil.NewStringsListValue(&cfg.TrustedProxies), "trusted-proxies", "Comma-separated list of trusted reverse proxy addresses or CIDR networks")
 }
 
 type PlaygroundConfig struct {
@@ -71,6 +131,23 @@ type BuildConfig struct {
 	// CleanupInterval is WebAssembly build artifact cache clean interval
 	CleanupInterval time.Duration `envconfig:"APP_CLEAN_INTERVAL" json:"cleanupInterval"`
 
+	// CacheMaxAge is max time since WebAssembly artifact last access before it's evicted from cache.
+	CacheMaxAge time.Duration `envconfig:"APP_CACHE_MAX_AGE" json:"cacheMaxAge"`
+
+	// CacheMaxSize is max total size of cached WebAssembly artifacts in bytes.
+	//
+	// Least recently used artifacts are evicted first. Zero value disables limit.
+	CacheMaxSize int64 `envconfig:"APP_CACHE_MAX_SIZE" json:"cacheMaxSize"`
+
+	// MaxParallelBuilds is max number of concurrently running Go builds.
+	MaxParallelBuilds int `envconfig:"APP_MAX_PARALLEL_BUILDS" json:"maxParallelBuilds"`
+
+	// BuildQueueSize is max number of builds waiting for a free build slot.
+	BuildQueueSize int `envconfig:"APP_BUILD_QUEUE_SIZE" json:"buildQueueSize"`
+
+	// ClientBuildQueueSize is max number of queued builds per client IP.
+	ClientBuildQueueSize int `envconfig:"APP_CLIENT_BUILD_QUEUE_SIZE" json:"clientBuildQueueSize"`
+
 	// GoBuildTimeout is Go program build timeout.
 	GoBuildTimeout time.Duration `envconfig:"APP_GO_BUILD_TIMEOUT" json:"goBuildTimeout"`
 
@@ -82,14 +159,157 @@ type BuildConfig struct {
 	//
 	// Empty value disables environment variable filter.
 	BypassEnvVarsList []string `envconfig:"APP_PERMIT_ENV_VARS" json:"bypassEnvVarsList"`
+
+	// ArtifactStorageURL is optional shared WebAssembly artifact storage connection URL.
+	//
+	// Used to share build artifacts between multiple server instances.
+	// Supported storage types are S3-compatible object storage ("s3://") and Redis ("redis://").
+	//
+	// Value is excluded from JSON as it may contain credentials.
+	ArtifactStorageURL string `envconfig:"APP_ARTIFACT_STORAGE_URL" json:"-"`
+
+	// GoRoots is a list of Go toolchain root directories used to build WebAssembly programs.
+	//
+	// The first toolchain is used by default. Go from PATH is used if list is empty.
+	GoRoots []string `envconfig:"APP_GO_TOOLCHAINS" json:"goRoots"`
 }
 
 func (cfg *BuildConfig) mountFlagSet(f *flag.FlagSet) {
 	f.StringVar(&cfg.BuildDir, "wasm-build-dir", os.TempDir(), "Directory for WASM builds")
 	f.BoolVar(&cfg.SkipModuleCleanup, "skip-mod-clean", false, "Skip Go module cache cleanup")
 	f.DurationVar(&cfg.CleanupInterval, "clean-interval", DefaultCleanInterval, "Build directory cleanup interval")
+	f.DurationVar(&cfg.CacheMaxAge, "cache-max-age", DefaultCacheMaxAge, "Max time since WASM artifact last access before eviction")
+	f.Int64Var(&cfg.CacheMaxSize, "cache-max-size", 0, "Max total size of cached WASM artifacts in bytes (0 - unlimited)")
+	f.IntVar(&cfg.MaxParallelBuilds, "max-parallel-builds", DefaultMaxParallelBuilds, "Max number of concurrent Go builds")
+	f.IntVar(&cfg.BuildQueueSize, "build-queue-size", DefaultBuildQueueSize, "Max number of Go builds waiting in a queue")
+	f.IntVar(&cfg.ClientBuildQueueSize, "client-build-queue-size", DefaultClientBuildQueueSize, "Max number of queued Go builds per client")
 	f.DurationVar(&cfg.GoBuildTimeout, "go-build-timeout", DefaultGoBuildTimeout, "Go program build timeout.")
 	f.Var(cmdutil.NewStringsListValue(&cfg.BypassEnvVarsList), "permit-env-vars", "Comma-separated allow list of environment variables passed to Go compiler tool")
+	f.StringVar(&cfg.ArtifactStorageURL, "artifact-storage-url", "", "Shared WebAssembly artifact storage URL (s3:// or redis://)")
+	f.Var(cmdutil.NewStringsListValue(&cfg.GoRoots), "go-toolchains", "Comma-separated list of GOROOT directories of Go toolchains used for WASM builds")
+}
+
+type SandboxConfig struct {
+	// Enabled enables "local" run backend which builds and runs programs on a server.
+	Enabled bool `envconfig:"APP_SANDBOX_ENABLED" json:"enabled"`
+
+	// RunTimeout is max program execution time.
+	RunTimeout time.Duration `envconfig:"APP_SANDBOX_RUN_TIMEOUT" json:"runTimeout"`
+
+	// MaxMemory is max program heap size in bytes.
+	MaxMemory uint64 `envconfig:"APP_SANDBOX_MAX_MEMORY" json:"maxMemory"`
+
+	// MaxOutputSize is max program output size in bytes.
+	MaxOutputSize int `envconfig:"APP_SANDBOX_MAX_OUTPUT" json:"maxOutputSize"`
+
+	// UID is unprivileged user ID used to run programs.
+	UID int `envconfig:"APP_SANDBOX_UID" json:"uid"`
+
+	// GID is unprivileged group ID used to run programs.
+	GID int `envconfig:"APP_SANDBOX_GID" json:"gid"`
+}
+
+func (cfg *SandboxConfig) mountFlagSet(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "sandbox", false, "Enable local run backend which executes programs on a server")
+	f.DurationVar(&cfg.RunTimeout, "sandbox-run-timeout", DefaultSandboxRunTimeout, "Local backend program execution timeout")
+	f.Uint64Var(&cfg.MaxMemory, "sandbox-max-memory", DefaultSandboxMaxMemory, "Local backend program memory limit in bytes")
+	f.IntVar(&cfg.MaxOutputSize, "sandbox-max-output", DefaultSandboxMaxOutputSize, "Local backend program output size limit in bytes")
+	f.IntVar(&cfg.UID, "sandbox-uid", DefaultSandboxUID, "Unprivileged user ID used to run programs on local backend")
+	f.IntVar(&cfg.GID, "sandbox-gid", DefaultSandboxGID, "Unprivileged group ID used to run programs on local backend")
+}
+
+type WasmRunConfig struct {
+	// Enabled enables "wasm-server" run backend which runs WebAssembly programs on a server.
+	Enabled bool `envconfig:"APP_WASM_RUN_ENABLED" json:"enabled"`
+
+	// RunTimeout is max program execution time.
+	RunTimeout time.Duration `envconfig:"APP_WASM_RUN_TIMEOUT" json:"runTimeout"`
+
+	// MaxMemory is max program linear memory size in bytes.
+	MaxMemory uint64 `envconfig:"APP_WASM_RUN_MAX_MEMORY" json:"maxMemory"`
+
+	// MaxOutputSize is max program output size in bytes.
+	MaxOutputSize int `envconfig:"APP_WASM_RUN_MAX_OUTPUT" json:"maxOutputSize"`
+}
+
+func (cfg *WasmRunConfig) mountFlagSet(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "wasm-run", false, "Enable wasm-server run backend which executes WebAssembly programs on a server")
+	f.DurationVar(&cfg.RunTimeout, "wasm-run-timeout", DefaultWasmRunTimeout, "WebAssembly program execution timeout on a server")
+	f.Uint64Var(&cfg.MaxMemory, "wasm-run-max-memory", DefaultWasmRunMaxMemory, "WebAssembly program memory limit in bytes")
+	f.IntVar(&cfg.MaxOutputSize, "wasm-run-max-output", DefaultWasmRunMaxOutputSize, "WebAssembly program output size limit in bytes")
+}
+
+type FormatConfig struct {
+	// Provider is default code formatter.
+	//
+	// Code is formatted by Go Playground by default.
+	Provider string `envconfig:"APP_FORMATTER" json:"provider"`
+
+	// StdlibIndexFile is path to Go packages index file used by local formatter to resolve missing imports.
+	//
+	// "data/go-index.json" file from assets directory is used by default.
+	StdlibIndexFile string `envconfig:"APP_FORMAT_INDEX_FILE" json:"stdlibIndexFile"`
+
+	// LocalPrefix is default comma-separated list of local import path prefixes for goimports style.
+	//
+	// Imports of local packages are put into a separate group after third-party imports.
+	LocalPrefix string `envconfig:"APP_FORMAT_LOCAL_PREFIX" json:"localPrefix"`
+}
+
+func (cfg *FormatConfig) mountFlagSet(f *flag.FlagSet) {
+	f.StringVar(&cfg.Provider, "formatter", formatter.ProviderUpstream, "Default code formatter (upstream or local)")
+	f.StringVar(&cfg.StdlibIndexFile, "format-index-file", "", "Path to Go packages index file used by local formatter")
+	f.StringVar(&cfg.LocalPrefix, "format-local-prefix", "", "Comma-separated list of local import path prefixes for goimports style")
+}
+
+type MetricsConfig struct {
+	// Enabled enables Prometheus metrics endpoint.
+	Enabled bool `envconfig:"APP_METRICS_ENABLED" json:"enabled"`
+
+	// Path is metrics endpoint HTTP path.
+	Path string `envconfig:"APP_METRICS_PATH" json:"path"`
+}
+
+func (cfg *MetricsConfig) mountFlagSet(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "metrics", false, "Enable Prometheus metrics endpoint")
+	f.StringVar(&cfg.Path, "metrics-path", DefaultMetricsPath, "Prometheus metrics endpoint path")
+}
+
+type SnippetsConfig struct {
+	// Store is snippet store type.
+	//
+	// Snippets are proxied to Go Playground by default.
+	Store string `envconfig:"APP_SNIPPET_STORE" json:"store"`
+
+	// Dir is directory to keep snippets for local snippet store.
+	Dir string `envconfig:"APP_SNIPPET_DIR" json:"dir"`
+}
+
+func (cfg *SnippetsConfig) mountFlagSet(f *flag.FlagSet) {
+	f.StringVar(&cfg.Store, "snippet-store", snippets.StoreTypePlayground, "Snippet store type (playground or local)")
+	f.StringVar(&cfg.Dir, "snippet-dir", "", "Directory to keep snippets for local snippet store")
+}
+
+type ModulesConfig struct {
+	// ProxyURL is Go modules proxy URL used to index third-party modules.
+	//
+	// Accepts a list of URLs in GOPROXY environment variable format.
+	ProxyURL string `envconfig:"APP_GOPROXY_URL" json:"proxyUrl"`
+
+	// Private is a comma-separated list of glob patterns of private module path prefixes.
+	//
+	// Uses the same format as GONOSUMDB environment variable.
+	// Private modules are never requested from proxies.
+	Private string `envconfig:"APP_GOPRIVATE" json:"private"`
+
+	// CacheTTL is module versions cache expiration interval.
+	CacheTTL time.Duration `envconfig:"APP_GOPROXY_CACHE_TTL" json:"cacheTTL"`
+}
+
+func (cfg *ModulesConfig) mountFlagSet(f *flag.FlagSet) {
+	f.StringVar(&cfg.ProxyURL, "goproxy-url", goproxy.DefaultProxyURL, "Go modules proxy URL list in GOPROXY format")
+	f.StringVar(&cfg.Private, "goprivate", "", "Comma-separated list of private module path patterns in GONOSUMDB format")
+	f.DurationVar(&cfg.CacheTTL, "goproxy-cache-ttl", DefaultModulesCacheTTL, "Module versions cache expiration interval")
 }
 
 type ServicesConfig struct {
@@ -114,6 +334,12 @@ type Config struct {
 	HTTP       HTTPConfig       `json:"http"`
 	Playground PlaygroundConfig `json:"playground"`
 	Build      BuildConfig      `json:"build"`
+	Sandbox    SandboxConfig    `json:"sandbox"`
+	WasmRun    WasmRunConfig    `json:"wasmRun"`
+	Format     FormatConfig     `json:"format"`
+	Metrics    MetricsConfig    `json:"metrics"`
+	Snippets   SnippetsConfig   `json:"snippets"`
+	Modules    ModulesConfig    `json:"modules"`
 	Log        LogConfig        `json:"log"`
 	Services   ServicesConfig   `json:"services"`
 	Misc       MiscConfig       `json:"misc"`
@@ -121,6 +347,10 @@ type Config struct {
 
 // Validate validates a config and returns error if config is invalid.
 func (cfg Config) Validate() error {
+	if _, err := cfg.HTTP.TrustedProxyNetworks(); err != nil {
+		return err
+	}
+
 	if cfg.Build.GoBuildTimeout > cfg.HTTP.WriteTimeout {
 		return fmt.Errorf(
 			"go build timeout (%s) exceeds HTTP response timeout (%s)",
@@ -128,6 +358,38 @@ func (cfg Config) Validate() error {
 		)
 	}
 
+	if cfg.Sandbox.Enabled {
+		runTimeout := cfg.Build.GoBuildTimeout + cfg.Sandbox.RunTimeout
+		if runTimeout > cfg.HTTP.WriteTimeout {
+			return fmt.Errorf(
+				"sandbox build and run timeout (%s) exceeds HTTP response timeout (%s)",
+				runTimeout, cfg.HTTP.WriteTimeout,
+			)
+		}
+	}
+
+	if cfg.WasmRun.Enabled {
+		runTimeout := cfg.Build.GoBuildTimeout + cfg.WasmRun.RunTimeout
+		if runTimeout > cfg.HTTP.WriteTimeout {
+			return fmt.Errorf(
+				"WebAssembly build and run timeout (%s) exceeds HTTP response timeout (%s)",
+				runTimeout, cfg.HTTP.WriteTimeout,
+			)
+		}
+	}
+
+	if cfg.Format.Provider != "" && !formatter.ValidateProvider(cfg.Format.Provider) {
+		return fmt.Errorf("unsupported formatter %q", cfg.Format.Provider)
+	}
+
+	if cfg.Snippets.Store != "" && !snippets.ValidateStoreType(cfg.Snippets.Store) {
+		return fmt.Errorf("unsupported snippet store type %q", cfg.Snippets.Store)
+	}
+
+	if cfg.Snippets.Store == snippets.StoreTypeLocal && cfg.Snippets.Dir == "" {
+		return errors.New("snippet directory is required for local snippet store")
+	}
+
 	return nil
 }
 
@@ -138,6 +400,12 @@ func FromFlagSet(f *flag.FlagSet) *Config {
 	cfg.HTTP.mountFlagSet(f)
 	cfg.Playground.mountFlagSet(f)
 	cfg.Build.mountFlagSet(f)
+	cfg.Sandbox.mountFlagSet(f)
+	cfg.WasmRun.mountFlagSet(f)
+	cfg.Format.mountFlagSet(f)
+	cfg.Metrics.mountFlagSet(f)
+	cfg.Snippets.mountFlagSet(f)
+	cfg.Modules.mountFlagSet(f)
 	cfg.Log.mountFlagSet(f)
 	cfg.Services.mountFlagSet(f)
 	return &cfg
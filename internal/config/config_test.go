@@ -14,22 +14,62 @@ import (
 func TestFromFlags(t *testing.T) {
 	expect := Config{
 		HTTP: HTTPConfig{
-			Addr:         "testaddr",
-			AssetsDir:    "testdir",
-			ReadTimeout:  7 * time.Second,
-			WriteTimeout: 6 * time.Second,
-			IdleTimeout:  3 * time.Second,
+			Addr:           "testaddr",
+			AssetsDir:      "testdir",
+			ReadTimeout:    7 * time.Second,
+			WriteTimeout:   6 * time.Second,
+			IdleTimeout:    3 * time.Second,
+			TrustedProxies: []string{"10.0.0.0/8", "127.0.0.1"},
 		},
 		Playground: PlaygroundConfig{
 			PlaygroundURL:  "pgurl",
 			ConnectTimeout: 2 * time.Hour,
 		},
 		Build: BuildConfig{
-			BuildDir:          "builddir",
-			CleanupInterval:   1 * time.Hour,
-			BypassEnvVarsList: []string{"FOO", "BAR"},
-			SkipModuleCleanup: true,
-			GoBuildTimeout:    4 * time.Second,
+			BuildDir:             "builddir",
+			CleanupInterval:      1 * time.Hour,
+			CacheMaxAge:          30 * time.Minute,
+			CacheMaxSize:         1024,
+			MaxParallelBuilds:    2,
+			BuildQueueSize:       8,
+			ClientBuildQueueSize: 1,
+			BypassEnvVarsList:    []string{"FOO", "BAR"},
+			SkipModuleCleanup:    true,
+			GoBuildTimeout:       4 * time.Second,
+			ArtifactStorageURL:   "redis://localhost:6379",
+			GoRoots:              []string{"/opt/go1.23", "/opt/go1.22"},
+		},
+		Sandbox: SandboxConfig{
+			Enabled:       true,
+			RunTimeout:    5 * time.Second,
+			MaxMemory:     1024,
+			MaxOutputSize: 2048,
+			UID:           1000,
+			GID:           1001,
+		},
+		WasmRun: WasmRunConfig{
+			Enabled:       true,
+			RunTimeout:    3 * time.Second,
+			MaxMemory:     4096,
+			MaxOutputSize: 512,
+		},
+		Format: FormatConfig{
+			Provider:        "local",
+			StdlibIndexFile: "go-index.json",
+			LocalPrefix:     "example.com/foo",
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/prom",
+		},
+		Snippets: SnippetsConfig{
+			Store: "local",
+			Dir:   "snippetdir",
+		},
+		Modules: ModulesConfig{
+			ProxyURL: "http://goproxy",
+			Private:  "*.corp.example.com",
+			CacheTTL: 5 * time.Minute,
 		},
 		Services: ServicesConfig{GoogleAnalyticsID: "GA-123456"},
 		Log: LogConfig{
@@ -50,7 +90,21 @@ func TestFromFlags(t *testing.T) {
 		"-timeout=2h",
 		"-wasm-build-dir=builddir",
 		"-clean-interval=1h",
+		"-cache-max-age=30m",
+		"-cache-max-size=1024",
+		"-max-parallel-builds=2",
+		"-build-queue-size=8",
+		"-client-build-queue-size=1",
 		"-permit-env-vars=FOO,BAR",
+		"-artifact-storage-url=redis://localhost:6379",
+		"-go-toolchains=/opt/go1.23,/opt/go1.22",
+		"-metrics",
+		"-metrics-path=/prom",
+		"-snippet-store=local",
+		"-snippet-dir=snippetdir",
+		"-goproxy-url=http://goproxy",
+		"-goprivate=*.corp.example.com",
+		"-goproxy-cache-ttl=5m",
 		"-gtag-id=GA-123456",
 		"-debug",
 		"-log-level=warn",
@@ -61,7 +115,21 @@ func TestFromFlags(t *testing.T) {
 		"-http-read-timeout=7s",
 		"-http-write-timeout=6s",
 		"-http-idle-timeout=3s",
+		"-trusted-proxies=10.0.0.0/8,127.0.0.1",
 		"-go-build-timeout=4s",
+		"-sandbox",
+		"-sandbox-run-timeout=5s",
+		"-sandbox-max-memory=1024",
+		"-sandbox-max-output=2048",
+		"-sandbox-uid=1000",
+		"-sandbox-gid=1001",
+		"-wasm-run",
+		"-wasm-run-timeout=3s",
+		"-wasm-run-max-memory=4096",
+		"-wasm-run-max-output=512",
+		"-formatter=local",
+		"-format-index-file=go-index.json",
+		"-format-local-prefix=example.com/foo",
 	}
 
 	fl := flag.NewFlagSet("app", flag.PanicOnError)
@@ -117,22 +185,62 @@ func TestFromEnv(t *testing.T) {
 		"process environment variables": {
 			expect: Config{
 				HTTP: HTTPConfig{
-					Addr:         "testaddr",
-					AssetsDir:    "testdir",
-					ReadTimeout:  21 * time.Second,
-					WriteTimeout: 22 * time.Second,
-					IdleTimeout:  23 * time.Second,
+					Addr:           "testaddr",
+					AssetsDir:      "testdir",
+					ReadTimeout:    21 * time.Second,
+					WriteTimeout:   22 * time.Second,
+					IdleTimeout:    23 * time.Second,
+					TrustedProxies: []string{"172.16.0.0/12"},
 				},
 				Playground: PlaygroundConfig{
 					PlaygroundURL:  "pgurl",
 					ConnectTimeout: 2 * time.Hour,
 				},
 				Build: BuildConfig{
-					BuildDir:          "builddir",
-					CleanupInterval:   1 * time.Hour,
-					BypassEnvVarsList: []string{"FOO", "BAR"},
-					SkipModuleCleanup: true,
-					GoBuildTimeout:    time.Hour,
+					BuildDir:             "builddir",
+					CleanupInterval:      1 * time.Hour,
+					CacheMaxAge:          2 * time.Hour,
+					CacheMaxSize:         2048,
+					MaxParallelBuilds:    3,
+					BuildQueueSize:       16,
+					ClientBuildQueueSize: 4,
+					BypassEnvVarsList:    []string{"FOO", "BAR"},
+					SkipModuleCleanup:    true,
+					GoBuildTimeout:       time.Hour,
+					ArtifactStorageURL:   "s3://minio:9000/artifacts",
+					GoRoots:              []string{"/usr/local/go", "/opt/go1.22"},
+				},
+				Sandbox: SandboxConfig{
+					Enabled:       true,
+					RunTimeout:    2 * time.Second,
+					MaxMemory:     1024,
+					MaxOutputSize: 2048,
+					UID:           1000,
+					GID:           1001,
+				},
+				WasmRun: WasmRunConfig{
+					Enabled:       true,
+					RunTimeout:    time.Second,
+					MaxMemory:     4096,
+					MaxOutputSize: 512,
+				},
+				Format: FormatConfig{
+					Provider:        "local",
+					StdlibIndexFile: "/opt/go-index.json",
+					LocalPrefix:     "example.com/bar",
+				},
+				Metrics: MetricsConfig{
+					Enabled: true,
+					Path:    "/metrics",
+				},
+				Snippets: SnippetsConfig{
+					Store: "local",
+					Dir:   "/var/lib/snippets",
+				},
+				Modules: ModulesConfig{
+					ProxyURL: "http://goproxy|https://proxy.golang.org",
+					Private:  "example.com/private",
+					CacheTTL: time.Hour,
 				},
 				Services: ServicesConfig{GoogleAnalyticsID: "GA-123456"},
 				Log: LogConfig{
@@ -147,25 +255,53 @@ func TestFromEnv(t *testing.T) {
 				},
 			},
 			env: map[string]string{
-				"APP_HTTP_ADDR":           "testaddr",
-				"APP_ASSETS_DIR":          "testdir",
-				"APP_PLAYGROUND_URL":      "pgurl",
-				"APP_PLAYGROUND_TIMEOUT":  "2h",
-				"APP_BUILD_DIR":           "builddir",
-				"APP_CLEAN_INTERVAL":      "1h",
-				"APP_PERMIT_ENV_VARS":     "FOO,BAR",
-				"APP_GTAG_ID":             "GA-123456",
-				"APP_DEBUG":               "1",
-				"APP_LOG_LEVEL":           "warn",
-				"APP_LOG_FORMAT":          "console",
-				"APP_SKIP_MOD_CLEANUP":    "true",
-				"SENTRY_DSN":              "testdsn",
-				"SENTRY_USE_BREADCRUMBS":  "1",
-				"SENTRY_BREADCRUMB_LEVEL": "debug",
-				"HTTP_READ_TIMEOUT":       "21s",
-				"HTTP_WRITE_TIMEOUT":      "22s",
-				"HTTP_IDLE_TIMEOUT":       "23s",
-				"APP_GO_BUILD_TIMEOUT":    "1h",
+				"APP_HTTP_ADDR":               "testaddr",
+				"APP_ASSETS_DIR":              "testdir",
+				"APP_PLAYGROUND_URL":          "pgurl",
+				"APP_PLAYGROUND_TIMEOUT":      "2h",
+				"APP_BUILD_DIR":               "builddir",
+				"APP_CLEAN_INTERVAL":          "1h",
+				"APP_CACHE_MAX_AGE":           "2h",
+				"APP_CACHE_MAX_SIZE":          "2048",
+				"APP_MAX_PARALLEL_BUILDS":     "3",
+				"APP_BUILD_QUEUE_SIZE":        "16",
+				"APP_CLIENT_BUILD_QUEUE_SIZE": "4",
+				"APP_PERMIT_ENV_VARS":         "FOO,BAR",
+				"APP_ARTIFACT_STORAGE_URL":    "s3://minio:9000/artifacts",
+				"APP_GO_TOOLCHAINS":           "/usr/local/go,/opt/go1.22",
+				"APP_GTAG_ID":                 "GA-123456",
+				"APP_DEBUG":                   "1",
+				"APP_LOG_LEVEL":               "warn",
+				"APP_LOG_FORMAT":              "console",
+				"APP_SKIP_MOD_CLEANUP":        "true",
+				"SENTRY_DSN":                  "testdsn",
+				"SENTRY_USE_BREADCRUMBS":      "1",
+				"SENTRY_BREADCRUMB_LEVEL":     "debug",
+				"HTTP_READ_TIMEOUT":           "21s",
+				"HTTP_WRITE_TIMEOUT":          "22s",
+				"HTTP_IDLE_TIMEOUT":           "23s",
+				"APP_TRUSTED_PROXIES":         "172.16.0.0/12",
+				"APP_GO_BUILD_TIMEOUT":        "1h",
+				"APP_SANDBOX_ENABLED":         "true",
+				"APP_SANDBOX_RUN_TIMEOUT":     "2s",
+				"APP_SANDBOX_MAX_MEMORY":      "1024",
+				"APP_SANDBOX_MAX_OUTPUT":      "2048",
+				"APP_SANDBOX_UID":             "1000",
+				"APP_SANDBOX_GID":             "1001",
+				"APP_WASM_RUN_ENABLED":        "true",
+				"APP_WASM_RUN_TIMEOUT":        "1s",
+				"APP_WASM_RUN_MAX_MEMORY":     "4096",
+				"APP_WASM_RUN_MAX_OUTPUT":     "512",
+				"APP_FORMATTER":               "local",
+				"APP_FORMAT_INDEX_FILE":       "/opt/go-index.json",
+				"APP_FORMAT_LOCAL_PREFIX":     "example.com/bar",
+				"APP_METRICS_ENABLED":         "true",
+				"APP_METRICS_PATH":            "/metrics",
+				"APP_SNIPPET_STORE":           "local",
+				"APP_SNIPPET_DIR":             "/var/lib/snippets",
+				"APP_GOPROXY_URL":             "http://goproxy|https://proxy.golang.org",
+				"APP_GOPRIVATE":               "example.com/private",
+				"APP_GOPROXY_CACHE_TTL":       "1h",
 			},
 		},
 		"parse announcements": {
@@ -232,6 +368,80 @@ func TestConfig_Validate(t *testing.T) {
 				}
 			},
 		},
+		"sandbox timeout": {
+			expectErr: fmt.Sprintf(
+				"sandbox build and run timeout (%s) exceeds HTTP response timeout (%s)",
+				3*time.Second, 2*time.Second,
+			),
+			cfg: func(_ *testing.T) Config {
+				return Config{
+					Build: BuildConfig{
+						GoBuildTimeout: time.Second,
+					},
+					Sandbox: SandboxConfig{
+						Enabled:    true,
+						RunTimeout: 2 * time.Second,
+					},
+					HTTP: HTTPConfig{
+						WriteTimeout: 2 * time.Second,
+					},
+				}
+			},
+		},
+		"wasm run timeout": {
+			expectErr: fmt.Sprintf(
+				"WebAssembly build and run timeout (%s) exceeds HTTP response timeout (%s)",
+				3*time.Second, 2*time.Second,
+			),
+			cfg: func(_ *testing.T) Config {
+				return Config{
+					Build: BuildConfig{
+						GoBuildTimeout: time.Second,
+					},
+					WasmRun: WasmRunConfig{
+						Enabled:    true,
+						RunTimeout: 2 * time.Second,
+					},
+					HTTP: HTTPConfig{
+						WriteTimeout: 2 * time.Second,
+					},
+				}
+			},
+		},
+		"invalid trusted proxy": {
+			expectErr: `invalid trusted proxy network "10.0.0.0/33": netip.ParsePrefix("10.0.0.0/33"): prefix length out of range`,
+			cfg: func(_ *testing.T) Config {
+				return Config{
+					HTTP: HTTPConfig{
+						TrustedProxies: []string{"127.0.0.1", "10.0.0.0/33"},
+					},
+				}
+			},
+		},
+		"unsupported formatter": {
+			expectErr: `unsupported formatter "foo"`,
+			cfg: func(_ *testing.T) Config {
+				return Config{
+					Format: FormatConfig{Provider: "foo"},
+				}
+			},
+		},
+		"unsupported snippet store": {
+			expectErr: `unsupported snippet store type "foo"`,
+			cfg: func(_ *testing.T) Config {
+				return Config{
+					Snippets: SnippetsConfig{Store: "foo"},
+				}
+			},
+		},
+		"local snippet store without dir": {
+			expectErr: "snippet directory is required for local snippet store",
+			cfg: func(_ *testing.T) Config {
+				return Config{
+					Snippets: SnippetsConfig{Store: "local"},
+				}
+			},
+		},
 	}
 
 	for n, c := range cases {
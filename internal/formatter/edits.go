@@ -0,0 +1,185 @@
+package formatter
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"typefox.dev/lsp"
+)
+
+// maxEditDistance is max number of changed lines for which minimal edits are computed.
+//
+// Texts with more changes are replaced by a single edit to keep diff computation cheap.
+const maxEditDistance = 1000
+
+// lineHunk is a range of original lines replaced by a range of new lines.
+type lineHunk struct {
+	origStart, origEnd int
+	newStart, newEnd   int
+}
+
+// TextEdits returns a list of line-based edits which transform original text into a new one.
+//
+// Edits are sorted by position and don't overlap. Empty list is returned if texts are equal.
+func TextEdits(orig, updated string) []lsp.TextEdit {
+	a := splitLines(orig)
+	b := splitLines(updated)
+
+	hunks := diffLines(a, b)
+	edits := make([]lsp.TextEdit, 0, len(hunks))
+	for _, h := range hunks {
+		edits = append(edits, lsp.TextEdit{
+			Range: lsp.Range{
+				Start: linePosition(a, h.origStart),
+				End:   linePosition(a, h.origEnd),
+			},
+			NewText: strings.Join(b[h.newStart:h.newEnd], ""),
+		})
+	}
+
+	return edits
+}
+
+// splitLines splits text into lines, keeping line breaks.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// linePosition returns position of a line start.
+func linePosition(lines []string, line int) lsp.Position {
+	// Last line without a trailing line break ends at the end of that line, not at the next line.
+	if line == len(lines) && line > 0 && !strings.HasSuffix(lines[line-1], "\n") {
+		return lsp.Position{
+			Line:      uint32(line - 1),
+			Character: utf16Len(lines[line-1]),
+		}
+	}
+
+	return lsp.Position{Line: uint32(line)}
+}
+
+// utf16Len returns string length in UTF-16 code units, as LSP positions use them by default.
+func utf16Len(s string) uint32 {
+	var n uint32
+	for _, r := range s {
+		n += uint32(utf16.RuneLen(r))
+	}
+
+	return n
+}
+
+// diffLines returns hunks of changed lines between two texts.
+func diffLines(a, b []string) []lineHunk {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	hunks := myersDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	for i := range hunks {
+		hunks[i].origStart += prefix
+		hunks[i].origEnd += prefix
+		hunks[i].newStart += prefix
+		hunks[i].newEnd += prefix
+	}
+
+	return hunks
+}
+
+// myersDiff finds the shortest edit script using Myers' algorithm and returns it as a list of hunks.
+//
+// If texts differ by more than maxEditDistance lines, a single hunk is returned.
+func myersDiff(a, b []string) []lineHunk {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	limit := min(n+m, maxEditDistance)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+
+	// trace keeps furthest reaching paths for each edit distance,
+	// trace[d][k+d] is a furthest x on diagonal k.
+	trace := make([][]int, 0, limit+1)
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackHunks(trace, n, m, d)
+			}
+		}
+
+		trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+	}
+
+	return []lineHunk{{origEnd: n, newEnd: m}}
+}
+
+// backtrackHunks restores edit script from Myers' algorithm trace and merges adjacent edits into hunks.
+func backtrackHunks(trace [][]int, n, m, distance int) []lineHunk {
+	edits := make([]lineHunk, 0, distance)
+	x, y := n, m
+	for d := distance; d > 0; d-- {
+		prev := trace[d-1]
+		furthest := func(k int) int {
+			return prev[k+d-1]
+		}
+
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && furthest(k-1) < furthest(k+1)) {
+			prevK = k + 1
+		}
+
+		prevX := furthest(prevK)
+		prevY := prevX - prevK
+		if prevK == k+1 {
+			// Insertion of b[prevY]
+			edits = append(edits, lineHunk{prevX, prevX, prevY, prevY + 1})
+		} else {
+			// Deletion of a[prevX]
+			edits = append(edits, lineHunk{prevX, prevX + 1, prevY, prevY})
+		}
+
+		x, y = prevX, prevY
+	}
+
+	slices.Reverse(edits)
+	hunks := make([]lineHunk, 0, len(edits))
+	for _, e := range edits {
+		if i := len(hunks) - 1; i >= 0 && hunks[i].origEnd == e.origStart && hunks[i].newEnd == e.newStart {
+			hunks[i].origEnd = e.origEnd
+			hunks[i].newEnd = e.newEnd
+			continue
+		}
+
+		hunks = append(hunks, e)
+	}
+
+	return hunks
+}
@@ -0,0 +1,132 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"typefox.dev/lsp"
+)
+
+// applyEdits applies text edits to a text, edits are expected to be sorted.
+func applyEdits(t *testing.T, text string, edits []lsp.TextEdit) string {
+	t.Helper()
+	lines := splitLines(text)
+	offset := func(pos lsp.Position) int {
+		n := 0
+		for i := 0; i < int(pos.Line) && i < len(lines); i++ {
+			n += len(lines[i])
+		}
+
+		// Test texts are ASCII, so UTF-16 offsets are equal to byte offsets.
+		return n + int(pos.Character)
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, e := range edits {
+		start, end := offset(e.Range.Start), offset(e.Range.End)
+		require.GreaterOrEqual(t, start, last, "edits overlap or aren't sorted")
+		sb.WriteString(text[last:start])
+		sb.WriteString(e.NewText)
+		last = end
+	}
+
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+func lspRange(startLine, startChar, endLine, endChar uint32) lsp.Range {
+	return lsp.Range{
+		Start: lsp.Position{Line: startLine, Character: startChar},
+		End:   lsp.Position{Line: endLine, Character: endChar},
+	}
+}
+
+func TestTextEdits(t *testing.T) {
+	cases := map[string]struct {
+		orig   string
+		update string
+		expect []lsp.TextEdit
+	}{
+		"equal texts": {
+			orig:   "a\nb\n",
+			update: "a\nb\n",
+			expect: []lsp.TextEdit{},
+		},
+		"replace line": {
+			orig:   "a\nb\nc\n",
+			update: "a\nB\nc\n",
+			expect: []lsp.TextEdit{
+				{Range: lspRange(1, 0, 2, 0), NewText: "B\n"},
+			},
+		},
+		"insert and delete lines": {
+			orig:   "a\nb\nc\nd\n",
+			update: "a\nc\nd\ne\n",
+			expect: []lsp.TextEdit{
+				{Range: lspRange(1, 0, 2, 0), NewText: ""},
+				{Range: lspRange(4, 0, 4, 0), NewText: "e\n"},
+			},
+		},
+		"add trailing line break": {
+			orig:   "a\nb",
+			update: "a\nb\n",
+			expect: []lsp.TextEdit{
+				{Range: lspRange(1, 0, 1, 1), NewText: "b\n"},
+			},
+		},
+		"count UTF-16 code units": {
+			orig:   "a\n😀 ж",
+			update: "a\n",
+			expect: []lsp.TextEdit{
+				{Range: lspRange(1, 0, 1, 4), NewText: ""},
+			},
+		},
+		"empty original text": {
+			orig:   "",
+			update: "a\n",
+			expect: []lsp.TextEdit{
+				{Range: lspRange(0, 0, 0, 0), NewText: "a\n"},
+			},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got := TextEdits(c.orig, c.update)
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
+
+func TestTextEdits_Apply(t *testing.T) {
+	cases := map[string]struct {
+		orig   string
+		update string
+	}{
+		"reorder lines": {
+			orig:   "a\nb\nc\nd\ne\n",
+			update: "b\nc\na\ne\nd\n",
+		},
+		"interleaved changes": {
+			orig:   "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			update: "1\nx\n3\n4\ny\nz\n6\n8\n9\n10\n",
+		},
+		"replace everything": {
+			orig:   "a\nb\nc",
+			update: "x\ny\n",
+		},
+		"too many changes": {
+			orig:   strings.Repeat("a\n", maxEditDistance),
+			update: strings.Repeat("b\n", maxEditDistance),
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			edits := TextEdits(c.orig, c.update)
+			require.Equal(t, c.update, applyEdits(t, c.orig, edits))
+		})
+	}
+}
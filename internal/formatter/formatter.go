@@ -0,0 +1,256 @@
+// Package formatter implements Go source code formatting without a round-trip to the Go Playground.
+package formatter
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"path"
+	"slices"
+
+	"golang.org/x/mod/modfile"
+	gofumpt "mvdan.cc/gofumpt/format"
+)
+
+const (
+	// ProviderUpstream is formatter which proxies requests to Go Playground.
+	ProviderUpstream = "upstream"
+
+	// ProviderLocal is formatter which formats code on a server.
+	ProviderLocal = "local"
+)
+
+// ValidateProvider checks whether formatter provider is supported.
+func ValidateProvider(provider string) bool {
+	switch provider {
+	case ProviderUpstream, ProviderLocal:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	// StyleGofmt formats code like gofmt.
+	StyleGofmt = "gofmt"
+
+	// StyleGofmtSimplify formats code like "gofmt -s".
+	StyleGofmtSimplify = "gofmt-s"
+
+	// StyleGoimports formats code and fixes imports like goimports.
+	StyleGoimports = "goimports"
+
+	// StyleGofumpt formats code using stricter gofumpt rules.
+	StyleGofumpt = "gofumpt"
+)
+
+// ValidateStyle checks whether formatting style is supported.
+func ValidateStyle(style string) bool {
+	switch style {
+	case StyleGofmt, StyleGofmtSimplify, StyleGoimports, StyleGofumpt:
+		return true
+	default:
+		return false
+	}
+}
+
+// Options are code formatting options.
+type Options struct {
+	// Style is formatting style.
+	//
+	// Code is formatted like goimports if value is empty.
+	Style string
+
+	// LocalPrefix is comma-separated list of import path prefixes of local packages.
+	//
+	// Imports of local packages are put after third-party imports, same as "goimports -local".
+	// Used only by goimports style.
+	LocalPrefix string
+}
+
+// Formatter formats Go source files using one of supported styles.
+//
+// For goimports style, missing imports are resolved only from the standard library.
+type Formatter struct {
+	stdlib *StdlibIndex
+}
+
+// NewFormatter returns a new formatter which uses standard library index to resolve missing imports.
+//
+// Missing imports are not added if index is nil.
+func NewFormatter(stdlib *StdlibIndex) *Formatter {
+	return &Formatter{stdlib: stdlib}
+}
+
+// Format formats a set of snippet files.
+//
+// Go files are formatted using specified style, go.mod files are formatted using modfile rules.
+// Other files are returned as is.
+//
+// Returned error is a syntax error of a first invalid file.
+func (f *Formatter) Format(files map[string]string, opts Options) (map[string]string, error) {
+	result := make(map[string]string, len(files))
+	fset := token.NewFileSet()
+
+	var modFile *modfile.File
+
+	// Files have to be parsed first to collect package-level declarations
+	// from all files of the same package.
+	pkgs := make(map[string]*sourcePackage)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		src := files[name]
+		switch {
+		case path.Base(name) == "go.mod":
+			mod, out, err := formatModFile(name, src)
+			if err != nil {
+				return nil, err
+			}
+
+			if path.Dir(name) == "." {
+				modFile = mod
+			}
+
+			result[name] = out
+		case path.Ext(name) == ".go":
+			file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+			if err != nil {
+				return nil, err
+			}
+
+			key := path.Join(path.Dir(name), file.Name.Name)
+			pkg, ok := pkgs[key]
+			if !ok {
+				pkg = newSourcePackage()
+				pkgs[key] = pkg
+			}
+
+			pkg.add(name, file)
+		default:
+			result[name] = src
+		}
+	}
+
+	for _, pkg := range pkgs {
+		for name, file := range pkg.files {
+			out, err := f.formatFile(fset, file, pkg, modFile, opts)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+
+			result[name] = out
+		}
+	}
+
+	return result, nil
+}
+
+func (f *Formatter) formatFile(fset *token.FileSet, file *ast.File, pkg *sourcePackage, modFile *modfile.File, opts Options) (string, error) {
+	switch opts.Style {
+	case StyleGofmtSimplify:
+		simplify(file)
+	case StyleGoimports, "":
+		f.stdlib.fixImports(fset, file, pkg.decls)
+	}
+
+	out, err := printFile(fset, file)
+	if err != nil {
+		return "", err
+	}
+
+	switch opts.Style {
+	case StyleGoimports, "":
+		out, err = groupImports(out, splitLocalPrefix(opts.LocalPrefix))
+	case StyleGofumpt:
+		out, err = gofumpt.Source(out, gofumptOptions(modFile))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(out), nil
+}
+
+// gofumptOptions returns gofumpt options based on snippet go.mod file.
+func gofumptOptions(modFile *modfile.File) gofumpt.Options {
+	var opts gofumpt.Options
+	if modFile == nil {
+		return opts
+	}
+
+	if modFile.Module != nil {
+		opts.ModulePath = modFile.Module.Mod.Path
+	}
+
+	if modFile.Go != nil {
+		opts.LangVersion = "go" + modFile.Go.Version
+	}
+
+	return opts
+}
+
+// sourcePackage is a set of Go files which belong to the same package.
+type sourcePackage struct {
+	files map[string]*ast.File
+
+	// decls is a set of package-level declarations from all package files.
+	decls map[string]struct{}
+}
+
+func newSourcePackage() *sourcePackage {
+	return &sourcePackage{
+		files: make(map[string]*ast.File),
+		decls: make(map[string]struct{}),
+	}
+}
+
+func (pkg *sourcePackage) add(name string, file *ast.File) {
+	pkg.files[name] = file
+	for _, decl := range file.Decls {
+		switch t := decl.(type) {
+		case *ast.FuncDecl:
+			if t.Recv == nil {
+				pkg.decls[t.Name.Name] = struct{}{}
+			}
+		case *ast.GenDecl:
+			for _, spec := range t.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					pkg.decls[s.Name.Name] = struct{}{}
+				case *ast.ValueSpec:
+					for _, ident := range s.Names {
+						pkg.decls[ident.Name] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+}
+
+func printFile(fset *token.FileSet, file *ast.File) ([]byte, error) {
+	buff := new(bytes.Buffer)
+	if err := format.Node(buff, fset, file); err != nil {
+		return nil, err
+	}
+
+	// Reformat the result again as imports modification may leave positions
+	// which printer can't lay out as gofmt does (e.g. extra blank lines).
+	return format.Source(buff.Bytes())
+}
+
+func formatModFile(name string, src string) (*modfile.File, string, error) {
+	f, err := modfile.Parse(name, []byte(src), nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	out, err := f.Format()
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", name, err)
+	}
+
+	return f, string(out), nil
+}
@@ -0,0 +1,198 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/go-playground/internal/pkgindex/docutil"
+	"github.com/x1unix/go-playground/internal/pkgindex/index"
+)
+
+func newTestIndexFile() *index.GoIndexFile {
+	pkgs := map[string][]string{
+		"fmt":           {"Println", "Sprintf"},
+		"os":            {"Args", "Exit"},
+		"strings":       {"ToUpper", "Builder"},
+		"math/rand":     {"Intn", "Read"},
+		"math/rand/v2":  {"IntN"},
+		"crypto/rand":   {"Read", "Text"},
+		"html/template": {"New", "HTML"},
+		"text/template": {"New"},
+		"internal/abi":  {"Type"},
+	}
+
+	src := &index.GoIndexFile{
+		Version:  index.GoIndexFileVersion,
+		Packages: index.NewPackages(len(pkgs)),
+		Symbols:  index.NewSymbols(0),
+	}
+
+	for importPath, symbols := range pkgs {
+		name := importPathToAssumedName(importPath)
+		src.Packages.Append(index.PackageInfo{Name: name, ImportPath: importPath})
+		for _, sym := range symbols {
+			src.Symbols.Append(index.SymbolSource{Name: name, Path: importPath}, docutil.Symbol{Label: sym})
+		}
+	}
+
+	return src
+}
+
+func TestFormatter_Format(t *testing.T) {
+	cases := map[string]struct {
+		files     map[string]string
+		noIndex   bool
+		expect    map[string]string
+		expectErr string
+	}{
+		"format code": {
+			files: map[string]string{
+				"main.go": "package main\nimport \"fmt\"\nfunc main(){\nfmt.Println( \"hi\" )\n}",
+			},
+			expect: map[string]string{
+				"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n",
+			},
+		},
+		"add missing imports": {
+			files: map[string]string{
+				"main.go": "package main\n\nfunc main() {\n\tfmt.Println(strings.ToUpper(os.Args[0]))\n}\n",
+			},
+			expect: map[string]string{
+				"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"strings\"\n)\n\n" +
+					"func main() {\n\tfmt.Println(strings.ToUpper(os.Args[0]))\n}\n",
+			},
+		},
+		"remove unused imports": {
+			files: map[string]string{
+				"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"text/template\"\n)\n\n" +
+					"func main() {\n\tos.Exit(1)\n}\n",
+			},
+			expect: map[string]string{
+				"main.go": "package main\n\nimport (\n\t\"os\"\n)\n\nfunc main() {\n\tos.Exit(1)\n}\n",
+			},
+		},
+		"keep third-party imports with unknown package name": {
+			files: map[string]string{
+				"main.go": "package main\n\nimport (\n\t\"github.com/foo/go-bar\"\n\t\"github.com/hashicorp/golang-lru\"\n)\n\n" +
+					"func main() {\n\tlru.New(strings.ToUpper(\"a\"))\n}\n",
+				"rand.go": "package main\n\nimport \"github.com/foo/fast-rand\"\n\nvar n = rand.Intn(10)\n",
+			},
+			expect: map[string]string{
+				"main.go": "package main\n\nimport (\n\t\"strings\"\n\n\t\"github.com/foo/go-bar\"\n\t\"github.com/hashicorp/golang-lru\"\n)\n\n" +
+					"func main() {\n\tlru.New(strings.ToUpper(\"a\"))\n}\n",
+				"rand.go": "package main\n\nimport \"github.com/foo/fast-rand\"\n\nvar n = rand.Intn(10)\n",
+			},
+		},
+		"keep used third-party and special imports": {
+			files: map[string]string{
+				"main.go": "package main\n\nimport (\n\t_ \"embed\"\n\t. \"strings\"\n\tstr \"strings\"\n\n" +
+					"\t\"github.com/foo/go-bar\"\n\t\"github.com/foo/baz/v2\"\n)\n\n" +
+					"func main() {\n\tbar.Do(baz.New(), str.ToUpper(\"a\"))\n}\n",
+			},
+			expect: map[string]string{
+				"main.go": "package main\n\nimport (\n\t_ \"embed\"\n\t. \"strings\"\n\tstr \"strings\"\n\n" +
+					"\t\"github.com/foo/baz/v2\"\n\t\"github.com/foo/go-bar\"\n)\n\n" +
+					"func main() {\n\tbar.Do(baz.New(), str.ToUpper(\"a\"))\n}\n",
+			},
+		},
+		"pick package by used symbols": {
+			files: map[string]string{
+				"a.go": "package main\n\nvar a = rand.Intn(10)\n",
+				"b.go": "package main\n\nvar b = rand.IntN(10)\n",
+				"c.go": "package main\n\nvar c = rand.Text()\n",
+				"d.go": "package main\n\nvar d = template.New(\"\")\n",
+				"e.go": "package main\n\nvar e = abi.Type{}\n",
+			},
+			expect: map[string]string{
+				"a.go": "package main\n\nimport \"math/rand\"\n\nvar a = rand.Intn(10)\n",
+				"b.go": "package main\n\nimport \"math/rand/v2\"\n\nvar b = rand.IntN(10)\n",
+				"c.go": "package main\n\nimport \"crypto/rand\"\n\nvar c = rand.Text()\n",
+				"d.go": "package main\n\nimport \"html/template\"\n\nvar d = template.New(\"\")\n",
+				"e.go": "package main\n\nvar e = abi.Type{}\n",
+			},
+		},
+		"ignore local declarations": {
+			files: map[string]string{
+				"main.go": "package main\n\nfunc main() {\n\tfmt := struct{ Println func() }{}\n\tfmt.Println()\n\tstrings.ToUpper()\n}\n",
+				"log.go":  "package main\n\ntype logger struct{}\n\nfunc (logger) ToUpper() {}\n\nvar strings logger\n",
+			},
+			expect: map[string]string{
+				"main.go": "package main\n\nfunc main() {\n\tfmt := struct{ Println func() }{}\n\tfmt.Println()\n\tstrings.ToUpper()\n}\n",
+				"log.go":  "package main\n\ntype logger struct{}\n\nfunc (logger) ToUpper() {}\n\nvar strings logger\n",
+			},
+		},
+		"only remove imports without index": {
+			noIndex: true,
+			files: map[string]string{
+				"main.go": "package main\n\nimport \"os\"\n\nfunc main() {\n\tfmt.Println()\n}\n",
+			},
+			expect: map[string]string{
+				"main.go": "package main\n\nfunc main() {\n\tfmt.Println()\n}\n",
+			},
+		},
+		"format go.mod and keep other files": {
+			files: map[string]string{
+				"go.mod":   "module   foo\ngo 1.22\nrequire github.com/foo/bar v1.0.0",
+				"data.txt": "  some text ",
+				"main.go":  "package main\n",
+			},
+			expect: map[string]string{
+				"go.mod":   "module foo\n\ngo 1.22\n\nrequire github.com/foo/bar v1.0.0\n",
+				"data.txt": "  some text ",
+				"main.go":  "package main\n",
+			},
+		},
+		"report syntax error": {
+			files: map[string]string{
+				"main.go": "package main\n\nfunc main() {\n",
+			},
+			expectErr: "main.go:3:15: expected '}', found 'EOF'",
+		},
+		"report go.mod error": {
+			files: map[string]string{
+				"go.mod":  "module foo\nfoo bar\n",
+				"main.go": "package main\n",
+			},
+			expectErr: "go.mod:2: unknown directive: foo",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			var stdlib *StdlibIndex
+			if !c.noIndex {
+				stdlib = NewStdlibIndex(newTestIndexFile())
+			}
+
+			got, err := NewFormatter(stdlib).Format(c.files, Options{})
+			if c.expectErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
+
+func TestImportPathToAssumedName(t *testing.T) {
+	cases := map[string]string{
+		"fmt":                        "fmt",
+		"math/rand/v2":               "rand",
+		"github.com/foo/go-bar":      "bar",
+		"github.com/foo/bar.v1":      "bar",
+		"github.com/foo/bar-baz":     "bar",
+		"gopkg.in/yaml.v3":           "yaml",
+		"github.com/foo/v2":          "foo",
+		"github.com/foo/bar/version": "version",
+	}
+
+	for importPath, expect := range cases {
+		t.Run(importPath, func(t *testing.T) {
+			require.Equal(t, expect, importPathToAssumedName(importPath))
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package formatter
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	importGroupStd = iota
+	importGroupThirdParty
+	importGroupLocal
+)
+
+// importGroup returns import group of a package like goimports does.
+//
+// Standard library packages go first, followed by third-party and local packages.
+func importGroup(localPrefixes []string, importPath string) int {
+	for _, prefix := range localPrefixes {
+		if strings.HasPrefix(importPath, prefix) || strings.TrimSuffix(prefix, "/") == importPath {
+			return importGroupLocal
+		}
+	}
+
+	firstElem, _, _ := strings.Cut(importPath, "/")
+	if strings.Contains(firstElem, ".") {
+		return importGroupThirdParty
+	}
+
+	return importGroupStd
+}
+
+// splitLocalPrefix splits comma-separated list of local import path prefixes.
+func splitLocalPrefix(localPrefix string) []string {
+	if localPrefix == "" {
+		return nil
+	}
+
+	prefixes := strings.Split(localPrefix, ",")
+	for i, prefix := range prefixes {
+		prefixes[i] = strings.TrimSpace(prefix)
+	}
+
+	return slices.DeleteFunc(prefixes, func(prefix string) bool {
+		return prefix == ""
+	})
+}
+
+type importChunk struct {
+	group int
+	start int
+	end   int
+}
+
+// groupImports sorts imports by group and separates groups with a blank line.
+//
+// Like goimports, imports are regrouped only within a block of consecutive lines,
+// blocks separated by blank lines are left as is.
+// Source file is expected to be formatted.
+func groupImports(src []byte, localPrefixes []string) ([]byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.ImportsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	tokFile := fset.File(file.Pos())
+	var (
+		out    bytes.Buffer
+		offset int
+	)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT || !gen.Lparen.IsValid() {
+			continue
+		}
+
+		for _, run := range importRuns(tokFile, gen.Specs, localPrefixes) {
+			sorted := slices.Clone(run)
+			slices.SortStableFunc(sorted, func(a, b importChunk) int {
+				return a.group - b.group
+			})
+
+			out.Write(src[offset:run[0].start])
+			for i, chunk := range sorted {
+				if i > 0 {
+					out.WriteString("\n")
+					if chunk.group != sorted[i-1].group {
+						out.WriteString("\n")
+					}
+				}
+
+				out.Write(src[chunk.start:chunk.end])
+			}
+
+			offset = run[len(run)-1].end
+		}
+	}
+
+	if offset == 0 {
+		return src, nil
+	}
+
+	out.Write(src[offset:])
+	return format.Source(out.Bytes())
+}
+
+// importRuns splits import specs into blocks of consecutive lines.
+func importRuns(tokFile *token.File, specs []ast.Spec, localPrefixes []string) [][]importChunk {
+	var (
+		runs    [][]importChunk
+		lastEnd token.Pos
+	)
+
+	for _, spec := range specs {
+		spec := spec.(*ast.ImportSpec)
+		start, end := spec.Pos(), spec.End()
+		if spec.Doc != nil {
+			start = spec.Doc.Pos()
+		}
+		if spec.Comment != nil {
+			end = spec.Comment.End()
+		}
+
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		chunk := importChunk{
+			group: importGroup(localPrefixes, importPath),
+			start: tokFile.Offset(start),
+			end:   tokFile.Offset(end),
+		}
+
+		if len(runs) == 0 || tokFile.Line(start) > tokFile.Line(lastEnd)+1 {
+			runs = append(runs, nil)
+		}
+
+		runs[len(runs)-1] = append(runs[len(runs)-1], chunk)
+		lastEnd = end
+	}
+
+	return runs
+}
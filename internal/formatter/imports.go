@@ -0,0 +1,188 @@
+package formatter
+
+import (
+	"go/ast"
+	"go/token"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// fixImports adds missing standard library imports and removes unused imports.
+//
+// Third-party imports with unknown package name are never removed, as package name
+// might differ from a name assumed from import path.
+//
+// pkgDecls is a set of package-level declarations from all files of a package.
+// Selector expressions on these identifiers are not treated as package references.
+func (idx *StdlibIndex) fixImports(fset *token.FileSet, file *ast.File, pkgDecls map[string]struct{}) {
+	refs := collectReferences(file, pkgDecls)
+
+	type importRef struct {
+		name string
+		path string
+	}
+
+	imported := make(map[string]struct{}, len(file.Imports))
+	var (
+		unused     []importRef
+		unresolved []string
+	)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || importPath == "C" {
+			continue
+		}
+
+		name, resolved := idx.importName(spec, importPath)
+		if name == "_" || name == "." {
+			continue
+		}
+
+		if _, ok := refs[name]; ok {
+			imported[name] = struct{}{}
+			continue
+		}
+
+		if !resolved {
+			unresolved = append(unresolved, importPath)
+			continue
+		}
+
+		ref := importRef{path: importPath}
+		if spec.Name != nil {
+			ref.name = spec.Name.Name
+		}
+		unused = append(unused, ref)
+	}
+
+	for _, ref := range unused {
+		astutil.DeleteNamedImport(fset, file, ref.name, ref.path)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(refs)) {
+		if _, ok := imported[name]; ok || mayProvideName(unresolved, name) {
+			continue
+		}
+
+		pkg, ok := idx.lookup(name, refs[name])
+		if !ok {
+			continue
+		}
+
+		alias := ""
+		if pkg.name != importPathToAssumedName(pkg.path) {
+			alias = pkg.name
+		}
+
+		astutil.AddNamedImport(fset, file, alias, pkg.path)
+	}
+}
+
+// importName returns a name which is used to reference an imported package in a file.
+//
+// Returns false if name is assumed from a third-party package import path,
+// as actual package name can't be resolved without package sources.
+func (idx *StdlibIndex) importName(spec *ast.ImportSpec, importPath string) (string, bool) {
+	if spec.Name != nil {
+		return spec.Name.Name, true
+	}
+
+	if name, ok := idx.packageName(importPath); ok {
+		return name, true
+	}
+
+	return importPathToAssumedName(importPath), isStdlibImportPath(importPath)
+}
+
+// mayProvideName returns whether a package name might refer to one of imports with unknown package name.
+//
+// Package name is usually a part of the last import path element, like "lru" in "github.com/hashicorp/golang-lru".
+func mayProvideName(importPaths []string, name string) bool {
+	return slices.ContainsFunc(importPaths, func(importPath string) bool {
+		return strings.Contains(importPathBase(importPath), name)
+	})
+}
+
+// isStdlibImportPath returns whether import path belongs to standard library.
+//
+// Like in "go" command, paths without a dot in the first element are reserved for standard library.
+func isStdlibImportPath(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// collectReferences returns unresolved identifiers used as selector operands
+// and symbols selected from them.
+//
+// Such identifiers can be only references to imported packages.
+func collectReferences(file *ast.File, pkgDecls map[string]struct{}) map[string]map[string]struct{} {
+	refs := make(map[string]map[string]struct{})
+	ast.Inspect(file, func(node ast.Node) bool {
+		sel, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok || ident.Obj != nil || ident.Name == "_" {
+			return true
+		}
+
+		if _, ok := pkgDecls[ident.Name]; ok {
+			return true
+		}
+
+		symbols, ok := refs[ident.Name]
+		if !ok {
+			symbols = make(map[string]struct{})
+			refs[ident.Name] = symbols
+		}
+
+		symbols[sel.Sel.Name] = struct{}{}
+		return true
+	})
+
+	return refs
+}
+
+// importPathToAssumedName returns package name assumed from import path.
+//
+// Mirrors goimports behavior for packages which are not present in index:
+// major version suffix and "go-" prefix are dropped, name is cut on a first non-identifier character.
+func importPathToAssumedName(importPath string) string {
+	base := strings.TrimPrefix(importPathBase(importPath), "go-")
+	if i := strings.IndexFunc(base, notIdentifier); i >= 0 {
+		base = base[:i]
+	}
+
+	return base
+}
+
+// importPathBase returns the last element of import path without major version suffix.
+func importPathBase(importPath string) string {
+	base := path.Base(importPath)
+	if strings.HasPrefix(base, "v") {
+		if _, err := strconv.Atoi(base[1:]); err == nil {
+			dir := path.Dir(importPath)
+			if dir != "." {
+				base = path.Base(dir)
+			}
+		}
+	}
+
+	return base
+}
+
+func notIdentifier(ch rune) bool {
+	return !('a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' ||
+		'0' <= ch && ch <= '9' ||
+		ch == '_' ||
+		ch >= utf8.RuneSelf && (unicode.IsLetter(ch) || unicode.IsDigit(ch)))
+}
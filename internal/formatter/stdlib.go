@@ -0,0 +1,157 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/x1unix/go-playground/internal/pkgindex/index"
+)
+
+// stdlibPackage is a standard library package which can be used to resolve missing import.
+type stdlibPackage struct {
+	name    string
+	path    string
+	symbols map[string]struct{}
+}
+
+func (pkg stdlibPackage) hasSymbols(symbols map[string]struct{}) bool {
+	for sym := range symbols {
+		if _, ok := pkg.symbols[sym]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// StdlibIndex is a list of standard library packages and their exported symbols.
+type StdlibIndex struct {
+	// packages is a list of packages grouped by package name.
+	packages map[string][]stdlibPackage
+
+	// names is a map of import paths to package names.
+	names map[string]string
+}
+
+// NewStdlibIndex builds standard library index from Go packages index produced by pkgindex.
+func NewStdlibIndex(src *index.GoIndexFile) *StdlibIndex {
+	pkgCount := len(src.Packages.Names)
+	pkgs := make(map[string]*stdlibPackage, pkgCount)
+	idx := &StdlibIndex{
+		packages: make(map[string][]stdlibPackage, pkgCount),
+		names:    make(map[string]string, pkgCount),
+	}
+
+	for i, name := range src.Packages.Names {
+		importPath := src.Packages.Paths[i]
+		if !isImportablePath(importPath) {
+			continue
+		}
+
+		pkgs[importPath] = &stdlibPackage{
+			name:    name,
+			path:    importPath,
+			symbols: make(map[string]struct{}),
+		}
+	}
+
+	for i, sym := range src.Symbols.Names {
+		pkg, ok := pkgs[src.Symbols.Packages[i][1]]
+		if ok {
+			pkg.symbols[sym] = struct{}{}
+		}
+	}
+
+	for _, pkg := range pkgs {
+		idx.packages[pkg.name] = append(idx.packages[pkg.name], *pkg)
+		idx.names[pkg.path] = pkg.name
+	}
+
+	for _, candidates := range idx.packages {
+		slices.SortFunc(candidates, compareCandidates)
+	}
+
+	return idx
+}
+
+// LoadStdlibIndex reads standard library index from a Go packages index file.
+//
+// Index file is produced by "pkgindexer index" command.
+func LoadStdlibIndex(fileName string) (*StdlibIndex, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	src := new(index.GoIndexFile)
+	if err := json.NewDecoder(f).Decode(src); err != nil {
+		return nil, fmt.Errorf("failed to decode index file %q: %w", fileName, err)
+	}
+
+	if src.Version != index.GoIndexFileVersion {
+		return nil, fmt.Errorf(
+			"unsupported index file version %d (want: %d)", src.Version, index.GoIndexFileVersion,
+		)
+	}
+
+	if src.Module != nil {
+		return nil, fmt.Errorf("index file %q is not a standard library index", fileName)
+	}
+
+	return NewStdlibIndex(src), nil
+}
+
+// packageName returns name of a standard library package by import path.
+func (idx *StdlibIndex) packageName(importPath string) (string, bool) {
+	if idx == nil {
+		return "", false
+	}
+
+	name, ok := idx.names[importPath]
+	return name, ok
+}
+
+// lookup finds standard library package by name which exports all passed symbols.
+func (idx *StdlibIndex) lookup(name string, symbols map[string]struct{}) (stdlibPackage, bool) {
+	if idx == nil {
+		return stdlibPackage{}, false
+	}
+
+	for _, pkg := range idx.packages[name] {
+		if pkg.hasSymbols(symbols) {
+			return pkg, true
+		}
+	}
+
+	return stdlibPackage{}, false
+}
+
+// compareCandidates sorts packages with the same name in order of preference.
+//
+// Like goimports, shorter import paths are preferred (e.g. "math/rand" over "math/rand/v2").
+func compareCandidates(a, b stdlibPackage) int {
+	if n := strings.Count(a.path, "/") - strings.Count(b.path, "/"); n != 0 {
+		return n
+	}
+
+	if n := len(a.path) - len(b.path); n != 0 {
+		return n
+	}
+
+	return strings.Compare(a.path, b.path)
+}
+
+func isImportablePath(importPath string) bool {
+	for _, part := range strings.Split(importPath, "/") {
+		if part == "internal" || part == "vendor" {
+			return false
+		}
+	}
+
+	return true
+}
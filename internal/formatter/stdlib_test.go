@@ -0,0 +1,69 @@
+package formatter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/go-playground/internal/pkgindex/index"
+)
+
+func TestLoadStdlibIndex(t *testing.T) {
+	cases := map[string]struct {
+		src       func() *index.GoIndexFile
+		expectErr string
+	}{
+		"load index": {
+			src: newTestIndexFile,
+		},
+		"unsupported version": {
+			src: func() *index.GoIndexFile {
+				src := newTestIndexFile()
+				src.Version = 1
+				return src
+			},
+			expectErr: "unsupported index file version 1",
+		},
+		"module index": {
+			src: func() *index.GoIndexFile {
+				src := newTestIndexFile()
+				src.Module = &index.ModuleInfo{Path: "github.com/foo/bar", Version: "v1.0.0"}
+				return src
+			},
+			expectErr: "is not a standard library index",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			data, err := json.Marshal(c.src())
+			require.NoError(t, err)
+
+			fileName := filepath.Join(t.TempDir(), "go-index.json")
+			require.NoError(t, os.WriteFile(fileName, data, 0644))
+
+			idx, err := LoadStdlibIndex(fileName)
+			if c.expectErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			name, ok := idx.packageName("math/rand/v2")
+			require.True(t, ok)
+			require.Equal(t, "rand", name)
+
+			_, ok = idx.packageName("internal/abi")
+			require.False(t, ok)
+
+			pkg, ok := idx.lookup("rand", map[string]struct{}{"Read": {}})
+			require.True(t, ok)
+			require.Equal(t, "math/rand", pkg.path)
+		})
+	}
+}
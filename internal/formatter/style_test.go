@@ -0,0 +1,62 @@
+package formatter
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+// TestFormatter_FormatStyles checks formatting results against golden files.
+//
+// Each directory in testdata contains "*.input" sources and expected "*.golden" results for a style.
+// Optional "*.mod" file is passed as snippet go.mod file.
+//
+// Use "-update" flag to regenerate golden files.
+func TestFormatter_FormatStyles(t *testing.T) {
+	cases := map[string]Options{
+		"gofmt":           {Style: StyleGofmt},
+		"gofmt-s":         {Style: StyleGofmtSimplify},
+		"goimports":       {Style: StyleGoimports},
+		"goimports-local": {Style: StyleGoimports, LocalPrefix: "example.com/local"},
+		"gofumpt":         {Style: StyleGofumpt},
+	}
+
+	stdlib := NewStdlibIndex(newTestIndexFile())
+	for dir, opts := range cases {
+		inputs, err := filepath.Glob(filepath.Join("testdata", dir, "*.input"))
+		require.NoError(t, err)
+		require.NotEmpty(t, inputs, "no test inputs in %q", dir)
+
+		for _, inputFile := range inputs {
+			baseName := strings.TrimSuffix(inputFile, ".input")
+			t.Run(dir+"/"+filepath.Base(baseName), func(t *testing.T) {
+				src, err := os.ReadFile(inputFile)
+				require.NoError(t, err)
+
+				files := map[string]string{"main.go": string(src)}
+				if modFile, err := os.ReadFile(baseName + ".mod"); err == nil {
+					files["go.mod"] = string(modFile)
+				}
+
+				got, err := NewFormatter(stdlib).Format(files, opts)
+				require.NoError(t, err)
+
+				goldenFile := baseName + ".golden"
+				if *updateGolden {
+					require.NoError(t, os.WriteFile(goldenFile, []byte(got["main.go"]), 0644))
+					return
+				}
+
+				expect, err := os.ReadFile(goldenFile)
+				require.NoError(t, err)
+				require.Equal(t, string(expect), got["main.go"])
+			})
+		}
+	}
+}
@@ -0,0 +1,180 @@
+// Package metrics provides Prometheus metrics of the playground server.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "goplay"
+
+const (
+	statusOK    = "ok"
+	statusError = "error"
+)
+
+// Metrics is a set of server metrics.
+//
+// All methods are safe to call on a nil value which allows to disable metrics collection.
+type Metrics struct {
+	buildDuration    *prometheus.HistogramVec
+	cacheLookups     *prometheus.CounterVec
+	queueRejections  prometheus.Counter
+	cleanupDuration  *prometheus.HistogramVec
+	upstreamDuration *prometheus.HistogramVec
+}
+
+// New creates and registers server metrics.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		buildDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "build",
+			Name:      "duration_seconds",
+			Help:      "WebAssembly build duration by project type.",
+			Buckets:   []float64{.5, 1, 2.5, 5, 10, 20, 30, 60},
+		}, []string{"type", "status"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "build",
+			Name:      "cache_lookups_total",
+			Help:      "Number of WebAssembly build cache lookups by result.",
+		}, []string{"result"}),
+		queueRejections: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "build",
+			Name:      "queue_rejections_total",
+			Help:      "Number of builds rejected due to full build queue.",
+		}),
+		cleanupDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "cleanup",
+			Name:      "duration_seconds",
+			Help:      "Cleanup job duration.",
+			Buckets:   prometheus.ExponentialBuckets(.1, 4, 8),
+		}, []string{"job", "status"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "upstream",
+			Name:      "request_duration_seconds",
+			Help:      "Go Playground API request duration by method, backend and response code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "backend", "code"}),
+	}
+
+	reg.MustRegister(
+		m.buildDuration,
+		m.cacheLookups,
+		m.queueRejections,
+		m.cleanupDuration,
+		m.upstreamDuration,
+	)
+	return m
+}
+
+// Handler returns HTTP handler which exposes metrics from gatherer.
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
+
+// ObserveBuild records build duration.
+func (m *Metrics) ObserveBuild(projectType string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	m.buildDuration.WithLabelValues(projectType, errorStatus(err)).Observe(d.Seconds())
+}
+
+// ObserveCacheLookup records build cache hit or miss.
+func (m *Metrics) ObserveCacheLookup(hit bool) {
+	if m == nil {
+		return
+	}
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	m.cacheLookups.WithLabelValues(result).Inc()
+}
+
+// ObserveQueueRejection records build rejected due to full build queue.
+func (m *Metrics) ObserveQueueRejection() {
+	if m == nil {
+		return
+	}
+
+	m.queueRejections.Inc()
+}
+
+// ObserveCleanup records cleanup job duration.
+func (m *Metrics) ObserveCleanup(job string, d time.Duration, err error) {
+	if m == nil {
+		return
+	}
+
+	m.cleanupDuration.WithLabelValues(job, errorStatus(err)).Observe(d.Seconds())
+}
+
+// InstrumentRoundTripper returns HTTP transport which records Go Playground API request metrics.
+//
+// Request method is a last URL path segment and backend is taken from "backend" query parameter.
+func (m *Metrics) InstrumentRoundTripper(next http.RoundTripper) http.RoundTripper {
+	if m == nil {
+		return next
+	}
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		startTime := time.Now()
+		rsp, err := next.RoundTrip(req)
+
+		code := statusError
+		if err == nil {
+			code = strconv.Itoa(rsp.StatusCode)
+		}
+
+		m.upstreamDuration.
+			WithLabelValues(requestMethod(req), requestBackend(req), code).
+			Observe(time.Since(startTime).Seconds())
+		return rsp, err
+	})
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func requestMethod(req *http.Request) string {
+	return path.Base(req.URL.Path)
+}
+
+func requestBackend(req *http.Request) string {
+	if backend := req.URL.Query().Get("backend"); backend != "" {
+		return backend
+	}
+
+	return "default"
+}
+
+func errorStatus(err error) string {
+	if err == nil {
+		return statusOK
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return "canceled"
+	}
+
+	return statusError
+}
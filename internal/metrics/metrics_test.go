@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_Nil(t *testing.T) {
+	var m *Metrics
+	require.NotPanics(t, func() {
+		m.ObserveBuild("program", time.Second, nil)
+		m.ObserveCacheLookup(true)
+		m.ObserveQueueRejection()
+		m.ObserveCleanup("foo", time.Second, nil)
+	})
+
+	transport := http.DefaultTransport
+	require.Equal(t, transport, m.InstrumentRoundTripper(transport))
+}
+
+func TestMetrics_ObserveBuild(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+	m.ObserveBuild("program", time.Second, nil)
+	m.ObserveBuild("test", time.Second, errors.New("build failed"))
+	m.ObserveBuild("test", time.Second, context.Canceled)
+	m.ObserveCacheLookup(true)
+	m.ObserveCacheLookup(false)
+	m.ObserveCacheLookup(false)
+
+	require.Equal(t, 3, testutil.CollectAndCount(m.buildDuration))
+	require.Equal(t, float64(1), testutil.ToFloat64(m.cacheLookups.WithLabelValues("hit")))
+	require.Equal(t, float64(2), testutil.ToFloat64(m.cacheLookups.WithLabelValues("miss")))
+}
+
+func TestMetrics_InstrumentRoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/share" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	m := New(prometheus.NewRegistry())
+	client := &http.Client{Transport: m.InstrumentRoundTripper(http.DefaultTransport)}
+
+	cases := map[string]struct {
+		url string
+
+		// expectLabel is a list of label values sorted by label name.
+		expectLabel []string
+	}{
+		"default backend": {
+			url:         srv.URL + "/compile",
+			expectLabel: []string{"default", "200", "compile"},
+		},
+		"custom backend": {
+			url:         srv.URL + "/version?backend=gotip",
+			expectLabel: []string{"gotip", "200", "version"},
+		},
+		"error response": {
+			url:         srv.URL + "/share",
+			expectLabel: []string{"default", "500", "share"},
+		},
+		"connection error": {
+			url:         "http://127.0.0.1:0/fmt",
+			expectLabel: []string{"default", statusError, "fmt"},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			rsp, err := client.Get(c.url)
+			if err == nil {
+				require.NoError(t, rsp.Body.Close())
+			}
+
+			require.Contains(t, collectLabels(t, m.upstreamDuration), c.expectLabel)
+		})
+	}
+}
+
+func collectLabels(t *testing.T, c prometheus.Collector) [][]string {
+	t.Helper()
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(c))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	var labels [][]string
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			values := make([]string, 0, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				values = append(values, label.GetValue())
+			}
+			labels = append(labels, values)
+		}
+	}
+
+	return labels
+}
@@ -0,0 +1,190 @@
+// Package modinfo provides information about Go module versions using Go modules proxy.
+package modinfo
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/x1unix/go-playground/internal/util/syncx"
+	"github.com/x1unix/go-playground/pkg/goproxy"
+)
+
+const (
+	// DefaultCacheTTL is default expiration interval of cached module versions.
+	DefaultCacheTTL = 15 * time.Minute
+
+	// maxCacheEntries is max number of cached responses.
+	maxCacheEntries = 4096
+)
+
+// ModuleVersions is a list of module versions.
+type ModuleVersions struct {
+	// Module is module path.
+	Module string `json:"module"`
+
+	// Versions is list of released versions sorted from the newest to the oldest.
+	Versions []string `json:"versions"`
+}
+
+// LatestVersion is information about latest module version.
+type LatestVersion struct {
+	// Module is module path.
+	Module string `json:"module"`
+
+	// Version is latest module version.
+	Version string `json:"version"`
+
+	// Time is version commit time.
+	Time time.Time `json:"time"`
+}
+
+type cacheEntry struct {
+	value     any
+	expiresAt time.Time
+}
+
+// Service resolves module versions and caches results.
+//
+// Unlike module contents, list of module versions changes over time,
+// so cached results expire after TTL.
+type Service struct {
+	proxy goproxy.Proxy
+	ttl   time.Duration
+	group singleflight.Group
+
+	lock  sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewService returns a new service.
+//
+// DefaultCacheTTL is used if TTL is zero.
+func NewService(proxy goproxy.Proxy, ttl time.Duration) *Service {
+	if ttl == 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	return &Service{
+		proxy: proxy,
+		ttl:   ttl,
+		cache: make(map[string]cacheEntry),
+	}
+}
+
+// CacheTTL returns cached results expiration interval.
+func (s *Service) CacheTTL() time.Duration {
+	return s.ttl
+}
+
+// GetVersions returns a list of module versions.
+//
+// Pseudo-versions and invalid versions are omitted.
+func (s *Service) GetVersions(ctx context.Context, modPath string) (*ModuleVersions, error) {
+	if err := goproxy.CheckModule(modPath, ""); err != nil {
+		return nil, err
+	}
+
+	return getCached(ctx, s, "versions:"+modPath, func(ctx context.Context) (*ModuleVersions, error) {
+		versions, err := s.proxy.GetVersions(ctx, goproxy.EscapePath(modPath))
+		if err != nil {
+			return nil, goproxy.ModuleError(modPath, err)
+		}
+
+		return &ModuleVersions{Module: modPath, Versions: sortVersions(versions)}, nil
+	})
+}
+
+// GetLatestVersion returns information about latest module version.
+func (s *Service) GetLatestVersion(ctx context.Context, modPath string) (*LatestVersion, error) {
+	if err := goproxy.CheckModule(modPath, ""); err != nil {
+		return nil, err
+	}
+
+	return getCached(ctx, s, "latest:"+modPath, func(ctx context.Context) (*LatestVersion, error) {
+		info, err := s.proxy.GetLatestVersion(ctx, goproxy.EscapePath(modPath))
+		if err != nil {
+			return nil, goproxy.ModuleError(modPath, err)
+		}
+
+		return &LatestVersion{Module: modPath, Version: info.Version, Time: info.Time}, nil
+	})
+}
+
+// getCached returns cached value or fetches a new one.
+//
+// Concurrent requests for the same key are coalesced. Errors are not cached.
+func getCached[T any](ctx context.Context, s *Service, key string, fetch func(ctx context.Context) (T, error)) (T, error) {
+	if v, ok := s.getCacheEntry(key); ok {
+		return v.(T), nil
+	}
+
+	// Value is fetched in background to be reused by other callers if current request is cancelled.
+	return syncx.DoDetached(ctx, &s.group, key, func(ctx context.Context) (T, error) {
+		v, err := fetch(ctx)
+		if err != nil {
+			return v, err
+		}
+
+		s.putCacheEntry(key, v)
+		return v, nil
+	})
+}
+
+func (s *Service) getCacheEntry(key string) (any, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	entry, ok := s.cache[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(s.cache, key)
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+func (s *Service) putCacheEntry(key string, value any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now()
+	if len(s.cache) >= maxCacheEntries {
+		for k, entry := range s.cache {
+			if now.After(entry.expiresAt) {
+				delete(s.cache, k)
+			}
+		}
+	}
+
+	if len(s.cache) >= maxCacheEntries {
+		clear(s.cache)
+	}
+
+	s.cache[key] = cacheEntry{value: value, expiresAt: now.Add(s.ttl)}
+}
+
+// sortVersions returns valid semver versions sorted in descending order.
+func sortVersions(versions []string) []string {
+	result := make([]string, 0, len(versions))
+	for _, v := range versions {
+		if semver.IsValid(v) && !module.IsPseudoVersion(v) {
+			result = append(result, v)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b string) int {
+		return semver.Compare(b, a)
+	})
+
+	return slices.Compact(result)
+}
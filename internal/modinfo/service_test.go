@@ -0,0 +1,121 @@
+package modinfo
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/go-playground/pkg/goproxy"
+)
+
+func TestService(t *testing.T) {
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github.com/!burnt!sushi/toml/@v/list", func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("v0.4.1\nv1.3.2\nv1.10.0\nv0.0.0-20230101000000-abcdefabcdef\nv1.4.0-rc.1\nv1.3.2\nfoo\n"))
+	})
+	mux.HandleFunc("/github.com/!burnt!sushi/toml/@latest", func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Version":"v1.10.0","Time":"2024-01-02T03:04:05Z"}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "not found", http.StatusGone)
+	})
+
+	proxy := httptest.NewServer(mux)
+	t.Cleanup(proxy.Close)
+
+	svc := NewService(goproxy.NewClient(proxy.Client(), proxy.URL), time.Minute)
+
+	cases := map[string]struct {
+		module    string
+		run       func(ctx context.Context, modPath string) (any, error)
+		expect    any
+		expectErr error
+		isInvalid bool
+	}{
+		"versions": {
+			module: "github.com/BurntSushi/toml",
+			run: func(ctx context.Context, modPath string) (any, error) {
+				return svc.GetVersions(ctx, modPath)
+			},
+			expect: &ModuleVersions{
+				Module:   "github.com/BurntSushi/toml",
+				Versions: []string{"v1.10.0", "v1.4.0-rc.1", "v1.3.2", "v0.4.1"},
+			},
+		},
+		"latest version": {
+			module: "github.com/BurntSushi/toml",
+			run: func(ctx context.Context, modPath string) (any, error) {
+				return svc.GetLatestVersion(ctx, modPath)
+			},
+			expect: &LatestVersion{
+				Module:  "github.com/BurntSushi/toml",
+				Version: "v1.10.0",
+				Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			},
+		},
+		"not found": {
+			module: "github.com/foo/bar",
+			run: func(ctx context.Context, modPath string) (any, error) {
+				return svc.GetVersions(ctx, modPath)
+			},
+			expectErr: goproxy.ErrModuleNotFound,
+		},
+		"invalid path": {
+			module: "github.com/foo/../bar",
+			run: func(ctx context.Context, modPath string) (any, error) {
+				return svc.GetLatestVersion(ctx, modPath)
+			},
+			isInvalid: true,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			// Second call should be served from cache.
+			for range 2 {
+				got, err := c.run(context.Background(), c.module)
+				if c.isInvalid {
+					require.ErrorAs(t, err, new(goproxy.ValidationError))
+					return
+				}
+
+				if c.expectErr != nil {
+					require.ErrorIs(t, err, c.expectErr)
+					continue
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, c.expect, got)
+			}
+		})
+	}
+
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestService_CacheExpiration(t *testing.T) {
+	var requests atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte("v1.0.0\n"))
+	}))
+	t.Cleanup(proxy.Close)
+
+	svc := NewService(goproxy.NewClient(proxy.Client(), proxy.URL), time.Millisecond)
+	for range 2 {
+		_, err := svc.GetVersions(context.Background(), "example.com/foo")
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	require.Equal(t, int32(2), requests.Load())
+}
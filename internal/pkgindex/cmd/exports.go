@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/x1unix/go-playground/internal/analyzer/check"
+	"github.com/x1unix/go-playground/internal/pkgindex/exports"
+)
+
+type exportsFlags struct {
+	*globalFlags
+	outFile  string
+	goos     string
+	goarch   string
+	packages []string
+}
+
+func newCmdExports(g *globalFlags) *cobra.Command {
+	flags := exportsFlags{
+		globalFlags: g,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "exports [-r goroot] -o output",
+		Short: "Generate standard library export data archive",
+		Long:  "Generate an archive with export data of standard Go packages. Used by analyzer worker for type checking",
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if flags.outFile == "" {
+				return fmt.Errorf("missing output file flag")
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runGenExports(cmd.Context(), flags)
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.outFile, "output", "o", "", "Path to output file")
+	cmd.Flags().StringVar(&flags.goos, "goos", "linux", "Target OS of export data")
+	cmd.Flags().StringVar(&flags.goarch, "goarch", "amd64", "Target architecture of export data")
+	cmd.Flags().StringSliceVarP(&flags.packages, "package", "p", nil, "Packages to include. Includes all standard packages by default")
+	return cmd
+}
+
+func runGenExports(ctx context.Context, flags exportsFlags) error {
+	data, err := exports.Collect(ctx, exports.Params{
+		GoRoot:   flags.goRoot,
+		GOOS:     flags.goos,
+		GOARCH:   flags.goarch,
+		Packages: flags.packages,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(flags.outFile), 0755); err != nil {
+		return fmt.Errorf("failed to pre-create parent directories: %w", err)
+	}
+
+	f, err := os.OpenFile(flags.outFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("can't create output file: %w", err)
+	}
+
+	defer silentClose(f)
+	if err := check.WriteExportData(f, data); err != nil {
+		return fmt.Errorf("can't write export data to file %q: %w", flags.outFile, err)
+	}
+
+	log.Printf("Collected export data of %d packages", len(data))
+	return nil
+}
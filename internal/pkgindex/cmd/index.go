@@ -42,6 +42,9 @@ func runGenIndex(flags importsFlags) error {
 		return err
 	}
 
-	log.Printf("Scanned %d packages and %d symbols", len(entries.Packages.Names), len(entries.Symbols.Names))
+	log.Printf(
+		"Scanned %d packages, %d symbols and %d type members",
+		len(entries.Packages.Names), len(entries.Symbols.Names), len(entries.Members.Names),
+	)
 	return nil
 }
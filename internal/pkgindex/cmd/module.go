@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/x1unix/go-playground/internal/pkgindex/index"
+	"github.com/x1unix/go-playground/internal/pkgindex/modindex"
+	"github.com/x1unix/go-playground/pkg/goproxy"
+)
+
+type moduleFlags struct {
+	importsFlags
+	proxyURL string
+}
+
+func newCmdModule(g *globalFlags) *cobra.Command {
+	flags := moduleFlags{
+		importsFlags: importsFlags{
+			globalFlags: g,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "module module[@version] [-o output]",
+		Short: "Generate index file with third-party module packages and symbols",
+		Long:  "Download a module from Go modules proxy and generate a JSON index file of its packages and symbols. Latest version is used if version is omitted",
+		Args:  cobra.ExactArgs(1),
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			index.Debug = flags.verbose
+			return flags.validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runGenModuleIndex(cmd.Context(), flags, args[0])
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.outFile, "output", "o", "", "Path to output file. When empty, prints to stdout")
+	cmd.Flags().BoolVarP(&flags.prettyPrint, "pretty", "P", false, "Add indents to JSON output")
+	cmd.Flags().BoolVar(&flags.stdout, "stdout", false, "Dump result into stdout")
+	cmd.Flags().BoolVarP(&flags.verbose, "verbose", "v", false, "Enable verbose logging")
+	cmd.Flags().StringVar(&flags.proxyURL, "proxy", "", "Go modules proxy URL. Uses $GOPROXY by default")
+	return cmd
+}
+
+func runGenModuleIndex(ctx context.Context, flags moduleFlags, module string) error {
+	client := goproxy.NewClientFromEnv()
+	if flags.proxyURL != "" {
+		client = goproxy.NewClient(http.DefaultClient, flags.proxyURL)
+	}
+
+	modPath, version, _ := strings.Cut(module, "@")
+	if err := goproxy.CheckModule(modPath, version); err != nil {
+		return err
+	}
+
+	version, err := modindex.ResolveVersion(ctx, client, modPath, version)
+	if err != nil {
+		return err
+	}
+
+	entries, err := modindex.IndexModule(ctx, client, modPath, version)
+	if err != nil {
+		return err
+	}
+
+	if err := writeOutput(flags.importsFlags, entries); err != nil {
+		return err
+	}
+
+	log.Printf(
+		"Scanned %s@%s: %d packages, %d symbols and %d type members",
+		modPath, version, len(entries.Packages.Names), len(entries.Symbols.Names), len(entries.Members.Names),
+	)
+	return nil
+}
@@ -33,6 +33,8 @@ func newCmdRoot() *cobra.Command {
 
 	cmd.AddCommand(newCmdImports(f))
 	cmd.AddCommand(newCmdIndex(f))
+	cmd.AddCommand(newCmdExports(f))
+	cmd.AddCommand(newCmdModule(f))
 	return cmd
 }
 
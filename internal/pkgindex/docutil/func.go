@@ -11,6 +11,7 @@ import (
 // SymbolFromFunc constructs completion item from a function AST declaration.
 //
 // Function documentation is generated in Markdown format.
+// Methods are returned with receiver type name.
 func SymbolFromFunc(fset *token.FileSet, fn *ast.FuncDecl, snippetFormat lsp.InsertTextFormat) (item Symbol, err error) {
 	isSnippet := snippetFormat == lsp.SnippetTextFormat
 	item = Symbol{
@@ -21,6 +22,11 @@ func SymbolFromFunc(fset *token.FileSet, fn *ast.FuncDecl, snippetFormat lsp.Ins
 		Documentation:   string(FormatCommentGroup(fn.Doc)),
 	}
 
+	if fn.Recv != nil {
+		item.Kind = lsp.MethodCompletion
+		item.Receiver = ReceiverTypeName(fn.Recv)
+	}
+
 	item.Detail, err = PrintFuncAnonymous(fset, fn)
 	if err != nil {
 		return item, err
@@ -65,3 +71,30 @@ func PrintFuncPrototype(fset *token.FileSet, decl *ast.FuncDecl) (string, error)
 func PrintFuncAnonymous(fset *token.FileSet, decl *ast.FuncDecl) (string, error) {
 	return PrintDecl(fset, decl.Type)
 }
+
+// ReceiverTypeName returns base type name of a method receiver.
+//
+// Pointer and type parameters are omitted.
+func ReceiverTypeName(recv *ast.FieldList) string {
+	if recv == nil || len(recv.List) == 0 {
+		return ""
+	}
+
+	typ := recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+
+	switch t := typ.(type) {
+	case *ast.IndexExpr:
+		typ = t.X
+	case *ast.IndexListExpr:
+		typ = t.X
+	}
+
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
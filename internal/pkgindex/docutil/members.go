@@ -0,0 +1,141 @@
+package docutil
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"typefox.dev/lsp"
+)
+
+// CollectTypeMembers collects struct fields and interface methods of types in a type declaration block.
+//
+// Collected symbols have Receiver set to a type name.
+// Embedded interfaces and type constraints are ignored.
+func CollectTypeMembers(fset *token.FileSet, specGroup *ast.GenDecl, filter Filter, snippetFormat lsp.InsertTextFormat, collector Collector) (count int, err error) {
+	filter = filterOrDefault(filter)
+	for _, spec := range specGroup.Specs {
+		typeSpec, ok := spec.(*ast.TypeSpec)
+		if !ok || filter.Ignore(typeSpec.Name.Name) {
+			continue
+		}
+
+		var n int
+		switch t := typeSpec.Type.(type) {
+		case *ast.StructType:
+			n, err = collectStructFields(fset, t, filter, collectReceiver(typeSpec, collector))
+		case *ast.InterfaceType:
+			n, err = collectInterfaceMethods(fset, t, filter, snippetFormat, collectReceiver(typeSpec, collector))
+		}
+
+		count += n
+		if err != nil {
+			return count, fmt.Errorf("%w (type: %q, pos: %s)", err, typeSpec.Name.Name, GetDeclPosition(fset, typeSpec))
+		}
+	}
+
+	return count, nil
+}
+
+func collectReceiver(spec *ast.TypeSpec, collector Collector) Collector {
+	return CollectorFunc(func(sym Symbol) {
+		sym.Receiver = spec.Name.Name
+		collector.CollectSymbol(sym)
+	})
+}
+
+func collectStructFields(fset *token.FileSet, typ *ast.StructType, filter Filter, collector Collector) (count int, err error) {
+	for _, field := range typ.Fields.List {
+		names := field.Names
+		if len(names) == 0 {
+			// Embedded field name is a type name.
+			ident := embeddedFieldName(field.Type)
+			if ident == nil {
+				continue
+			}
+
+			names = []*ast.Ident{ident}
+		}
+
+		for _, name := range names {
+			if filter.Ignore(name.Name) {
+				continue
+			}
+
+			sym, err := fieldToSymbol(fset, name, field)
+			if err != nil {
+				return count, err
+			}
+
+			count++
+			collector.CollectSymbol(sym)
+		}
+	}
+
+	return count, nil
+}
+
+func collectInterfaceMethods(fset *token.FileSet, typ *ast.InterfaceType, filter Filter, snippetFormat lsp.InsertTextFormat, collector Collector) (count int, err error) {
+	for _, field := range typ.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 || filter.Ignore(field.Names[0].Name) {
+			continue
+		}
+
+		sym, err := SymbolFromFunc(fset, &ast.FuncDecl{Doc: field.Doc, Name: field.Names[0], Type: fn}, snippetFormat)
+		if err != nil {
+			return count, err
+		}
+
+		sym.Kind = lsp.MethodCompletion
+		count++
+		collector.CollectSymbol(sym)
+	}
+
+	return count, nil
+}
+
+func fieldToSymbol(fset *token.FileSet, name *ast.Ident, field *ast.Field) (Symbol, error) {
+	typeStr, err := PrintDecl(fset, field.Type)
+	if err != nil {
+		return Symbol{}, err
+	}
+
+	doc := field.Doc
+	if CommentGroupEmpty(doc) {
+		doc = field.Comment
+	}
+
+	sym := Symbol{
+		Label:           name.Name,
+		Kind:            lsp.FieldCompletion,
+		InsertText:      name.Name,
+		InsertTextRules: lsp.PlainTextTextFormat,
+		Detail:          typeStr,
+		Signature:       name.Name + " " + typeStr,
+	}
+
+	if !CommentGroupEmpty(doc) {
+		sym.Documentation = string(FormatCommentGroup(doc))
+	}
+
+	return sym, nil
+}
+
+// embeddedFieldName returns name of embedded struct field.
+func embeddedFieldName(typ ast.Expr) *ast.Ident {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t
+	case *ast.StarExpr:
+		return embeddedFieldName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel
+	case *ast.IndexExpr:
+		return embeddedFieldName(t.X)
+	case *ast.IndexListExpr:
+		return embeddedFieldName(t.X)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+// Package members is a test package for type members.
+package members
+
+import (
+	"io"
+	"sync"
+)
+
+// Reader reads data.
+type Reader interface {
+	// Read reads data into p.
+	Read(p []byte) (n int, err error)
+	io.Closer
+	reset()
+}
+
+// Buffer is a sample struct.
+type Buffer[T any] struct {
+	sync.Mutex
+	*io.PipeReader
+
+	// Data is buffer contents.
+	Data, Extra []T
+	Size        int // Size is buffer size.
+
+	private bool
+}
+
+// Len returns buffer length.
+func (b *Buffer[T]) Len() int {
+	return len(b.Data)
+}
+
+// Reset resets buffer.
+func (b Buffer[T]) Reset(size int) {}
+
+func (b *Buffer[T]) grow() {}
+
+type counter int
+
+// Inc is method of unexported type.
+func (c counter) Inc() {}
@@ -13,11 +13,14 @@ type TraverseOpts struct {
 	Filter        Filter
 	FileSet       *token.FileSet
 	SnippetFormat lsp.InsertTextFormat
+
+	// CollectMembers enables collection of type methods and struct fields.
+	CollectMembers bool
 }
 
 // CollectSymbols traverses root file declarations and transforms them into completion items.
 //
-// Important: type methods are ignored.
+// Type methods and struct fields are collected only if CollectMembers option is set.
 func CollectSymbols(decls []ast.Decl, opts TraverseOpts, collector Collector) (count int, err error) {
 	filter := filterOrDefault(opts.Filter)
 	for _, decl := range decls {
@@ -28,8 +31,10 @@ func CollectSymbols(decls []ast.Decl, opts TraverseOpts, collector Collector) (c
 			}
 
 			if t.Recv != nil {
-				// Ignore type methods, at-least for now.
-				continue
+				recv := ReceiverTypeName(t.Recv)
+				if !opts.CollectMembers || filter.Ignore(recv) {
+					continue
+				}
 			}
 
 			item, err := SymbolFromFunc(opts.FileSet, t, opts.SnippetFormat)
@@ -55,6 +60,18 @@ func CollectSymbols(decls []ast.Decl, opts TraverseOpts, collector Collector) (c
 				)
 			}
 
+			count += n
+			if !opts.CollectMembers || t.Tok != token.TYPE {
+				continue
+			}
+
+			n, err = CollectTypeMembers(opts.FileSet, t, filter, opts.SnippetFormat, collector)
+			if err != nil {
+				return count, fmt.Errorf(
+					"can't parse type members: %w (at %s)", err, GetDeclPosition(opts.FileSet, t),
+				)
+			}
+
 			count += n
 		default:
 			fname := opts.FileSet.File(decl.Pos()).Name()
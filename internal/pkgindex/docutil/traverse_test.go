@@ -19,6 +19,7 @@ func TestTypeToCompletionItem(t *testing.T) {
 		expectErr  string
 		dumpOnly   bool
 		ignore     []string
+		members    bool
 	}{
 		"bufio": {
 			filePath:   "testdata/bufio/bufio.go",
@@ -28,6 +29,11 @@ func TestTypeToCompletionItem(t *testing.T) {
 			filePath:   "testdata/simple/types.go",
 			expectFile: "testdata/simple/expect.json",
 		},
+		"members": {
+			filePath:   "testdata/members/members.go",
+			expectFile: "testdata/members/expect.json",
+			members:    true,
+		},
 		"builtin": {
 			ignore: []string{
 				"Type", "Type1", "IntegerType", "FloatType", "ComplexType",
@@ -45,8 +51,9 @@ func TestTypeToCompletionItem(t *testing.T) {
 			require.NoError(t, err)
 
 			opts := TraverseOpts{
-				FileSet:       fset,
-				SnippetFormat: lsp.SnippetTextFormat,
+				FileSet:        fset,
+				SnippetFormat:  lsp.SnippetTextFormat,
+				CollectMembers: c.members,
 			}
 			if len(c.ignore) > 0 {
 				opts.Filter = NewIgnoreList(c.ignore...)
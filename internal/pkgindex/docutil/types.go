@@ -17,6 +17,11 @@ type Symbol struct {
 	Signature       string                 `json:"signature,omitempty"`
 	Kind            lsp.CompletionItemKind `json:"kind"`
 	InsertTextRules lsp.InsertTextFormat   `json:"insertTextRules"`
+
+	// Receiver is a type name which method or struct field belongs to.
+	//
+	// Empty for package-level symbols.
+	Receiver string `json:"receiver,omitempty"`
 }
 
 // Compare compares two symbol for sorting.
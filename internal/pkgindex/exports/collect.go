@@ -0,0 +1,102 @@
+// Package exports collects export data of Go standard library packages.
+package exports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/x1unix/go-playground/internal/analyzer/check"
+)
+
+// Params is export data collection parameters.
+type Params struct {
+	// GoRoot is path to Go SDK.
+	GoRoot string
+
+	// GOOS is target OS of export data.
+	GOOS string
+
+	// GOARCH is target architecture of export data.
+	GOARCH string
+
+	// Packages is list of packages to collect.
+	//
+	// All standard library packages are collected if empty.
+	Packages []string
+}
+
+type packageInfo struct {
+	ImportPath string
+	Export     string
+}
+
+// Collect builds standard library packages and returns their export data.
+//
+// Internal and vendored packages are skipped as they can't be imported by user code.
+func Collect(ctx context.Context, params Params) (check.ExportData, error) {
+	pkgs := params.Packages
+	if len(pkgs) == 0 {
+		pkgs = []string{"std"}
+	}
+
+	args := append([]string{"list", "-export", "-json=ImportPath,Export"}, pkgs...)
+	cmd := exec.CommandContext(ctx, filepath.Join(params.GoRoot, "bin", "go"), args...)
+	cmd.Env = append(
+		os.Environ(),
+		"GOROOT="+params.GoRoot,
+		"GOOS="+params.GOOS,
+		"GOARCH="+params.GOARCH,
+		"CGO_ENABLED=0",
+	)
+
+	stderr := new(bytes.Buffer)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed: %w. Stderr: %s", err, stderr)
+	}
+
+	data := make(check.ExportData)
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var pkg packageInfo
+		err := dec.Decode(&pkg)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed go list output: %w", err)
+		}
+
+		if pkg.Export == "" || !isImportable(pkg.ImportPath) {
+			continue
+		}
+
+		contents, err := os.ReadFile(pkg.Export)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read export data of %q: %w", pkg.ImportPath, err)
+		}
+
+		data[pkg.ImportPath] = contents
+	}
+
+	return data, nil
+}
+
+func isImportable(importPath string) bool {
+	for _, part := range strings.Split(importPath, "/") {
+		if part == "internal" || part == "vendor" {
+			return false
+		}
+	}
+
+	return true
+}
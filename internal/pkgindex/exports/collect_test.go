@@ -0,0 +1,30 @@
+package exports
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/go-playground/internal/pkgindex/imports"
+)
+
+func TestCollect(t *testing.T) {
+	goRoot, err := imports.ResolveGoRoot()
+	require.NoError(t, err)
+
+	data, err := Collect(context.Background(), Params{
+		GoRoot:   goRoot,
+		GOOS:     runtime.GOOS,
+		GOARCH:   runtime.GOARCH,
+		Packages: []string{"fmt", "internal/fmtsort"},
+	})
+	require.NoError(t, err)
+	require.Contains(t, data, "fmt")
+	require.NotContains(t, data, "internal/fmtsort")
+
+	pkg, err := data.Importer().Import("fmt")
+	require.NoError(t, err)
+	require.NotNil(t, pkg.Scope().Lookup("Println"))
+}
@@ -0,0 +1,57 @@
+package index
+
+import (
+	"github.com/x1unix/go-playground/internal/pkgindex/docutil"
+)
+
+// indexBuilder accumulates packages, symbols and type members into a flat index.
+type indexBuilder struct {
+	packages Packages
+	symbols  Symbols
+	members  Members
+
+	// pkgMembers are members of a currently scanned package.
+	//
+	// Methods might be declared before their types, so members are resolved after package scan.
+	pkgMembers []docutil.Symbol
+}
+
+func newIndexBuilder(pkgCapacity, symCapacity, memberCapacity int) *indexBuilder {
+	return &indexBuilder{
+		packages: NewPackages(pkgCapacity),
+		symbols:  NewSymbols(symCapacity),
+		members:  NewMembers(memberCapacity),
+	}
+}
+
+func (b *indexBuilder) collect(src SymbolSource, sym docutil.Symbol) {
+	if sym.Receiver != "" {
+		b.pkgMembers = append(b.pkgMembers, sym)
+		return
+	}
+
+	b.symbols.Append(src, sym)
+}
+
+// scanPackage calls scan function to collect a single package symbols.
+func (b *indexBuilder) scanPackage(scan func(collector CollectFn) (*traverseResult, error)) (*traverseResult, error) {
+	offset := len(b.symbols.Names)
+	b.pkgMembers = b.pkgMembers[:0]
+	result, err := scan(b.collect)
+	if err != nil {
+		return nil, err
+	}
+
+	b.members.AppendPackageMembers(b.symbols, offset, b.pkgMembers)
+	return result, nil
+}
+
+func (b *indexBuilder) build(goVersion string) *GoIndexFile {
+	return &GoIndexFile{
+		Version:  GoIndexFileVersion,
+		Go:       goVersion,
+		Packages: b.packages,
+		Symbols:  b.symbols,
+		Members:  b.members,
+	}
+}
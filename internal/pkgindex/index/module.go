@@ -0,0 +1,183 @@
+package index
+
+import (
+	"archive/zip"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"io/fs"
+	"path"
+	"runtime"
+	"strings"
+
+	"github.com/x1unix/go-playground/internal/pkgindex/docutil"
+)
+
+const (
+	// Most modules contain less than hundred packages, so buffers are smaller than for GOROOT.
+	modPkgBuffSize    = 16
+	modSymBuffSize    = 512
+	modMemberBuffSize = 512
+
+	goModFileName = "go.mod"
+)
+
+// ScanModule builds index of exported packages of a module from module zip archive.
+//
+// Archive format is the same as served by Go modules proxy, all files should be prefixed with "module@version/".
+// Main, internal and nested modules packages are skipped.
+func ScanModule(r io.ReaderAt, size int64, modPath, version string) (*GoIndexFile, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("can't read module archive: %w", err)
+	}
+
+	fsys, err := fs.Sub(zr, modPath+"@"+version)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newIndexBuilder(modPkgBuffSize, modSymBuffSize, modMemberBuffSize)
+	err = fs.WalkDir(fsys, ".", func(dir string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if dir != "." && isModuleDirIgnored(fsys, dir) {
+			return fs.SkipDir
+		}
+
+		importPath := modPath
+		if dir != "." {
+			importPath = path.Join(modPath, dir)
+		}
+
+		result, err := b.scanPackage(func(collector CollectFn) (*traverseResult, error) {
+			return traverseModuleDir(fsys, dir, importPath, collector)
+		})
+		if err != nil {
+			return fmt.Errorf("error while scanning package %q: %w", importPath, err)
+		}
+
+		if result != nil {
+			b.packages.Append(result.pkgInfo)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	index := b.build(strings.TrimPrefix(runtime.Version(), "go"))
+	index.Module = &ModuleInfo{
+		Path:    modPath,
+		Version: version,
+	}
+
+	return index, nil
+}
+
+func isModuleDirIgnored(fsys fs.FS, dir string) bool {
+	name := path.Base(dir)
+	if isDirIgnored(name) || name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+		return true
+	}
+
+	// Nested modules are not part of a module.
+	_, err := fs.Stat(fsys, path.Join(dir, goModFileName))
+	return err == nil
+}
+
+func traverseModuleDir(fsys fs.FS, dir, importPath string, collector CollectFn) (*traverseResult, error) {
+	dirents, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("can't read dir %q: %w", dir, err)
+	}
+
+	ctx := defaultCtx
+	ctx.JoinPath = path.Join
+	ctx.OpenFile = func(name string) (io.ReadCloser, error) {
+		return fsys.Open(name)
+	}
+
+	// Read all files first as commands can't be imported and should be skipped.
+	var files []moduleFile
+	for _, dirent := range dirents {
+		name := dirent.Name()
+		if dirent.IsDir() || !docutil.IsGoSourceFile(name) {
+			continue
+		}
+
+		match, err := ctx.MatchFile(dir, name)
+		if err != nil {
+			return nil, fmt.Errorf("can't check build constraints for file %q: %w", name, err)
+		}
+
+		if !match {
+			continue
+		}
+
+		filePath := path.Join(dir, name)
+		src, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		if isMainPackage(src) {
+			return nil, nil
+		}
+
+		files = append(files, moduleFile{path: filePath, src: src})
+	}
+
+	var (
+		count   int
+		pkgInfo = PackageInfo{
+			ImportPath: importPath,
+		}
+	)
+
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parseFile(fset, file.path, fileParseParams{
+			src:        file.src,
+			parseDoc:   pkgInfo.Doc == "",
+			importPath: importPath,
+			collector:  collector,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can't parse file %q: %w", file.path, err)
+		}
+
+		count += f.symbolsCount
+		pkgInfo.Name = f.packageName
+		if f.doc != nil {
+			pkgInfo.Doc = docutil.BuildPackageDoc(f.doc, importPath)
+		}
+	}
+
+	if count == 0 {
+		return nil, nil
+	}
+
+	return &traverseResult{
+		pkgInfo:      pkgInfo,
+		symbolsCount: count,
+	}, nil
+}
+
+type moduleFile struct {
+	path string
+	src  []byte
+}
+
+func isMainPackage(src []byte) bool {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, parser.PackageClauseOnly)
+	return err == nil && f.Name.Name == "main"
+}
@@ -0,0 +1,56 @@
+package index
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanModule(t *testing.T) {
+	data := testModuleArchive(t, "example.com/foo@v1.0.0", map[string]string{
+		"go.mod":                 "module example.com/foo\n",
+		"foo.go":                 "// Package foo is a test module.\npackage foo\n\n// Hello says hello.\nfunc Hello() {}\n\nfunc (b *Bar) Len() int { return 0 }\n",
+		"types.go":               "package foo\n\n// Bar is a sample type.\ntype Bar struct {\n\tName string\n}\n",
+		"foo_test.go":            "package foo\n\nfunc TestHelper() {}\n",
+		"baz/baz.go":             "package baz\n\nconst Version = 1\n",
+		"baz/baz_windows.go":     "package baz\n\nconst Windows = true\n",
+		"internal/util/util.go":  "package util\n\nfunc Util() {}\n",
+		"tool/main.go":           "package main\n\nfunc Run() {}\n\nfunc main() {}\n",
+		"nested/go.mod":          "module example.com/foo/nested\n",
+		"nested/nested.go":       "package nested\n\nfunc Nested() {}\n",
+		"testdata/testdata.go":   "package testdata\n\nfunc Data() {}\n",
+		".github/scripts/gen.go": "package scripts\n\nfunc Gen() {}\n",
+	})
+
+	got, err := ScanModule(bytes.NewReader(data), int64(len(data)), "example.com/foo", "v1.0.0")
+	require.NoError(t, err)
+	require.Equal(t, GoIndexFileVersion, got.Version)
+	require.Equal(t, &ModuleInfo{Path: "example.com/foo", Version: "v1.0.0"}, got.Module)
+	require.Equal(t, []string{"foo", "baz"}, got.Packages.Names)
+	require.Equal(t, []string{"example.com/foo", "example.com/foo/baz"}, got.Packages.Paths)
+	require.Equal(t, []string{"Hello", "Bar", "Version"}, got.Symbols.Names)
+	require.Equal(t, []FlatSymbolSource{
+		{"foo", "example.com/foo"},
+		{"foo", "example.com/foo"},
+		{"baz", "example.com/foo/baz"},
+	}, got.Symbols.Packages)
+	require.Equal(t, []string{"Len", "Name"}, got.Members.Names)
+	require.Equal(t, []int{1, 1}, got.Members.Types)
+}
+
+func testModuleArchive(t *testing.T, prefix string, files map[string]string) []byte {
+	t.Helper()
+	buff := new(bytes.Buffer)
+	zw := zip.NewWriter(buff)
+	for name, contents := range files {
+		w, err := zw.Create(prefix + "/" + name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+	return buff.Bytes()
+}
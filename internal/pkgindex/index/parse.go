@@ -21,6 +21,9 @@ type sourceSummary struct {
 }
 
 type fileParseParams struct {
+	// src is file contents. File is read from disk if src is nil.
+	src []byte
+
 	importPath string
 	parseDoc   bool
 	collector  CollectFn
@@ -35,7 +38,12 @@ func getFilter(importPath string) docutil.Filter {
 }
 
 func parseFile(fset *token.FileSet, fpath string, params fileParseParams) (*sourceSummary, error) {
-	root, err := parser.ParseFile(fset, fpath, nil, parser.ParseComments)
+	var contents any
+	if params.src != nil {
+		contents = params.src
+	}
+
+	root, err := parser.ParseFile(fset, fpath, contents, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
@@ -60,9 +68,10 @@ func parseFile(fset *token.FileSet, fpath string, params fileParseParams) (*sour
 	})
 
 	opts := docutil.TraverseOpts{
-		FileSet:       fset,
-		Filter:        getFilter(params.importPath),
-		SnippetFormat: lsp.SnippetTextFormat,
+		FileSet:        fset,
+		Filter:         getFilter(params.importPath),
+		SnippetFormat:  lsp.SnippetTextFormat,
+		CollectMembers: true,
 	}
 
 	summary.symbolsCount, err = docutil.CollectSymbols(root.Decls, opts, collector)
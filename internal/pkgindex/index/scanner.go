@@ -22,6 +22,9 @@ const (
 	// Go 1.23 has 182 packages and over 9k total symbols for linux.
 	pkgBuffSize = 182
 	symBuffSize = 9000
+
+	// Go 1.27 has over 6k exported methods and struct fields for linux.
+	memberBuffSize = 6200
 )
 
 var Debug = false
@@ -46,8 +49,7 @@ func ScanRoot(goRoot string) (*GoIndexFile, error) {
 		return nil, err
 	}
 
-	packages := NewPackages(pkgBuffSize)
-	symbols := NewSymbols(symBuffSize)
+	b := newIndexBuilder(pkgBuffSize, symBuffSize, memberBuffSize)
 
 	for queue.Occupied() {
 		v, ok := queue.Pop()
@@ -64,7 +66,9 @@ func ScanRoot(goRoot string) (*GoIndexFile, error) {
 			continue
 		}
 
-		result, err := traverseScanEntry(v, queue, symbols.Append)
+		result, err := b.scanPackage(func(collector CollectFn) (*traverseResult, error) {
+			return traverseScanEntry(v, queue, collector)
+		})
 		if err != nil {
 			return nil, fmt.Errorf("error while scanning package %q: %w", v.importPath, err)
 		}
@@ -77,18 +81,13 @@ func ScanRoot(goRoot string) (*GoIndexFile, error) {
 		// and not importable.
 		// Also skip empty packages (usually part of vendor path).
 		if result.pkgInfo.ImportPath != docutil.BuiltinPackage && result.symbolsCount > 0 {
-			packages.Append(result.pkgInfo)
+			b.packages.Append(result.pkgInfo)
 		} else if Debug {
 			log.Printf("Skip pkg: %s", result.pkgInfo.ImportPath)
 		}
 	}
 
-	return &GoIndexFile{
-		Version:  GoIndexFileVersion,
-		Go:       goVersion,
-		Packages: packages,
-		Symbols:  symbols,
-	}, nil
+	return b.build(goVersion), nil
 }
 
 func enqueueRootEntries(rootDir string, parentImportPath string, queue *imports.Queue[scanEntry]) error {
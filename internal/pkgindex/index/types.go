@@ -5,7 +5,7 @@ import (
 	"typefox.dev/lsp"
 )
 
-const GoIndexFileVersion = 2
+const GoIndexFileVersion = 3
 
 type FlatSymbolSource [2]string
 
@@ -93,6 +93,83 @@ func (s *Symbols) Append(src SymbolSource, sym docutil.Symbol) {
 	s.Packages = append(s.Packages, src.Flatten())
 }
 
+// Members is a flat representation of type methods and struct fields.
+type Members struct {
+	// Names are list of member names.
+	Names []string `json:"names"`
+
+	// Docs are list of member documentation.
+	Docs []string `json:"docs,omitempty"`
+
+	// Details are list of short member summaries (field type or method signature without name).
+	Details []string `json:"details,omitempty"`
+
+	// Signatures contain full method declaration or field name with type.
+	Signatures []string `json:"signatures,omitempty"`
+
+	// InsertTexts are values to be inserted when member suggestion is selected.
+	InsertTexts []string `json:"insertTexts"`
+
+	// InsertTextRules contains snippet insertion rules for InsertTexts.
+	InsertTextRules []lsp.InsertTextFormat `json:"insertTextRules,omitempty"`
+
+	// Kinds contains member type for suggestion icon.
+	Kinds []lsp.CompletionItemKind `json:"kinds"`
+
+	// Types contains index of receiver type in symbols list.
+	//
+	// Receiver type index is used instead of type name and package to keep file small.
+	Types []int `json:"types"`
+}
+
+func NewMembers(capacity int) Members {
+	return Members{
+		Names:           make([]string, 0, capacity),
+		Docs:            make([]string, 0, capacity),
+		Details:         make([]string, 0, capacity),
+		Signatures:      make([]string, 0, capacity),
+		InsertTexts:     make([]string, 0, capacity),
+		InsertTextRules: make([]lsp.InsertTextFormat, 0, capacity),
+		Kinds:           make([]lsp.CompletionItemKind, 0, capacity),
+		Types:           make([]int, 0, capacity),
+	}
+}
+
+func (m *Members) Append(typeIndex int, sym docutil.Symbol) {
+	m.Names = append(m.Names, sym.Label)
+	m.Docs = append(m.Docs, sym.Documentation)
+	m.Details = append(m.Details, sym.Detail)
+	m.Signatures = append(m.Signatures, sym.Signature)
+	m.InsertTexts = append(m.InsertTexts, sym.InsertText)
+	m.InsertTextRules = append(m.InsertTextRules, sym.InsertTextRules)
+	m.Kinds = append(m.Kinds, sym.Kind)
+	m.Types = append(m.Types, typeIndex)
+}
+
+// AppendPackageMembers appends members of package types.
+//
+// Package symbols should be located in symbols list starting from offset.
+// Members of types missing in symbols list are skipped.
+func (m *Members) AppendPackageMembers(symbols Symbols, offset int, members []docutil.Symbol) {
+	if len(members) == 0 {
+		return
+	}
+
+	types := make(map[string]int)
+	for i := offset; i < len(symbols.Names); i++ {
+		switch symbols.Kinds[i] {
+		case lsp.ClassCompletion, lsp.StructCompletion, lsp.InterfaceCompletion:
+			types[symbols.Names[i]] = i
+		}
+	}
+
+	for _, sym := range members {
+		if typeIndex, ok := types[sym.Receiver]; ok {
+			m.Append(typeIndex, sym)
+		}
+	}
+}
+
 // SymbolSource holds information where symbol belongs to.
 type SymbolSource struct {
 	// Name is package name.
@@ -121,4 +198,18 @@ type GoIndexFile struct {
 
 	// Symbols is structure of arrays of package symbols.
 	Symbols Symbols `json:"symbols"`
+
+	// Members is structure of arrays of type methods and struct fields.
+	Members Members `json:"members"`
+
+	// Module is information about indexed third-party module.
+	//
+	// Empty for standard library index.
+	Module *ModuleInfo `json:"module,omitempty"`
+}
+
+// ModuleInfo contains Go module path and version.
+type ModuleInfo struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
 }
@@ -0,0 +1,32 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/x1unix/go-playground/internal/pkgindex/docutil"
+	"typefox.dev/lsp"
+)
+
+func TestMembers_AppendPackageMembers(t *testing.T) {
+	src := SymbolSource{Name: "foo", Path: "example.com/foo"}
+	symbols := NewSymbols(4)
+	symbols.Append(SymbolSource{Name: "bar", Path: "bar"}, docutil.Symbol{Label: "Buffer", Kind: lsp.StructCompletion})
+	symbols.Append(src, docutil.Symbol{Label: "New", Kind: lsp.FunctionCompletion})
+	symbols.Append(src, docutil.Symbol{Label: "Buffer", Kind: lsp.StructCompletion})
+	symbols.Append(src, docutil.Symbol{Label: "Reader", Kind: lsp.InterfaceCompletion})
+
+	members := NewMembers(3)
+	members.AppendPackageMembers(symbols, 1, []docutil.Symbol{
+		{Label: "Len", Kind: lsp.MethodCompletion, Receiver: "Buffer"},
+		{Label: "Read", Kind: lsp.MethodCompletion, Receiver: "Reader"},
+		{Label: "Data", Kind: lsp.FieldCompletion, Receiver: "Buffer"},
+		{Label: "Inc", Kind: lsp.MethodCompletion, Receiver: "counter"},
+	})
+
+	require.Equal(t, []string{"Len", "Read", "Data"}, members.Names)
+	require.Equal(t, []int{2, 3, 2}, members.Types)
+	require.Equal(t, []lsp.CompletionItemKind{
+		lsp.MethodCompletion, lsp.MethodCompletion, lsp.FieldCompletion,
+	}, members.Kinds)
+}
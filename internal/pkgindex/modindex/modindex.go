@@ -0,0 +1,60 @@
+// Package modindex builds symbols index of third-party Go modules using Go modules proxy.
+package modindex
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/x1unix/go-playground/internal/pkgindex/index"
+	"github.com/x1unix/go-playground/pkg/goproxy"
+)
+
+// MaxModuleSize is max size of module archive in bytes.
+//
+// Module archive is kept in memory during indexing.
+const MaxModuleSize = 64 << 20
+
+// ErrModuleTooLarge is returned when module archive exceeds MaxModuleSize.
+var ErrModuleTooLarge = fmt.Errorf("module archive is too large (max %d bytes)", MaxModuleSize)
+
+// ResolveVersion returns module version to index.
+//
+// Latest module version is returned if version is empty.
+func ResolveVersion(ctx context.Context, client goproxy.Proxy, modPath, version string) (string, error) {
+	if version != "" {
+		return version, nil
+	}
+
+	info, err := client.GetLatestVersion(ctx, goproxy.EscapePath(modPath))
+	if err != nil {
+		return "", goproxy.ModuleError(modPath, err)
+	}
+
+	return info.Version, nil
+}
+
+// IndexModule downloads module archive of specified version and builds index of its packages.
+func IndexModule(ctx context.Context, client goproxy.Proxy, modPath, version string) (*index.GoIndexFile, error) {
+	archive, err := client.GetModuleSource(ctx, goproxy.EscapePath(modPath), goproxy.EscapePath(version))
+	if err != nil {
+		return nil, goproxy.ModuleError(modPath+"@"+version, err)
+	}
+
+	defer archive.Close()
+	if archive.Size > MaxModuleSize {
+		return nil, ErrModuleTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(archive, MaxModuleSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download module %s@%s: %w", modPath, version, err)
+	}
+
+	if len(data) > MaxModuleSize {
+		return nil, ErrModuleTooLarge
+	}
+
+	return index.ScanModule(bytes.NewReader(data), int64(len(data)), modPath, version)
+}
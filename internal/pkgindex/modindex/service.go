@@ -0,0 +1,121 @@
+package modindex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/x1unix/go-playground/internal/util/syncx"
+	"github.com/x1unix/go-playground/pkg/goproxy"
+)
+
+const cacheFileExt = ".json"
+
+// Service builds and caches indexes of third-party modules.
+//
+// Module versions are immutable, so cached index never expires.
+type Service struct {
+	logger   *zap.Logger
+	client   goproxy.Proxy
+	cacheDir string
+	group    singleflight.Group
+}
+
+// NewService returns a new service which keeps module indexes in a cache directory.
+func NewService(logger *zap.Logger, client goproxy.Proxy, cacheDir string) *Service {
+	return &Service{
+		logger:   logger.Named("modindex"),
+		client:   client,
+		cacheDir: cacheDir,
+	}
+}
+
+// GetModuleIndex returns JSON-encoded index of a module.
+//
+// Latest module version is used if version is empty.
+func (s *Service) GetModuleIndex(ctx context.Context, modPath, version string) ([]byte, error) {
+	if err := goproxy.CheckModule(modPath, version); err != nil {
+		return nil, err
+	}
+
+	version, err := ResolveVersion(ctx, s.client, modPath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolved version is used as cache file name.
+	if err := goproxy.CheckModule(modPath, version); err != nil {
+		return nil, err
+	}
+
+	cacheFile := s.cacheFilePath(modPath, version)
+	data, err := os.ReadFile(cacheFile)
+	if err == nil {
+		return data, nil
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		s.logger.Warn("failed to read module index cache", zap.String("file", cacheFile), zap.Error(err))
+	}
+
+	// Index is built in background to be reused by other callers if current request is cancelled.
+	return syncx.DoDetached(ctx, &s.group, cacheFile, func(ctx context.Context) ([]byte, error) {
+		return s.buildIndex(ctx, modPath, version, cacheFile)
+	})
+}
+
+func (s *Service) buildIndex(ctx context.Context, modPath, version, cacheFile string) ([]byte, error) {
+	idx, err := IndexModule(ctx, s.client, modPath, version)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode module index: %w", err)
+	}
+
+	if err := writeFileAtomic(cacheFile, data); err != nil {
+		s.logger.Error("failed to save module index to cache", zap.String("file", cacheFile), zap.Error(err))
+	}
+
+	return data, nil
+}
+
+func (s *Service) cacheFilePath(modPath, version string) string {
+	name := goproxy.EscapePath(modPath) + "@" + goproxy.EscapePath(version) + cacheFileExt
+	return filepath.Join(s.cacheDir, filepath.FromSlash(name))
+}
+
+func writeFileAtomic(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(name), filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), name)
+	}
+
+	if err != nil {
+		_ = os.Remove(f.Name())
+	}
+
+	return err
+}
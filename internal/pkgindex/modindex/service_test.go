@@ -0,0 +1,126 @@
+package modindex
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/x1unix/go-playground/internal/pkgindex/index"
+	"github.com/x1unix/go-playground/pkg/goproxy"
+)
+
+func TestService_GetModuleIndex(t *testing.T) {
+	var downloads atomic.Int32
+	archive := testModuleArchive(t, "github.com/Foo/bar@v1.2.0", map[string]string{
+		"bar.go": "package bar\n\n// Hello says hello.\nfunc Hello() {}\n",
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github.com/!foo/bar/@latest", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Version":"v1.2.0"}`))
+	})
+	mux.HandleFunc("/github.com/!foo/bar/@v/v1.2.0.zip", func(w http.ResponseWriter, _ *http.Request) {
+		downloads.Add(1)
+		w.Header().Set("Content-Type", "application/zip")
+		_, _ = w.Write(archive)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found: "+r.URL.Path, http.StatusGone)
+	})
+
+	proxy := httptest.NewServer(mux)
+	t.Cleanup(proxy.Close)
+
+	cacheDir := t.TempDir()
+	svc := NewService(zap.NewNop(), goproxy.NewClient(proxy.Client(), proxy.URL), cacheDir)
+
+	cases := map[string]struct {
+		module    string
+		version   string
+		expectErr error
+		isInvalid bool
+	}{
+		"latest version": {
+			module: "github.com/Foo/bar",
+		},
+		"specific version": {
+			module:  "github.com/Foo/bar",
+			version: "v1.2.0",
+		},
+		"missing module": {
+			module:    "github.com/Foo/baz",
+			expectErr: goproxy.ErrModuleNotFound,
+		},
+		"missing version": {
+			module:    "github.com/Foo/bar",
+			version:   "v1.9.0",
+			expectErr: goproxy.ErrModuleNotFound,
+		},
+		"invalid module path": {
+			module:    "../etc/passwd",
+			isInvalid: true,
+		},
+		"invalid version": {
+			module:    "github.com/Foo/bar",
+			version:   "v1/../../foo",
+			isInvalid: true,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			data, err := svc.GetModuleIndex(context.Background(), c.module, c.version)
+			if c.isInvalid {
+				require.ErrorAs(t, err, new(goproxy.ValidationError))
+				return
+			}
+
+			if c.expectErr != nil {
+				require.ErrorIs(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var got index.GoIndexFile
+			require.NoError(t, json.Unmarshal(data, &got))
+			require.Equal(t, &index.ModuleInfo{Path: "github.com/Foo/bar", Version: "v1.2.0"}, got.Module)
+			require.Equal(t, []string{"github.com/Foo/bar"}, got.Packages.Paths)
+			require.Equal(t, []string{"Hello"}, got.Symbols.Names)
+		})
+	}
+
+	// Module archive should be downloaded only once.
+	require.Equal(t, int32(1), downloads.Load())
+	require.FileExists(t, filepath.Join(cacheDir, "github.com", "!foo", "bar@v1.2.0.json"))
+
+	entries, err := os.ReadDir(filepath.Join(cacheDir, "github.com", "!foo"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func testModuleArchive(t *testing.T, prefix string, files map[string]string) []byte {
+	t.Helper()
+	buff := new(bytes.Buffer)
+	zw := zip.NewWriter(buff)
+	for name, contents := range files {
+		w, err := zw.Create(prefix + "/" + name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+	return buff.Bytes()
+}
@@ -0,0 +1,302 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// oldRootDir is a temporary mount point of an old root file system during pivot_root.
+const oldRootDir = ".oldroot"
+
+// sandboxDevices is a list of device files available inside sandbox.
+var sandboxDevices = []string{"/dev/null", "/dev/zero", "/dev/random", "/dev/urandom"}
+
+// Init handles sandbox helper process startup and should be called at the beginning of the main function.
+//
+// Go doesn't allow to set resource limits between fork and exec, so sandbox re-executes
+// the current binary which prepares a sandbox and then replaces process image
+// with a target program.
+//
+// Function returns immediately if current process is not a sandbox helper.
+func Init() {
+	rawConfig, ok := os.LookupEnv(configEnvVar)
+	if !ok {
+		return
+	}
+
+	// No new privileges flag is set per thread and has to be set on a thread which calls execve.
+	runtime.LockOSThread()
+	err := execSandboxed(rawConfig, os.Args[1:])
+
+	// execve returns only on failure.
+	_, _ = fmt.Fprintln(os.Stderr, "sandbox:", err)
+	os.Exit(exitCodeSetupFailed)
+}
+
+func execSandboxed(rawConfig string, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing program name")
+	}
+
+	cfg, err := decodeHelperConfig(rawConfig)
+	if err != nil {
+		return fmt.Errorf("malformed config: %w", err)
+	}
+
+	if err := setupRoot(cfg); err != nil {
+		return err
+	}
+
+	if err := dropPrivileges(cfg.UID, cfg.GID); err != nil {
+		return err
+	}
+
+	if err := applyLimits(cfg.Limits); err != nil {
+		return err
+	}
+
+	env := os.Environ()
+	filteredEnv := make([]string, 0, len(env))
+	for _, v := range env {
+		if !strings.HasPrefix(v, configEnvVar+"=") {
+			filteredEnv = append(filteredEnv, v)
+		}
+	}
+
+	return syscall.Exec(args[0], args, filteredEnv)
+}
+
+// setupRoot replaces root file system with a tmpfs which contains only a program workspace,
+// GOROOT and a few device files.
+//
+// Root file system is read-only, only workspace is writable.
+func setupRoot(cfg helperConfig) error {
+	// Don't propagate any mount changes back to the host.
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %w", err)
+	}
+
+	root := cfg.RootDir
+	if err := unix.Mount("tmpfs", root, "tmpfs", unix.MS_NOSUID|unix.MS_NODEV, "size=1m,mode=0755"); err != nil {
+		return fmt.Errorf("failed to mount root: %w", err)
+	}
+
+	if err := bindMount(cfg.WorkDir, filepath.Join(root, sandboxWorkDir), unix.MS_NOSUID|unix.MS_NODEV); err != nil {
+		return err
+	}
+
+	if cfg.GoRoot != "" {
+		flags := uintptr(unix.MS_RDONLY | unix.MS_NOSUID | unix.MS_NODEV)
+		if err := bindMount(cfg.GoRoot, filepath.Join(root, sandboxGoRoot), flags); err != nil {
+			return err
+		}
+	}
+
+	for _, dev := range sandboxDevices {
+		if err := bindMount(dev, filepath.Join(root, dev), unix.MS_NOSUID); err != nil {
+			return err
+		}
+	}
+
+	oldRoot := filepath.Join(root, oldRootDir)
+	if err := os.Mkdir(oldRoot, 0700); err != nil {
+		return fmt.Errorf("failed to create old root mount point: %w", err)
+	}
+
+	if err := unix.PivotRoot(root, oldRoot); err != nil {
+		return fmt.Errorf("pivot_root failed: %w", err)
+	}
+
+	if err := unix.Chdir("/"); err != nil {
+		return err
+	}
+
+	if err := unix.Unmount("/"+oldRootDir, unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("failed to unmount old root: %w", err)
+	}
+
+	if err := os.Remove("/" + oldRootDir); err != nil {
+		return fmt.Errorf("failed to remove old root mount point: %w", err)
+	}
+
+	rootFlags := uintptr(unix.MS_REMOUNT | unix.MS_BIND | unix.MS_RDONLY | unix.MS_NOSUID | unix.MS_NODEV)
+	if err := unix.Mount("", "/", "", rootFlags, ""); err != nil {
+		return fmt.Errorf("failed to remount root as read-only: %w", err)
+	}
+
+	return unix.Chdir(sandboxWorkDir)
+}
+
+// bindMount mounts a file or directory to a target path and applies mount flags.
+func bindMount(src, dst string, flags uintptr) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		err = os.MkdirAll(dst, 0755)
+	} else {
+		err = createEmptyFile(dst)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create mount point for %q: %w", src, err)
+	}
+
+	if err := unix.Mount(src, dst, "", unix.MS_BIND, ""); err != nil {
+		return fmt.Errorf("failed to mount %q: %w", src, err)
+	}
+
+	// Flags of mounts inherited from a parent user namespace are locked and have to be preserved.
+	lockedFlags, err := mountFlags(src)
+	if err != nil {
+		return err
+	}
+
+	if err := unix.Mount("", dst, "", unix.MS_REMOUNT|unix.MS_BIND|flags|lockedFlags, ""); err != nil {
+		return fmt.Errorf("failed to remount %q: %w", src, err)
+	}
+
+	return nil
+}
+
+func createEmptyFile(name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// mountFlags returns flags of a mount which contains a given path.
+func mountFlags(name string) (uintptr, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(name, &st); err != nil {
+		return 0, fmt.Errorf("failed to stat mount of %q: %w", name, err)
+	}
+
+	statToMountFlags := []struct {
+		st    int64
+		mount uintptr
+	}{
+		{st: unix.ST_RDONLY, mount: unix.MS_RDONLY},
+		{st: unix.ST_NOSUID, mount: unix.MS_NOSUID},
+		{st: unix.ST_NODEV, mount: unix.MS_NODEV},
+		{st: unix.ST_NOEXEC, mount: unix.MS_NOEXEC},
+		{st: unix.ST_NOATIME, mount: unix.MS_NOATIME},
+		{st: unix.ST_NODIRATIME, mount: unix.MS_NODIRATIME},
+		{st: unix.ST_RELATIME, mount: unix.MS_RELATIME},
+	}
+
+	var flags uintptr
+	for _, f := range statToMountFlags {
+		if st.Flags&f.st != 0 {
+			flags |= f.mount
+		}
+	}
+
+	return flags, nil
+}
+
+// dropPrivileges switches process to an unprivileged user and prevents gaining new privileges.
+func dropPrivileges(uid, gid int) error {
+	if err := syscall.Setgroups(nil); err != nil {
+		return fmt.Errorf("failed to reset supplementary groups: %w", err)
+	}
+
+	if err := syscall.Setgid(gid); err != nil {
+		return fmt.Errorf("failed to set group ID: %w", err)
+	}
+
+	if err := syscall.Setuid(uid); err != nil {
+		return fmt.Errorf("failed to set user ID: %w", err)
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no new privileges flag: %w", err)
+	}
+
+	return nil
+}
+
+func applyLimits(l Limits) error {
+	rlimits := []struct {
+		resource int
+		name     string
+		value    uint64
+	}{
+		{resource: unix.RLIMIT_CPU, name: "cpu", value: uint64(math.Ceil(l.CPUTime.Seconds()))},
+		{resource: unix.RLIMIT_DATA, name: "memory", value: l.Memory},
+		{resource: unix.RLIMIT_FSIZE, name: "file size", value: l.FileSize},
+		{resource: unix.RLIMIT_NOFILE, name: "open files", value: l.OpenFiles},
+		{resource: unix.RLIMIT_NPROC, name: "processes", value: l.Processes},
+		{resource: unix.RLIMIT_CORE, name: "core dump"},
+	}
+
+	for _, lim := range rlimits {
+		if lim.value == 0 && lim.resource != unix.RLIMIT_CORE {
+			continue
+		}
+
+		rlim := &unix.Rlimit{Cur: lim.value, Max: lim.value}
+		if err := unix.Setrlimit(lim.resource, rlim); err != nil {
+			return fmt.Errorf("failed to set %s limit: %w", lim.name, err)
+		}
+	}
+
+	return nil
+}
+
+// newSandboxedCommand returns command which starts a program inside sandbox helper process.
+//
+// Program is started in a new process group and new user, PID, mount, IPC and network namespaces.
+// Program path and arguments are resolved inside sandbox.
+func newSandboxedCommand(ctx context.Context, cfg helperConfig, name string, args ...string) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("can't locate sandbox helper executable: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, self, append([]string{name}, args...)...)
+	cmd.Env = []string{configEnvVar + "=" + cfg.encode()}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWPID | syscall.CLONE_NEWNS |
+			syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+
+		// Helper process runs as namespace root to prepare a sandbox and then switches to
+		// an unprivileged user, so both users are mapped.
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getuid(), Size: 1},
+			{ContainerID: cfg.UID, HostID: cfg.UID, Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: os.Getgid(), Size: 1},
+			{ContainerID: cfg.GID, HostID: cfg.GID, Size: 1},
+		},
+		GidMappingsEnableSetgroups: true,
+	}
+
+	// Kill the whole process group to not leave any orphans.
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+	return cmd, nil
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+)
+
+// Init handles sandbox helper process startup.
+//
+// Sandbox is supported only on Linux, func is no-op on other platforms.
+func Init() {}
+
+func newSandboxedCommand(_ context.Context, _ helperConfig, _ string, _ ...string) (*exec.Cmd, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,75 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	// configEnvVar is environment variable used to pass configuration to a sandbox helper process.
+	configEnvVar = "GOPLAY_SANDBOX_CONFIG"
+
+	// exitCodeSetupFailed is exit code returned by helper process if it failed to prepare a sandbox.
+	exitCodeSetupFailed = 126
+
+	// sandboxWorkDir is a path of program workspace inside sandbox.
+	sandboxWorkDir = "/work"
+
+	// sandboxGoRoot is a path of read-only GOROOT inside sandbox.
+	sandboxGoRoot = "/goroot"
+)
+
+// Limits is a set of resource limits applied to a sandboxed program.
+//
+// Zero values mean no limit.
+type Limits struct {
+	// CPUTime is max CPU time consumed by a process.
+	CPUTime time.Duration `json:"cpu,omitempty"`
+
+	// Memory is max size of process data segment (heap) in bytes.
+	//
+	// Address space limit is not used as Go runtime reserves a large chunk of virtual memory on startup.
+	Memory uint64 `json:"mem,omitempty"`
+
+	// FileSize is max size of a file created by a process.
+	FileSize uint64 `json:"fsize,omitempty"`
+
+	// OpenFiles is max number of open file descriptors.
+	OpenFiles uint64 `json:"nofile,omitempty"`
+
+	// Processes is max number of processes and threads.
+	//
+	// Go runtime starts a few threads per process, so value shouldn't be too small.
+	Processes uint64 `json:"nproc,omitempty"`
+}
+
+// helperConfig is sandbox helper process configuration.
+type helperConfig struct {
+	// Limits are resource limits applied to a program.
+	Limits Limits `json:"limits"`
+
+	// RootDir is an empty directory used as a mount point of a new root file system.
+	RootDir string `json:"root"`
+
+	// WorkDir is a host path of program workspace which is mounted at sandboxWorkDir.
+	WorkDir string `json:"work"`
+
+	// GoRoot is optional host GOROOT path which is mounted read-only at sandboxGoRoot.
+	GoRoot string `json:"goroot,omitempty"`
+
+	// UID is user ID used to run a program.
+	UID int `json:"uid"`
+
+	// GID is group ID used to run a program.
+	GID int `json:"gid"`
+}
+
+func (c helperConfig) encode() string {
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+func decodeHelperConfig(str string) (c helperConfig, err error) {
+	err = json.Unmarshal([]byte(str), &c)
+	return c, err
+}
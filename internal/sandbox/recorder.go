@@ -0,0 +1,175 @@
+package sandbox
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+const (
+	// KindStdout is event kind for program stdout output.
+	KindStdout = "stdout"
+
+	// KindStderr is event kind for program stderr output.
+	KindStderr = "stderr"
+
+	truncatedOutputMsg = "\n[output truncated]\n"
+)
+
+// EventHandler is called on each new output event.
+type EventHandler = func(e *goplay.CompileEvent)
+
+// Recorder captures program output and splits it into Go Playground compatible events.
+//
+// Each event delay is a time passed since a previous event which allows
+// front-end to replay output with the same timings as the original program run.
+type Recorder struct {
+	lock      sync.Mutex
+	lastEvent time.Time
+	events    []*goplay.CompileEvent
+	written   int
+	maxSize   int
+	truncated bool
+	closed    bool
+
+	onEvent EventHandler
+	pending []*goplay.CompileEvent
+	notify  chan struct{}
+	done    chan struct{}
+}
+
+// NewRecorder returns a new recorder.
+//
+// Recorder stops accepting output after maxSize bytes. Zero value disables limit.
+//
+// Optional event handler is called for each event as soon as output is received.
+// Handler is called from a separate goroutine to not block program output
+// if handler is slow, call Close to wait until all events are handled.
+func NewRecorder(maxSize int, onEvent EventHandler) *Recorder {
+	r := &Recorder{
+		maxSize:   maxSize,
+		lastEvent: time.Now(),
+		onEvent:   onEvent,
+		notify:    make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+
+	if onEvent == nil {
+		close(r.done)
+	} else {
+		go r.dispatch()
+	}
+
+	return r
+}
+
+// Stdout returns writer for program stdout.
+func (r *Recorder) Stdout() io.Writer {
+	return recorderWriter{kind: KindStdout, r: r}
+}
+
+// Stderr returns writer for program stderr.
+func (r *Recorder) Stderr() io.Writer {
+	return recorderWriter{kind: KindStderr, r: r}
+}
+
+// Events returns list of recorded events.
+func (r *Recorder) Events() []*goplay.CompileEvent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.events
+}
+
+// Close stops accepting output and waits until all events are passed to event handler.
+func (r *Recorder) Close() {
+	r.lock.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.notify)
+	}
+	r.lock.Unlock()
+
+	<-r.done
+}
+
+// Truncated returns whether output exceeded max size.
+func (r *Recorder) Truncated() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.truncated
+}
+
+func (r *Recorder) write(kind string, p []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.truncated || r.closed {
+		return
+	}
+
+	if r.maxSize > 0 && r.written+len(p) > r.maxSize {
+		p = p[:r.maxSize-r.written]
+		r.truncated = true
+	}
+
+	r.written += len(p)
+	if len(p) > 0 {
+		r.appendEvent(kind, string(p))
+	}
+
+	if r.truncated {
+		r.appendEvent(KindStderr, truncatedOutputMsg)
+	}
+}
+
+func (r *Recorder) appendEvent(kind, msg string) {
+	now := time.Now()
+	event := &goplay.CompileEvent{
+		Message: msg,
+		Kind:    kind,
+		Delay:   now.Sub(r.lastEvent),
+	}
+
+	r.events = append(r.events, event)
+	r.lastEvent = now
+	if r.onEvent == nil {
+		return
+	}
+
+	r.pending = append(r.pending, event)
+	select {
+	case r.notify <- struct{}{}:
+	default:
+		// Dispatcher is already notified about pending events.
+	}
+}
+
+// dispatch passes pending events to event handler until recorder is closed.
+func (r *Recorder) dispatch() {
+	defer close(r.done)
+	for range r.notify {
+		r.lock.Lock()
+		events := r.pending
+		r.pending = nil
+		r.lock.Unlock()
+
+		for _, e := range events {
+			r.onEvent(e)
+		}
+	}
+}
+
+type recorderWriter struct {
+	kind string
+	r    *Recorder
+}
+
+// Write implements io.Writer.
+//
+// Always reports success, even when output is truncated, to not break the program.
+func (w recorderWriter) Write(p []byte) (int, error) {
+	w.r.write(w.kind, p)
+	return len(p), nil
+}
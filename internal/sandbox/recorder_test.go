@@ -0,0 +1,108 @@
+package sandbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+func TestRecorder(t *testing.T) {
+	cases := map[string]struct {
+		maxSize   int
+		writes    [][2]string
+		expect    [][2]string
+		truncated bool
+	}{
+		"record events": {
+			writes: [][2]string{
+				{KindStdout, "foo"},
+				{KindStderr, "bar"},
+				{KindStdout, "baz"},
+			},
+			expect: [][2]string{
+				{KindStdout, "foo"},
+				{KindStderr, "bar"},
+				{KindStdout, "baz"},
+			},
+		},
+		"truncate output": {
+			maxSize:   5,
+			truncated: true,
+			writes: [][2]string{
+				{KindStdout, "foo"},
+				{KindStdout, "barbaz"},
+				{KindStderr, "ignored"},
+			},
+			expect: [][2]string{
+				{KindStdout, "foo"},
+				{KindStdout, "ba"},
+				{KindStderr, truncatedOutputMsg},
+			},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			var received []string
+			rec := NewRecorder(c.maxSize, func(e *goplay.CompileEvent) {
+				received = append(received, e.Message)
+			})
+			for _, w := range c.writes {
+				dst := rec.Stdout()
+				if w[0] == KindStderr {
+					dst = rec.Stderr()
+				}
+
+				n, err := dst.Write([]byte(w[1]))
+				require.NoError(t, err)
+				require.Equal(t, len(w[1]), n)
+			}
+
+			rec.Close()
+			events := rec.Events()
+			require.Len(t, events, len(c.expect))
+			for i, e := range events {
+				require.Equal(t, c.expect[i][0], e.Kind)
+				require.Equal(t, c.expect[i][1], e.Message)
+				require.GreaterOrEqual(t, e.Delay, time.Duration(0))
+				require.Equal(t, e.Message, received[i])
+			}
+			require.Equal(t, c.truncated, rec.Truncated())
+		})
+	}
+}
+
+func TestRecorder_SlowHandler(t *testing.T) {
+	unblock := make(chan struct{})
+	var received []string
+	rec := NewRecorder(0, func(e *goplay.CompileEvent) {
+		<-unblock
+		received = append(received, e.Message)
+	})
+
+	// Writes shouldn't wait for a blocked handler.
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		for _, msg := range []string{"foo", "bar", "baz"} {
+			_, _ = rec.Stdout().Write([]byte(msg))
+		}
+	}()
+
+	select {
+	case <-written:
+	case <-time.After(time.Second):
+		t.Fatal("write is blocked by event handler")
+	}
+
+	close(unblock)
+	rec.Close()
+	require.Equal(t, []string{"foo", "bar", "baz"}, received)
+
+	// Output after close is ignored.
+	_, _ = rec.Stdout().Write([]byte("ignored"))
+	require.Len(t, rec.Events(), 3)
+}
@@ -0,0 +1,412 @@
+// Package sandbox implements a self-hosted Go program run backend.
+//
+// Programs are compiled into native Linux executables and executed in a separate
+// process with resource limits, timeout and without network access.
+//
+// Program runs as an unprivileged user in own user, PID and mount namespaces
+// and sees only own workspace and read-only GOROOT.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/x1unix/go-playground/internal/builder"
+	"github.com/x1unix/go-playground/pkg/goplay"
+	"github.com/x1unix/go-playground/pkg/util/osutil"
+)
+
+const (
+	// BackendName is run backend name used to select sandbox in API requests.
+	BackendName = "local"
+
+	// ExitCodeTerminated is exit code reported when program was terminated
+	// without an exit code, e.g. by timeout.
+	ExitCodeTerminated = -1
+
+	targetOS   = "linux"
+	targetArch = "amd64"
+
+	binaryName      = "prog"
+	workDirPrefix   = "goplay-sandbox-"
+	rootDirPrefix   = "goplay-sandbox-root-"
+	defaultModName  = "app"
+	timeoutErrorMsg = "timeout running program"
+)
+
+var (
+	// ErrUnsupportedPlatform is returned when host platform can't run native sandbox programs.
+	ErrUnsupportedPlatform = fmt.Errorf("sandbox is supported only on %s/%s hosts", targetOS, targetArch)
+
+	// ErrPrivilegesRequired is returned when server can't switch programs to an unprivileged user.
+	ErrPrivilegesRequired = errors.New("sandbox requires server to run as root to start programs as an unprivileged user")
+)
+
+const (
+	// DefaultUID is default user ID used to run programs ("nobody").
+	DefaultUID = 65534
+
+	// DefaultGID is default group ID used to run programs ("nogroup").
+	DefaultGID = 65534
+)
+
+// buildVars is a list of environment variables used to build a program.
+var buildVars = osutil.EnvironmentVariables{
+	"CGO_ENABLED": "0",
+	"GOOS":        targetOS,
+	"GOARCH":      targetArch,
+	"HOME":        os.Getenv("HOME"),
+}
+
+// Config is sandbox configuration.
+type Config struct {
+	// WorkDir is a base directory for temporary program workspaces.
+	WorkDir string
+
+	// BuildTimeout is max time for program build.
+	BuildTimeout time.Duration
+
+	// RunTimeout is max program execution time.
+	RunTimeout time.Duration
+
+	// MaxOutputSize is max size of program output in bytes.
+	MaxOutputSize int
+
+	// Limits are resource limits applied to a program.
+	Limits Limits
+
+	// BuildEnvironment is a list of environment variables passed to Go compiler.
+	BuildEnvironment osutil.EnvironmentVariables
+
+	// BuildPool limits number of parallel builds.
+	//
+	// Pool is shared with other build services. Builds aren't limited if value is nil.
+	BuildPool *builder.BuildPool
+
+	// UID is user ID used to run programs.
+	//
+	// Programs can't be run as root, DefaultUID is used if value is zero.
+	UID int
+
+	// GID is group ID used to run programs.
+	//
+	// Programs can't be run as root, DefaultGID is used if value is zero.
+	GID int
+}
+
+// RunOptions are program run options.
+type RunOptions struct {
+	// Vet enables go vet check before run.
+	Vet bool
+
+	// CompilerOptions is a list of additional "go build" flags.
+	CompilerOptions []string
+
+	// ClientID identifies requester for fair build queue scheduling.
+	ClientID string
+
+	// OnEvent is optional handler to receive program output events as they arrive.
+	OnEvent EventHandler
+
+	// OnQueued is optional handler called with a queue position when build waits for a free worker.
+	OnQueued func(position int)
+}
+
+// Service builds and runs Go programs on a host inside a sandbox.
+type Service struct {
+	log    *zap.Logger
+	cfg    Config
+	goRoot string
+}
+
+// NewService constructs a new sandbox service.
+//
+// Returns ErrUnsupportedPlatform if host can't run sandboxed programs.
+func NewService(log *zap.Logger, cfg Config) (*Service, error) {
+	if runtime.GOOS != targetOS || runtime.GOARCH != targetArch {
+		return nil, ErrUnsupportedPlatform
+	}
+
+	// Only root can map a different user to a user namespace.
+	if os.Geteuid() != 0 {
+		return nil, ErrPrivilegesRequired
+	}
+
+	if cfg.WorkDir == "" {
+		cfg.WorkDir = os.TempDir()
+	}
+
+	if cfg.UID == 0 {
+		cfg.UID = DefaultUID
+	}
+
+	if cfg.GID == 0 {
+		cfg.GID = DefaultGID
+	}
+
+	s := &Service{
+		log: log.Named("sandbox"),
+		cfg: cfg,
+	}
+
+	// GOROOT is mounted into sandbox to provide time zones database and other runtime files.
+	goRoot, err := s.runGoTool(context.Background(), "", "env", "GOROOT")
+	if err != nil {
+		s.log.Warn("failed to locate GOROOT, programs will run without it", zap.Error(err))
+	}
+
+	s.goRoot = strings.TrimSpace(goRoot)
+	return s, nil
+}
+
+// Run builds and executes a program.
+//
+// Build errors and timeouts are reported in CompileResponse.Errors field
+// to keep behavior consistent with the Go Playground API.
+// Program exit code is returned in CompileResponse.Status field.
+func (s *Service) Run(ctx context.Context, files map[string][]byte, opts RunOptions) (*goplay.CompileResponse, error) {
+	workDir, err := os.MkdirTemp(s.cfg.WorkDir, workDirPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	defer func() {
+		if err := os.RemoveAll(workDir); err != nil {
+			s.log.Warn("failed to remove workspace", zap.String("dir", workDir), zap.Error(err))
+		}
+	}()
+
+	if err := writeWorkspace(workDir, files); err != nil {
+		return nil, err
+	}
+
+	isTest := hasTestFiles(files)
+	binPath := filepath.Join(workDir, binaryName)
+	buildOutput, err := s.build(ctx, workDir, binPath, isTest, opts)
+	if err != nil {
+		var buildErr *buildError
+		if errors.As(err, &buildErr) {
+			return &goplay.CompileResponse{Errors: buildErr.output}, nil
+		}
+
+		return nil, err
+	}
+
+	rec := NewRecorder(s.cfg.MaxOutputSize, opts.OnEvent)
+	defer rec.Close()
+	if buildOutput != "" {
+		// Include compiler diagnostics like escape analysis results.
+		_, _ = rec.Stderr().Write([]byte(buildOutput))
+	}
+
+	var args []string
+	if isTest {
+		args = append(args, "-test.v")
+	}
+
+	status, err := s.execute(ctx, rec, workDir, binPath, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.Close()
+	return &goplay.CompileResponse{Events: rec.Events(), Status: status}, nil
+}
+
+// build builds a program. Build waits for a free worker if build pool is set.
+//
+// Returns builder.QueueFullError if build queue is full.
+func (s *Service) build(ctx context.Context, workDir, binPath string, isTest bool, opts RunOptions) (string, error) {
+	if s.cfg.BuildPool != nil {
+		release, position, err := s.cfg.BuildPool.Acquire(ctx, opts.ClientID, opts.OnQueued)
+		if err != nil {
+			return "", err
+		}
+
+		defer release()
+		if position > 0 {
+			s.log.Debug("build was queued", zap.Int("position", position))
+		}
+	}
+
+	if s.cfg.BuildTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.BuildTimeout)
+		defer cancel()
+	}
+
+	if _, err := s.runGoTool(ctx, workDir, "mod", "tidy"); err != nil {
+		return "", err
+	}
+
+	if opts.Vet {
+		if _, err := s.runGoTool(ctx, workDir, "vet", "."); err != nil {
+			return "", err
+		}
+	}
+
+	args := []string{"build"}
+	if isTest {
+		args = []string{"test", "-c"}
+	}
+
+	args = append(args, opts.CompilerOptions...)
+	args = append(args, "-o", binPath)
+	if !isTest {
+		args = append(args, ".")
+	}
+
+	return s.runGoTool(ctx, workDir, args...)
+}
+
+func (s *Service) runGoTool(ctx context.Context, workDir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = workDir
+	cmd.Env = s.cfg.BuildEnvironment.Concat(buildVars).Join()
+
+	buff := new(bytes.Buffer)
+	cmd.Stderr = buff
+	cmd.Stdout = buff
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if errors.Is(ctxErr, context.DeadlineExceeded) {
+				return "", &buildError{output: "Go program build timeout exceeded"}
+			}
+
+			return "", ctxErr
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", &buildError{output: buff.String()}
+		}
+
+		return "", fmt.Errorf("failed to run %q: %w", strings.Join(cmd.Args, " "), err)
+	}
+
+	return buff.String(), nil
+}
+
+// execute runs a program and returns its exit code.
+func (s *Service) execute(ctx context.Context, rec *Recorder, workDir, binPath string, args ...string) (int, error) {
+	if s.cfg.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.RunTimeout)
+		defer cancel()
+	}
+
+	// Program runs as unprivileged user and needs write access to a workspace.
+	if err := os.Chown(workDir, s.cfg.UID, s.cfg.GID); err != nil {
+		return 0, fmt.Errorf("failed to change workspace owner: %w", err)
+	}
+
+	rootDir, err := os.MkdirTemp(s.cfg.WorkDir, rootDirPrefix)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create sandbox root: %w", err)
+	}
+
+	defer func() {
+		if err := os.Remove(rootDir); err != nil {
+			s.log.Warn("failed to remove sandbox root", zap.String("dir", rootDir), zap.Error(err))
+		}
+	}()
+
+	cmd, err := newSandboxedCommand(ctx, helperConfig{
+		Limits:  s.cfg.Limits,
+		RootDir: rootDir,
+		WorkDir: workDir,
+		GoRoot:  s.goRoot,
+		UID:     s.cfg.UID,
+		GID:     s.cfg.GID,
+	}, path.Join(sandboxWorkDir, filepath.Base(binPath)), args...)
+	if err != nil {
+		return 0, err
+	}
+
+	cmd.Dir = workDir
+	cmd.Env = append(cmd.Env, "HOME="+sandboxWorkDir, "TMPDIR="+sandboxWorkDir)
+	if s.goRoot != "" {
+		cmd.Env = append(cmd.Env, "GOROOT="+sandboxGoRoot)
+	}
+	cmd.Stdout = rec.Stdout()
+	cmd.Stderr = rec.Stderr()
+
+	err = cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			_, _ = rec.Stderr().Write([]byte("\n" + timeoutErrorMsg + "\n"))
+			return ExitCodeTerminated, nil
+		}
+
+		return 0, ctxErr
+	}
+
+	if err == nil {
+		return 0, nil
+	}
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return 0, fmt.Errorf("failed to start program: %w", err)
+	}
+
+	if exitErr.ExitCode() == exitCodeSetupFailed {
+		s.log.Error("sandbox helper process failed", zap.Error(err))
+	}
+
+	// Mimic "go run" behavior
+	_, _ = rec.Stderr().Write([]byte("\n" + exitErr.Error() + "\n"))
+	return exitErr.ExitCode(), nil
+}
+
+type buildError struct {
+	output string
+}
+
+func (e *buildError) Error() string {
+	return e.output
+}
+
+func hasTestFiles(files map[string][]byte) bool {
+	for name := range files {
+		if !strings.Contains(name, "/") && strings.HasSuffix(name, "_test.go") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeWorkspace(workDir string, files map[string][]byte) error {
+	if _, ok := files["go.mod"]; !ok {
+		// "go mod tidy" will populate Go version directive.
+		goMod := []byte("module " + defaultModName + "\n")
+		if err := os.WriteFile(filepath.Join(workDir, "go.mod"), goMod, 0644); err != nil {
+			return fmt.Errorf("failed to create go.mod: %w", err)
+		}
+	}
+
+	for name, data := range files {
+		fpath := filepath.Join(workDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+			return fmt.Errorf("can't create parent directory for %q: %w", name, err)
+		}
+
+		if err := os.WriteFile(fpath, data, 0644); err != nil {
+			return fmt.Errorf("failed to store file %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,299 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/x1unix/go-playground/internal/builder"
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+func TestMain(m *testing.M) {
+	// Test binary is used as a sandbox helper process.
+	Init()
+	os.Exit(m.Run())
+}
+
+func TestService_Run(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping sandbox test in short mode")
+	}
+
+	baseDir := t.TempDir()
+	escapeFile := filepath.Join(baseDir, "escape.txt")
+	cases := map[string]struct {
+		files      map[string]string
+		opts       RunOptions
+		wantErrors string
+		wantStatus int
+		check      func(t *testing.T, stdout, stderr string)
+	}{
+		"run program": {
+			files: map[string]string{
+				"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n",
+			},
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "hello\n", stdout)
+				require.Empty(t, stderr)
+			},
+		},
+		"report exit code": {
+			files: map[string]string{
+				"main.go": "package main\n\nimport \"os\"\n\nfunc main() {\n\tos.Stderr.WriteString(\"bye\\n\")\n\tos.Exit(3)\n}\n",
+			},
+			wantStatus: 3,
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Empty(t, stdout)
+				require.Equal(t, "bye\n\nexit status 3\n", stderr)
+			},
+		},
+		"report build errors": {
+			files: map[string]string{
+				"main.go": "package main\n\nfunc main() {\n\tfoo()\n}\n",
+			},
+			wantErrors: "undefined: foo",
+		},
+		"report vet errors": {
+			opts: RunOptions{Vet: true},
+			files: map[string]string{
+				"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Printf(\"%d\\n\", \"foo\")\n}\n",
+			},
+			wantErrors: "fmt.Printf format %d has arg",
+		},
+		"run tests": {
+			files: map[string]string{
+				"main_test.go": "package main\n\nimport \"testing\"\n\nfunc TestFoo(t *testing.T) {\n\tt.Log(\"works\")\n}\n",
+			},
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Contains(t, stdout, "--- PASS: TestFoo")
+			},
+		},
+		"report failed tests": {
+			files: map[string]string{
+				"main_test.go": "package main\n\nimport \"testing\"\n\nfunc TestFoo(t *testing.T) {\n\tt.Fatal(\"broken\")\n}\n",
+			},
+			wantStatus: 1,
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Contains(t, stdout, "--- FAIL: TestFoo")
+			},
+		},
+		"no network access": {
+			files: map[string]string{
+				"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"net\"\n)\n\nfunc main() {\n\t_, err := net.Dial(\"tcp\", \"1.1.1.1:80\")\n\tfmt.Println(err != nil)\n}\n",
+			},
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "true\n", stdout)
+			},
+		},
+		"isolate from host": {
+			files: map[string]string{
+				"main.go": fmt.Sprintf(isolationTestProgram, os.Getpid(), escapeFile),
+			},
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Empty(t, stderr)
+				require.Equal(t, strings.Join([]string{
+					"parent proc: not exist",
+					"write outside workspace: not exist",
+					"write to root: read-only file system",
+					"write to workspace: <nil>",
+					"uid: 65534",
+					"pid: 1",
+				}, "\n")+"\n", stdout)
+				require.NoFileExists(t, escapeFile)
+			},
+		},
+		"limit processes": {
+			files: map[string]string{
+				"main.go": forkTestProgram,
+			},
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "resource temporarily unavailable\n", stdout)
+			},
+		},
+		"limit memory": {
+			files: map[string]string{
+				"main.go": "package main\n\nfunc main() {\n\tvar s [][]byte\n\tfor i := 0; i < 100; i++ {\n\t\ts = append(s, make([]byte, 10<<20))\n\t}\n\tprintln(len(s))\n}\n",
+			},
+			wantStatus: 2,
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Contains(t, stderr, "out of memory")
+			},
+		},
+		"kill on timeout": {
+			files: map[string]string{
+				"main.go": "package main\n\nfunc main() {\n\tfor {\n\t}\n}\n",
+			},
+			wantStatus: ExitCodeTerminated,
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Contains(t, stderr, timeoutErrorMsg)
+			},
+		},
+	}
+
+	svc, err := NewService(zaptest.NewLogger(t), Config{
+		WorkDir:       baseDir,
+		RunTimeout:    3 * time.Second,
+		MaxOutputSize: 1024,
+		Limits: Limits{
+			CPUTime:   10 * time.Second,
+			Memory:    256 << 20,
+			OpenFiles: 64,
+			Processes: 32,
+		},
+		BuildEnvironment: map[string]string{
+			"PATH":        os.Getenv("PATH"),
+			"GOCACHE":     os.Getenv("GOCACHE"),
+			"GOMODCACHE":  os.Getenv("GOMODCACHE"),
+			"GOFLAGS":     "-mod=mod",
+			"GOTOOLCHAIN": "local",
+		},
+	})
+	if err == ErrUnsupportedPlatform || err == ErrPrivilegesRequired {
+		t.Skip(err)
+	}
+	require.NoError(t, err)
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			files := make(map[string][]byte, len(c.files))
+			for k, v := range c.files {
+				files[k] = []byte(v)
+			}
+
+			rsp, err := svc.Run(context.Background(), files, c.opts)
+			require.NoError(t, err)
+			if c.wantErrors != "" {
+				require.Contains(t, rsp.Errors, c.wantErrors)
+				return
+			}
+
+			require.Empty(t, rsp.Errors)
+			require.Equal(t, c.wantStatus, rsp.Status)
+			stdout, stderr := joinEvents(rsp.Events)
+			c.check(t, stdout, stderr)
+		})
+	}
+}
+
+func TestService_Run_BuildPool(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping sandbox test in short mode")
+	}
+
+	pool := builder.NewBuildPool(builder.PoolConfig{MaxWorkers: 1})
+	svc, err := NewService(zaptest.NewLogger(t), Config{
+		WorkDir:   t.TempDir(),
+		BuildPool: pool,
+		BuildEnvironment: map[string]string{
+			"PATH":        os.Getenv("PATH"),
+			"GOCACHE":     os.Getenv("GOCACHE"),
+			"GOMODCACHE":  os.Getenv("GOMODCACHE"),
+			"GOFLAGS":     "-mod=mod",
+			"GOTOOLCHAIN": "local",
+		},
+	})
+	if err == ErrUnsupportedPlatform || err == ErrPrivilegesRequired {
+		t.Skip(err)
+	}
+	require.NoError(t, err)
+
+	files := map[string][]byte{
+		"main.go": []byte("package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n"),
+	}
+
+	release, _, err := pool.Acquire(context.Background(), "other", nil)
+	require.NoError(t, err)
+
+	_, err = svc.Run(context.Background(), files, RunOptions{ClientID: "client"})
+	require.ErrorAs(t, err, new(builder.QueueFullError))
+
+	release()
+	rsp, err := svc.Run(context.Background(), files, RunOptions{ClientID: "client"})
+	require.NoError(t, err)
+	require.Empty(t, rsp.Errors)
+}
+
+// isolationTestProgram tries to access host resources outside a workspace.
+const isolationTestProgram = `package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+func report(name string, err error) {
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Println(name+":", "not exist")
+		return
+	}
+
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+
+	fmt.Println(name+":", err)
+}
+
+func main() {
+	_, err := os.ReadFile("/proc/%d/environ")
+	report("parent proc", err)
+	report("write outside workspace", os.WriteFile(%q, []byte("escape"), 0644))
+	report("write to root", os.WriteFile("/escape.txt", []byte("escape"), 0644))
+	report("write to workspace", os.WriteFile("file.txt", []byte("ok"), 0644))
+	fmt.Println("uid:", os.Getuid())
+	fmt.Println("pid:", os.Getpid())
+}
+`
+
+// forkTestProgram starts copies of itself until the process limit is reached.
+const forkTestProgram = `package main
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+func main() {
+	if len(os.Args) > 1 {
+		time.Sleep(time.Minute)
+		return
+	}
+
+	for i := 0; i < 100; i++ {
+		_, err := syscall.ForkExec(os.Args[0], []string{os.Args[0], "child"}, nil)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(0)
+		}
+	}
+
+	fmt.Println("limit not reached")
+}
+`
+
+func joinEvents(events []*goplay.CompileEvent) (string, string) {
+	var stdout, stderr strings.Builder
+	for _, e := range events {
+		switch e.Kind {
+		case KindStdout:
+			stdout.WriteString(e.Message)
+		case KindStderr:
+			stderr.WriteString(e.Message)
+		}
+	}
+
+	return stdout.String(), stderr.String()
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/x1unix/go-playground/internal/builder"
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+// diagnosticsError is an error which contains a list of source file diagnostics.
+type diagnosticsError interface {
+	error
+	Diagnostics() []builder.Diagnostic
+}
+
+// errorDiagnostics returns a list of diagnostics from an error chain, if any.
+func errorDiagnostics(err error) []builder.Diagnostic {
+	var diagErr diagnosticsError
+	if errors.As(err, &diagErr) {
+		return diagErr.Diagnostics()
+	}
+
+	return nil
+}
+
+// CompileError is a program compile error reported by a run backend.
+type CompileError struct {
+	message     string
+	diagnostics []builder.Diagnostic
+}
+
+// Error implements error
+func (e *CompileError) Error() string {
+	return e.message
+}
+
+// Diagnostics returns list of diagnostics mapped to request files.
+func (e *CompileError) Diagnostics() []builder.Diagnostic {
+	return e.diagnostics
+}
+
+// compileErrorFromResponse returns compile error from a run response.
+//
+// Go Playground reports errors for a single-file snippet as "prog.go" errors,
+// such errors are mapped back to an original file name.
+//
+// Multi-file snippets always contain "-- name --" file headers, so their errors
+// already refer to request files and are passed as is.
+func compileErrorFromResponse(res *goplay.CompileResponse, body *FilesPayload, params RunParams) error {
+	var snippet string
+	if !params.IsLocal() {
+		// Payload is deterministic, the same snippet is produced as the one sent to Go Playground.
+		src, err := evalPayloadFromFiles(body)
+		if err == nil {
+			snippet = string(src)
+		}
+	}
+
+	diagnostics := builder.ParseDiagnostics(res.Errors, "", builder.SeverityError)
+	return NewBadRequestError(&CompileError{
+		message:     res.Errors,
+		diagnostics: mapSnippetDiagnostics(diagnostics, body.Files, snippet),
+	})
+}
+
+// mapSnippetDiagnostics maps Go Playground file names and line numbers to request files.
+//
+// Only "prog.go" diagnostics of a single-file snippet need to be mapped,
+// diagnostics of multi-file snippets already refer to request files.
+// Diagnostics for files which aren't present in request are skipped.
+func mapSnippetDiagnostics(diagnostics []builder.Diagnostic, files map[string]string, snippet string) []builder.Diagnostic {
+	_, hasProgFile := files[goplay.DefaultProgramFileName]
+
+	var defaultFileName string
+	if len(files) == 1 {
+		for name := range files {
+			defaultFileName = name
+		}
+	}
+
+	mapped := make([]builder.Diagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		if d.File == goplay.DefaultProgramFileName && !hasProgFile && snippet != "" {
+			fileName, line, ok := goplay.LocateLine(snippet, d.Line)
+			if !ok {
+				continue
+			}
+
+			if fileName == "" {
+				fileName = defaultFileName
+			}
+
+			d.File = fileName
+			d.Line = line
+		}
+
+		if _, ok := files[d.File]; !ok {
+			continue
+		}
+
+		mapped = append(mapped, d)
+	}
+
+	return mapped
+}
@@ -18,6 +18,7 @@ var ErrSnippetTooLarge = Errorf(
 type HTTPError struct {
 	code   int
 	parent error
+	header http.Header
 }
 
 // Error implements error
@@ -30,9 +31,28 @@ func (err *HTTPError) Unwrap() error {
 	return err.parent
 }
 
+// WithHeader sets a response header which is sent with an error.
+func (err *HTTPError) WithHeader(key, value string) *HTTPError {
+	if err.header == nil {
+		err.header = make(http.Header)
+	}
+
+	err.header.Set(key, value)
+	return err
+}
+
 // WriteResponse writes error to response
 func (err *HTTPError) WriteResponse(rw http.ResponseWriter) {
-	resp := ErrorResponse{code: err.code, Error: err.parent.Error()}
+	for key, values := range err.header {
+		rw.Header()[key] = values
+	}
+
+	resp := ErrorResponse{
+		code:        err.code,
+		Error:       err.parent.Error(),
+		Diagnostics: errorDiagnostics(err.parent),
+	}
+
 	resp.Write(rw)
 }
 
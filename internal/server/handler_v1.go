@@ -6,7 +6,6 @@ import (
 	"io"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/x1unix/go-playground/internal/announcements"
@@ -15,14 +14,9 @@ import (
 	"github.com/x1unix/go-playground/internal/server/backendinfo"
 	"github.com/x1unix/go-playground/pkg/goplay"
 	"go.uber.org/zap"
-	"golang.org/x/time/rate"
 )
 
 const (
-	// limit for wasm compile requests per second (250ms per request)
-	compileRequestsPerFrame = 4
-	frameTime               = time.Second
-
 	wasmMimeType     = "application/wasm"
 	artifactParamVal = "artifactId"
 )
@@ -34,8 +28,7 @@ type APIv1Handler struct {
 	compiler        builder.BuildService
 	versionProvider backendinfo.BackendVersionProvider
 
-	client  *goplay.Client
-	limiter *rate.Limiter
+	client *goplay.Client
 }
 
 type ServiceConfig struct {
@@ -51,7 +44,6 @@ func NewAPIv1Handler(cfg ServiceConfig, client *goplay.Client, builder builder.B
 		client:          client,
 		log:             zap.S().Named("api.v1"),
 		versionProvider: versionProvider,
-		limiter:         rate.NewLimiter(rate.Every(frameTime), compileRequestsPerFrame),
 	}
 }
 
@@ -90,7 +82,8 @@ func (s *APIv1Handler) HandleGetVersions(w http.ResponseWriter, r *http.Request)
 	}
 
 	rsp := VersionsInformation{
-		WebAssembly: s.versionProvider.ServerVersion(),
+		WebAssembly:         s.compiler.Toolchains().Default().Version,
+		WebAssemblyVersions: s.compiler.Toolchains().Versions(),
 		Playground: &PlaygroundVersions{
 			GoCurrent:  versions.CurrentStable,
 			GoPrevious: versions.PreviousStable,
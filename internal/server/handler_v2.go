@@ -4,16 +4,28 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"math"
 	"net/http"
+	"net/netip"
+	"slices"
+	"strconv"
 	"time"
 
-	"golang.org/x/time/rate"
-
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
 	"github.com/x1unix/go-playground/internal/builder"
+	"github.com/x1unix/go-playground/internal/builder/storage"
+	"github.com/x1unix/go-playground/internal/formatter"
+	"github.com/x1unix/go-playground/internal/modinfo"
+	"github.com/x1unix/go-playground/internal/pkgindex/modindex"
+	"github.com/x1unix/go-playground/internal/sandbox"
+	"github.com/x1unix/go-playground/internal/snippets"
+	"github.com/x1unix/go-playground/internal/wasmrun"
 	"github.com/x1unix/go-playground/pkg/goplay"
+	"github.com/x1unix/go-playground/pkg/goproxy"
 )
 
 var ErrEmptyRequest = errors.New("empty request")
@@ -21,7 +33,46 @@ var ErrEmptyRequest = errors.New("empty request")
 type APIv2HandlerConfig struct {
 	Client       *goplay.Client
 	Builder      builder.BuildService
+	Snippets     snippets.Store
 	BuildTimeout time.Duration
+
+	// TrustedProxies is a list of reverse proxy networks which are allowed to set client address headers.
+	//
+	// Client address headers are ignored if list is empty.
+	TrustedProxies []netip.Prefix
+
+	// Sandbox is optional local run backend.
+	//
+	// Local backend is disabled if value is nil.
+	Sandbox *sandbox.Service
+
+	// WasmExecutor is optional run backend which executes WebAssembly programs on a server.
+	//
+	// Backend is disabled if value is nil.
+	WasmExecutor *wasmrun.Executor
+
+	// Formatter is optional local code formatter.
+	//
+	// Code is always formatted by Go Playground if value is nil.
+	Formatter *formatter.Formatter
+
+	// DefaultFormatter is formatter provider used if request doesn't specify it.
+	//
+	// Go Playground is used if value is empty.
+	DefaultFormatter string
+
+	// FormatLocalPrefix is default comma-separated list of local import path prefixes for goimports style.
+	FormatLocalPrefix string
+
+	// Modules is optional third-party modules index service.
+	//
+	// Modules index endpoint is disabled if value is nil.
+	Modules *modindex.Service
+
+	// ModuleVersions is optional module versions service.
+	//
+	// Module versions endpoints are disabled if value is nil.
+	ModuleVersions *modinfo.Service
 }
 
 func (cfg APIv2HandlerConfig) buildContext(parentCtx context.Context) (context.Context, context.CancelFunc) {
@@ -33,16 +84,14 @@ func (cfg APIv2HandlerConfig) buildContext(parentCtx context.Context) (context.C
 }
 
 type APIv2Handler struct {
-	logger  *zap.Logger
-	limiter *rate.Limiter
-	cfg     APIv2HandlerConfig
+	logger *zap.Logger
+	cfg    APIv2HandlerConfig
 }
 
 func NewAPIv2Handler(cfg APIv2HandlerConfig) *APIv2Handler {
 	return &APIv2Handler{
-		logger:  zap.L().Named("api.v2"),
-		cfg:     cfg,
-		limiter: rate.NewLimiter(rate.Every(frameTime), compileRequestsPerFrame),
+		logger: zap.L().Named("api.v2"),
+		cfg:    cfg,
 	}
 }
 
@@ -51,9 +100,9 @@ func (h *APIv2Handler) HandleGetSnippet(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	snippetID := vars["id"]
 
-	snippet, err := h.cfg.Client.GetSnippet(r.Context(), snippetID)
+	snippet, err := h.cfg.Snippets.GetSnippet(r.Context(), snippetID)
 	if err != nil {
-		if errors.Is(err, goplay.ErrSnippetNotFound) {
+		if errors.Is(err, snippets.ErrNotFound) {
 			return Errorf(http.StatusNotFound, "snippet %q not found", snippetID)
 		}
 
@@ -61,7 +110,7 @@ func (h *APIv2Handler) HandleGetSnippet(w http.ResponseWriter, r *http.Request)
 		return err
 	}
 
-	files, err := goplay.SplitFileSet(snippet.Contents, goplay.SplitFileOpts{
+	files, err := goplay.SplitFileSet(string(snippet.Contents), goplay.SplitFileOpts{
 		DefaultFileName: "main.go",
 		CheckPaths:      false,
 	})
@@ -70,26 +119,49 @@ func (h *APIv2Handler) HandleGetSnippet(w http.ResponseWriter, r *http.Request)
 		h.logger.Error(
 			"Cannot split snippet to files",
 			zap.Error(err),
-			zap.String("contents", snippet.Contents),
+			zap.ByteString("contents", snippet.Contents),
 			zap.String("snippetID", snippetID),
 		)
-		files = map[string]string{snippet.FileName: snippet.Contents}
+		files = map[string]string{snippetID + ".go": string(snippet.Contents)}
 	}
 
-	WriteJSON(w, FilesPayload{Files: files})
+	WriteJSON(w, FilesPayload{
+		Files:           files,
+		CompilerOptions: snippet.CompilerOptions,
+		Backend:         snippet.Backend,
+		Vet:             snippet.Vet,
+	})
 	return nil
 }
 
 // HandleShare handles snippet share requests.
+//
+// Compiler options, backend and vet settings are saved with a snippet.
 func (h *APIv2Handler) HandleShare(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 
-	payload, _, err := fileSetFromRequest(r)
+	body, err := filesPayloadFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	if !isServerBackend(body.Backend) && !goplay.ValidateBackend(body.Backend) {
+		return Errorf(http.StatusBadRequest, "invalid backend name %q", body.Backend)
+	}
+
+	payload, err := fileSetFromPayload(body)
 	if err != nil {
 		return err
 	}
 
-	snippetID, err := h.cfg.Client.Share(ctx, payload.Reader())
+	snippetID, err := h.cfg.Snippets.SaveSnippet(ctx, &snippets.Snippet{
+		Metadata: snippets.Metadata{
+			CompilerOptions: body.CompilerOptions,
+			Backend:         body.Backend,
+			Vet:             body.Vet,
+		},
+		Contents: payload.Bytes(),
+	})
 	if err != nil {
 		if isContentLengthError(err) {
 			return ErrSnippetTooLarge
@@ -104,48 +176,132 @@ func (h *APIv2Handler) HandleShare(w http.ResponseWriter, r *http.Request) error
 }
 
 // HandleFormat handles gofmt requests.
+//
+// Code is formatted by Go Playground unless local formatter or style other than goimports is requested.
+// Go Playground is used as a fallback if local formatter is not available.
+//
+// Depending on "result" query parameter, formatted files, text edits or both are returned.
 func (h *APIv2Handler) HandleFormat(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
+	query := r.URL.Query()
+
+	backend, err := backendFromQuery(query)
+	if err != nil {
+		return NewBadRequestError(err)
+	}
+
+	provider, err := formatterFromQuery(query, h.cfg.DefaultFormatter)
+	if err != nil {
+		return NewBadRequestError(err)
+	}
 
-	backend, err := backendFromQuery(r.URL.Query())
+	opts, err := formatOptionsFromQuery(query, h.cfg.FormatLocalPrefix)
+	if err != nil {
+		return NewBadRequestError(err)
+	}
+
+	resultType, err := formatResultFromQuery(query)
 	if err != nil {
 		return NewBadRequestError(err)
 	}
 
 	defer r.Body.Close()
-	payload, fileNames, err := fileSetFromRequest(r)
+	body, err := filesPayloadFromRequest(r)
+	if err != nil {
+		return err
+	}
+
+	payload, err := fileSetFromPayload(body)
+	if err != nil {
+		return err
+	}
+
+	var results map[string]string
+	if f := h.localFormatter(provider, opts); f != nil {
+		results, err = formatLocal(f, body.Files, opts)
+	} else {
+		results, err = h.formatUpstream(ctx, payload, body.Files, backend, opts)
+	}
 	if err != nil {
 		return err
 	}
 
+	WriteJSON(w, NewFormatResponse(body.Files, results, resultType))
+	return nil
+}
+
+// localFormatter returns formatter to format code on a server.
+//
+// Returns nil if code should be formatted by Go Playground.
+func (h *APIv2Handler) localFormatter(provider string, opts formatter.Options) *formatter.Formatter {
+	// Go Playground supports only goimports style without any extra options.
+	upstreamSupported := opts.Style == formatter.StyleGoimports && opts.LocalPrefix == ""
+	if h.cfg.Formatter != nil && (provider == formatter.ProviderLocal || !upstreamSupported) {
+		return h.cfg.Formatter
+	}
+
+	if opts.Style != formatter.StyleGoimports {
+		// Other styles don't resolve imports and don't need standard library index.
+		return formatter.NewFormatter(nil)
+	}
+
+	if provider == formatter.ProviderLocal || !upstreamSupported {
+		h.logger.Debug("local formatter is not available, falling back to upstream")
+	}
+
+	return nil
+}
+
+func (h *APIv2Handler) formatUpstream(
+	ctx context.Context, payload *goplay.FileSet, files map[string]string, backend goplay.Backend, opts formatter.Options,
+) (map[string]string, error) {
 	rsp, err := h.cfg.Client.GoImports(ctx, payload.Bytes(), backend)
 	if err != nil {
 		if isContentLengthError(err) {
-			return ErrSnippetTooLarge
+			return nil, ErrSnippetTooLarge
 		}
 
 		h.logger.Error("goimports error", zap.Error(err))
-		return err
+		return nil, err
 	}
 
 	if err := rsp.HasError(); err != nil {
-		return NewBadRequestError(err)
+		return nil, NewBadRequestError(err)
 	}
 
 	results, err := goplay.SplitFileSet(rsp.Body, goplay.SplitFileOpts{
-		DefaultFileName: fileNames[0],
+		DefaultFileName: slices.Min(slices.Collect(maps.Keys(files))),
 		CheckPaths:      true,
 	})
+	if err == nil {
+		return results, nil
+	}
+
+	h.logger.Error("failed to reconstruct files set from format response", zap.Error(err), zap.String("rsp", rsp.Body))
+	if h.cfg.Formatter != nil {
+		h.logger.Debug("falling back to local formatter")
+		return formatLocal(h.cfg.Formatter, files, opts)
+	}
+
+	return nil, NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to reconstruct files set from format response: %w", err))
+}
+
+func formatLocal(f *formatter.Formatter, files map[string]string, opts formatter.Options) (map[string]string, error) {
+	results, err := f.Format(files, opts)
 	if err != nil {
-		h.logger.Error("failed to reconstruct files set from format response", zap.Error(err), zap.String("rsp", rsp.Body))
-		return NewHTTPError(http.StatusInternalServerError, fmt.Errorf("failed to reconstruct files set from format response: %w", err))
+		return nil, NewBadRequestError(err)
 	}
 
-	WriteJSON(w, FilesPayload{Files: results})
-	return nil
+	return results, nil
 }
 
 // HandleRun sends snippet to upstream play.go.dev and returns evaluation result.
+//
+// If local or wasm-server backend is requested, snippet is executed on a server.
+//
+// Clients that accept "text/event-stream" receive output as Server-Sent Events.
+//
+// Compile errors are returned with a list of diagnostics mapped to request file names.
 func (h *APIv2Handler) HandleRun(w http.ResponseWriter, r *http.Request) error {
 	ctx := r.Context()
 
@@ -156,72 +312,289 @@ func (h *APIv2Handler) HandleRun(w http.ResponseWriter, r *http.Request) error {
 		return NewBadRequestError(err)
 	}
 
-	snippet, err := evalPayloadFromRequest(r)
+	params.ClientID = clientIDFromRequest(r, h.cfg.TrustedProxies)
+	body, err := filesPayloadFromRequest(r)
 	if err != nil {
 		return NewBadRequestError(err)
 	}
 
-	h.logger.Debug("evaluating snippet", zap.String("snippet", string(snippet)))
-	res, err := h.cfg.Client.Evaluate(ctx, goplay.CompileRequest{
-		Version: goplay.DefaultVersion,
-		WithVet: params.Vet,
-		Body:    snippet,
-	}, params.Backend)
+	if acceptsEventStream(r) {
+		return h.streamRun(ctx, w, body, params)
+	}
+
+	var res *goplay.CompileResponse
+	if params.IsLocal() {
+		res, err = h.runOnServer(ctx, body, params, nil, nil)
+	} else {
+		res, err = h.runRemote(ctx, body, params)
+	}
 	if err != nil {
 		return err
 	}
 
-	if err := res.HasError(); err != nil {
-		return NewBadRequestError(err)
+	if res.HasError() != nil {
+		return compileErrorFromResponse(res, body, params)
 	}
 
 	h.logger.Debug("response from compiler", zap.Any("res", res))
 	WriteJSON(w, RunResponse{
 		Events: res.Events,
+		Status: res.Status,
 	})
 
 	return nil
 }
 
+// streamRun runs a program and sends output events as soon as they arrive.
+//
+// Upstream playground doesn't support streaming, so remote backend events are sent after evaluation.
+// Errors that occurred before the first event are returned as regular error responses.
+func (h *APIv2Handler) streamRun(ctx context.Context, w http.ResponseWriter, body *FilesPayload, params RunParams) error {
+	stream := NewEventStream(w)
+
+	// Queue handler might be called from a shared build after request is done.
+	defer stream.Close()
+
+	var (
+		res *goplay.CompileResponse
+		err error
+	)
+	if params.IsLocal() {
+		onQueued := func(position int) {
+			_ = stream.Send(StreamEventQueued, QueueEvent{Position: position})
+		}
+		res, err = h.runOnServer(ctx, body, params, func(e *goplay.CompileEvent) {
+			// Client disconnect cancels the request context which stops the program.
+			_ = stream.Send(StreamEventOutput, e)
+		}, onQueued)
+	} else {
+		res, err = h.runRemote(ctx, body, params)
+		if err == nil && res.HasError() == nil {
+			for _, e := range res.Events {
+				if err := stream.Send(StreamEventOutput, e); err != nil {
+					return nil
+				}
+			}
+		}
+	}
+
+	if err == nil && res.HasError() != nil {
+		err = compileErrorFromResponse(res, body, params)
+	}
+
+	if err != nil {
+		if !stream.Started() {
+			return err
+		}
+
+		_ = stream.SendError(err)
+		return nil
+	}
+
+	_ = stream.Send(StreamEventDone, DoneEvent{Status: res.Status})
+	return nil
+}
+
+func (h *APIv2Handler) runRemote(ctx context.Context, body *FilesPayload, params RunParams) (*goplay.CompileResponse, error) {
+	snippet, err := evalPayloadFromFiles(body)
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	h.logger.Debug("evaluating snippet", zap.String("snippet", string(snippet)))
+	return h.cfg.Client.Evaluate(ctx, goplay.CompileRequest{
+		Version: goplay.DefaultVersion,
+		WithVet: params.Vet,
+		Body:    snippet,
+	}, params.Backend)
+}
+
+// runOnServer runs a program using one of server-side run backends.
+//
+// Optional onQueued handler is called with a queue position if build waits for a free worker.
+func (h *APIv2Handler) runOnServer(ctx context.Context, body *FilesPayload, params RunParams, onEvent sandbox.EventHandler, onQueued func(position int)) (*goplay.CompileResponse, error) {
+	if params.Backend == wasmrun.BackendName {
+		return h.runWasm(ctx, body, params, onEvent, onQueued)
+	}
+
+	return h.runLocal(ctx, body, params, onEvent, onQueued)
+}
+
+func (h *APIv2Handler) runLocal(ctx context.Context, body *FilesPayload, params RunParams, onEvent sandbox.EventHandler, onQueued func(position int)) (*goplay.CompileResponse, error) {
+	if h.cfg.Sandbox == nil {
+		return nil, Errorf(http.StatusNotImplemented, "local run backend is disabled on this server")
+	}
+
+	compilerOptions, err := builder.ParseCompilerOptions(body.CompilerOptions)
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	res, err := h.cfg.Sandbox.Run(ctx, body.ByteFiles(), sandbox.RunOptions{
+		Vet:             params.Vet,
+		CompilerOptions: compilerOptions,
+		ClientID:        params.ClientID,
+		OnEvent:         onEvent,
+		OnQueued:        onQueued,
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, NewHTTPError(http.StatusBadRequest, err)
+		}
+
+		var queueErr builder.QueueFullError
+		if errors.As(err, &queueErr) {
+			return nil, newQueueFullError(err, queueErr)
+		}
+
+		h.logger.Error("failed to run program in sandbox", zap.Error(err))
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// runWasm builds a program for WebAssembly and executes it on a server.
+//
+// Programs are built for WASI unless js/wasm target is requested.
+func (h *APIv2Handler) runWasm(ctx context.Context, body *FilesPayload, params RunParams, onEvent sandbox.EventHandler, onQueued func(position int)) (*goplay.CompileResponse, error) {
+	if h.cfg.WasmExecutor == nil {
+		return nil, Errorf(http.StatusNotImplemented, "%s run backend is disabled on this server", wasmrun.BackendName)
+	}
+
+	compilerOptions, err := builder.ParseCompilerOptions(body.CompilerOptions)
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	target := builder.TargetWASI
+	if body.Target != "" {
+		target, err = builder.ParseBuildTarget(body.Target)
+		if err != nil {
+			return nil, NewBadRequestError(err)
+		}
+	}
+
+	buildCtx, cancel := h.cfg.buildContext(ctx)
+	defer cancel()
+
+	result, err := h.cfg.Builder.Build(buildCtx, body.ByteFiles(), builder.BuildOptions{
+		CompilerOptions: compilerOptions,
+		ClientID:        params.ClientID,
+		GoVersion:       body.GoVersion,
+		Target:          target,
+		OnQueued:        onQueued,
+	})
+	if err != nil {
+		if builder.IsBuildError(err) {
+			return &goplay.CompileResponse{Errors: err.Error()}, nil
+		}
+
+		var queueErr builder.QueueFullError
+		if errors.As(err, &queueErr) {
+			return nil, newQueueFullError(err, queueErr)
+		}
+
+		if errors.Is(err, builder.ErrUnsupportedGoVersion) || errors.Is(err, context.Canceled) {
+			return nil, NewHTTPError(http.StatusBadRequest, err)
+		}
+
+		return nil, err
+	}
+
+	program, err := h.readArtifact(result.ArtifactID())
+	if err != nil {
+		return nil, err
+	}
+
+	var args []string
+	if result.IsTest {
+		args = append(args, "-test.v")
+	}
+
+	res, err := h.cfg.WasmExecutor.Run(ctx, program, wasmrun.RunOptions{
+		Args:           args,
+		CompilerOutput: result.CompilerOutput,
+		OnEvent:        onEvent,
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return nil, NewHTTPError(http.StatusBadRequest, err)
+		}
+
+		h.logger.Error("failed to run WebAssembly program", zap.Error(err))
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// newQueueFullError returns service unavailable error with a retry delay suggested by build queue.
+func newQueueFullError(err error, queueErr builder.QueueFullError) *HTTPError {
+	retryAfter := strconv.Itoa(int(math.Ceil(queueErr.RetryAfter.Seconds())))
+	return NewHTTPError(http.StatusServiceUnavailable, err).WithHeader("Retry-After", retryAfter)
+}
+
+func (h *APIv2Handler) readArtifact(id storage.ArtifactID) ([]byte, error) {
+	artifact, err := h.cfg.Builder.GetArtifact(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get artifact %s: %w", id, err)
+	}
+
+	defer artifact.Close()
+	return io.ReadAll(artifact)
+}
+
 // HandleCompile handles WebAssembly compile requests.
 func (h *APIv2Handler) HandleCompile(w http.ResponseWriter, r *http.Request) error {
 	// Limit for request timeout
 	ctx, cancel := h.cfg.buildContext(r.Context())
 	defer cancel()
 
-	// Wait for our queue in line for compilation
-	if err := h.limiter.Wait(ctx); err != nil {
-		return NewHTTPError(http.StatusTooManyRequests, err)
-	}
-
 	h.logger.Debug("handling compile request")
 	h.logger.Debug("parsing compile parameters from query", zap.Any("query", r))
-	files, compilerOptions, err := buildFilesFromRequest(r)
+	body, err := filesPayloadFromRequest(r)
 	if err != nil {
 		return err
 	}
 
+	files := body.ByteFiles()
 	h.logger.Debug("built files", zap.Any("files", files))
-	parsedCompilerOptions, err := builder.ParseCompilerOptions(compilerOptions)
+	parsedCompilerOptions, err := builder.ParseCompilerOptions(body.CompilerOptions)
+	if err != nil {
+		return NewBadRequestError(err)
+	}
+
+	target, err := builder.ParseBuildTarget(body.Target)
 	if err != nil {
 		return NewBadRequestError(err)
 	}
 
 	result, err := h.cfg.Builder.Build(ctx, files, builder.BuildOptions{
 		CompilerOptions: parsedCompilerOptions,
+		ClientID:        clientIDFromRequest(r, h.cfg.TrustedProxies),
+		GoVersion:       body.GoVersion,
+		Target:          target,
 	})
 	if err != nil {
-		if builder.IsBuildError(err) || errors.Is(err, context.Canceled) {
+		if builder.IsBuildError(err) || errors.Is(err, builder.ErrUnsupportedGoVersion) || errors.Is(err, context.Canceled) {
 			return NewHTTPError(http.StatusBadRequest, err)
 		}
 
+		var queueErr builder.QueueFullError
+		if errors.As(err, &queueErr) {
+			return newQueueFullError(err, queueErr)
+		}
+
 		return err
 	}
 	h.logger.Debug("build result", zap.Any("result", result))
 
 	WriteJSON(w, BuildResponseV2{
 		FileName:       result.FileName,
+		Target:         string(result.Target),
 		CompilerOutput: result.CompilerOutput,
+		Diagnostics:    result.Diagnostics,
 		IsTest:         result.IsTest,
 		HasBenchmark:   result.HasBenchmark,
 		HasFuzz:        result.HasFuzz,
@@ -229,10 +602,110 @@ func (h *APIv2Handler) HandleCompile(w http.ResponseWriter, r *http.Request) err
 	return nil
 }
 
+// HandleGetModuleIndex handles requests to get symbols index of a third-party module.
+//
+// Latest module version is used if version query parameter is empty.
+func (h *APIv2Handler) HandleGetModuleIndex(w http.ResponseWriter, r *http.Request) error {
+	if h.cfg.Modules == nil {
+		return Errorf(http.StatusNotFound, "modules index is disabled")
+	}
+
+	query := r.URL.Query()
+	modPath, version := query.Get("module"), query.Get("version")
+	data, err := h.cfg.Modules.GetModuleIndex(r.Context(), modPath, version)
+	if err != nil {
+		var validationErr goproxy.ValidationError
+		switch {
+		case errors.As(err, &validationErr):
+			return NewBadRequestError(err)
+		case errors.Is(err, goproxy.ErrModuleNotFound):
+			return NewHTTPError(http.StatusNotFound, err)
+		case errors.Is(err, goproxy.ErrPrivateModule):
+			return NewHTTPError(http.StatusForbidden, err)
+		case errors.Is(err, modindex.ErrModuleTooLarge):
+			return NewHTTPError(http.StatusUnprocessableEntity, err)
+		case errors.Is(err, context.Canceled):
+			return err
+		}
+
+		h.logger.Error("failed to get module index",
+			zap.String("module", modPath), zap.String("version", version), zap.Error(err))
+		return err
+	}
+
+	// Module versions are immutable, but latest version changes over time.
+	if version != "" {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+	return nil
+}
+
+// HandleGetModuleVersions handles requests to get a list of module versions.
+func (h *APIv2Handler) HandleGetModuleVersions(w http.ResponseWriter, r *http.Request) error {
+	if h.cfg.ModuleVersions == nil {
+		return Errorf(http.StatusNotFound, "module versions lookup is disabled")
+	}
+
+	modPath := mux.Vars(r)["path"]
+	rsp, err := h.cfg.ModuleVersions.GetVersions(r.Context(), modPath)
+	if err != nil {
+		return h.moduleVersionsError(modPath, err)
+	}
+
+	h.writeModuleVersionsCacheHeader(w)
+	WriteJSON(w, rsp)
+	return nil
+}
+
+// HandleGetLatestModuleVersion handles requests to get latest module version.
+func (h *APIv2Handler) HandleGetLatestModuleVersion(w http.ResponseWriter, r *http.Request) error {
+	if h.cfg.ModuleVersions == nil {
+		return Errorf(http.StatusNotFound, "module versions lookup is disabled")
+	}
+
+	modPath := mux.Vars(r)["path"]
+	rsp, err := h.cfg.ModuleVersions.GetLatestVersion(r.Context(), modPath)
+	if err != nil {
+		return h.moduleVersionsError(modPath, err)
+	}
+
+	h.writeModuleVersionsCacheHeader(w)
+	WriteJSON(w, rsp)
+	return nil
+}
+
+func (h *APIv2Handler) writeModuleVersionsCacheHeader(w http.ResponseWriter) {
+	maxAge := int(h.cfg.ModuleVersions.CacheTTL().Seconds())
+	w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(maxAge))
+}
+
+func (h *APIv2Handler) moduleVersionsError(modPath string, err error) error {
+	var validationErr goproxy.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return NewBadRequestError(err)
+	case errors.Is(err, goproxy.ErrModuleNotFound):
+		return NewHTTPError(http.StatusNotFound, err)
+	case errors.Is(err, goproxy.ErrPrivateModule):
+		return NewHTTPError(http.StatusForbidden, err)
+	case errors.Is(err, context.Canceled):
+		return err
+	}
+
+	h.logger.Error("failed to get module versions", zap.String("module", modPath), zap.Error(err))
+	return err
+}
+
 func (h *APIv2Handler) Mount(r *mux.Router) {
 	r.Path("/run").Methods(http.MethodPost).HandlerFunc(WrapHandler(h.HandleRun))
 	r.Path("/format").Methods(http.MethodPost).HandlerFunc(WrapHandler(h.HandleFormat))
 	r.Path("/share").Methods(http.MethodPost).HandlerFunc(WrapHandler(h.HandleShare))
 	r.Path("/share/{id}").Methods(http.MethodGet).HandlerFunc(WrapHandler(h.HandleGetSnippet))
 	r.Path("/compile").Methods(http.MethodPost).HandlerFunc(WrapHandler(h.HandleCompile))
+	r.Path("/modules/index").Methods(http.MethodGet).HandlerFunc(WrapHandler(h.HandleGetModuleIndex))
+	r.Path("/modules/{path:.+}/versions").Methods(http.MethodGet).HandlerFunc(WrapHandler(h.HandleGetModuleVersions))
+	r.Path("/modules/{path:.+}/latest").Methods(http.MethodGet).HandlerFunc(WrapHandler(h.HandleGetLatestModuleVersion))
 }
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/go-playground/internal/builder"
+	"github.com/x1unix/go-playground/internal/formatter"
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+func TestAPIv2Handler_HandleFormat(t *testing.T) {
+	const (
+		src      = "package main\nfunc main(){}"
+		localRsp = "{\"files\":{\"main.go\":\"package main\\n\\nfunc main() {}\\n\"}}"
+	)
+
+	cases := map[string]struct {
+		query            string
+		src              string
+		noFormatter      bool
+		defaultFormatter string
+		upstreamRsp      string
+		wantStatus       int
+		wantBody         string
+		wantUpstream     bool
+	}{
+		"use upstream by default": {
+			wantStatus:   http.StatusOK,
+			wantBody:     "{\"files\":{\"main.go\":\"upstream\"}}",
+			wantUpstream: true,
+		},
+		"use local formatter by default": {
+			defaultFormatter: formatter.ProviderLocal,
+			wantStatus:       http.StatusOK,
+			wantBody:         localRsp,
+		},
+		"select local formatter per request": {
+			query:      "?formatter=local",
+			wantStatus: http.StatusOK,
+			wantBody:   localRsp,
+		},
+		"select upstream formatter per request": {
+			query:            "?formatter=upstream",
+			defaultFormatter: formatter.ProviderLocal,
+			wantStatus:       http.StatusOK,
+			wantBody:         "{\"files\":{\"main.go\":\"upstream\"}}",
+			wantUpstream:     true,
+		},
+		"fallback to upstream if local formatter is not available": {
+			query:        "?formatter=local",
+			noFormatter:  true,
+			wantStatus:   http.StatusOK,
+			wantBody:     "{\"files\":{\"main.go\":\"upstream\"}}",
+			wantUpstream: true,
+		},
+		"format styles other than goimports locally": {
+			query:       "?style=gofumpt",
+			noFormatter: true,
+			wantStatus:  http.StatusOK,
+			wantBody:    localRsp,
+		},
+		"format goimports style with local prefix locally": {
+			query:      "?local=example.com",
+			wantStatus: http.StatusOK,
+			wantBody:   localRsp,
+		},
+		"fallback to upstream for local prefix if local formatter is not available": {
+			query:        "?local=example.com",
+			noFormatter:  true,
+			wantStatus:   http.StatusOK,
+			wantBody:     "{\"files\":{\"main.go\":\"upstream\"}}",
+			wantUpstream: true,
+		},
+		"invalid style": {
+			query:      "?style=foo",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "{\"error\":\"invalid formatting style \\\"foo\\\"\"}\n",
+		},
+		"report syntax error": {
+			query:      "?formatter=local",
+			src:        "package main\nfunc main() {",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "{\"error\":\"main.go:2:14: expected '}', found 'EOF'\"}\n",
+		},
+		"return text edits": {
+			query:      "?formatter=local&result=edits",
+			wantStatus: http.StatusOK,
+			wantBody: "{\"edits\":{\"main.go\":[{\"range\":{\"start\":{\"line\":1,\"character\":0}," +
+				"\"end\":{\"line\":1,\"character\":13}},\"newText\":\"\\nfunc main() {}\\n\"}]}}",
+		},
+		"return files and text edits": {
+			query:      "?formatter=local&result=all",
+			src:        "package main\n\nfunc main() {}\n",
+			wantStatus: http.StatusOK,
+			wantBody:   "{\"files\":{\"main.go\":\"package main\\n\\nfunc main() {}\\n\"},\"edits\":{\"main.go\":[]}}",
+		},
+		"return text edits for upstream formatter": {
+			query:        "?result=edits",
+			src:          "package main",
+			wantStatus:   http.StatusOK,
+			wantBody:     "{\"edits\":{\"main.go\":[{\"range\":{\"start\":{\"line\":0,\"character\":0},\"end\":{\"line\":0,\"character\":12}},\"newText\":\"upstream\"}]}}",
+			wantUpstream: true,
+		},
+		"fallback to local formatter if upstream response is malformed": {
+			upstreamRsp:  "-- ../main.go --\npackage main",
+			wantStatus:   http.StatusOK,
+			wantBody:     localRsp,
+			wantUpstream: true,
+		},
+		"invalid result type": {
+			query:      "?result=foo",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "{\"error\":\"invalid format result type \\\"foo\\\"\"}\n",
+		},
+		"invalid formatter": {
+			query:      "?formatter=foo",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "{\"error\":\"invalid formatter \\\"foo\\\"\"}\n",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			var upstreamCalled bool
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				upstreamCalled = true
+				rsp := c.upstreamRsp
+				if rsp == "" {
+					rsp = "upstream"
+				}
+
+				WriteJSON(w, goplay.FmtResponse{Body: rsp})
+			}))
+			t.Cleanup(upstream.Close)
+
+			cfg := APIv2HandlerConfig{
+				Client:           goplay.NewClient(upstream.URL, "test", time.Second),
+				DefaultFormatter: c.defaultFormatter,
+			}
+			if !c.noFormatter {
+				cfg.Formatter = formatter.NewFormatter(nil)
+			}
+
+			input := c.src
+			if input == "" {
+				input = src
+			}
+
+			body, err := json.Marshal(FilesPayload{
+				Files: map[string]string{"main.go": input},
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/format"+c.query, bytes.NewReader(body))
+			rec := httptest.NewRecorder()
+			WrapHandler(NewAPIv2Handler(cfg).HandleFormat)(rec, req)
+
+			require.Equal(t, c.wantStatus, rec.Code)
+			require.Equal(t, c.wantBody, rec.Body.String())
+			require.Equal(t, c.wantUpstream, upstreamCalled)
+		})
+	}
+}
+
+func TestAPIv2Handler_HandleRun_CompileErrors(t *testing.T) {
+	const mainSrc = "package main\n\nfunc main() {\n\tfoo()\n}\n"
+
+	cases := map[string]struct {
+		files           map[string]string
+		errors          string
+		wantDiagnostics []builder.Diagnostic
+	}{
+		"single file snippet": {
+			files:  map[string]string{"main.go": mainSrc},
+			errors: "# command-line-arguments\n./prog.go:4:2: undefined: foo\n",
+			wantDiagnostics: []builder.Diagnostic{
+				{File: "main.go", Line: 4, Column: 2, Severity: builder.SeverityError, Message: "undefined: foo"},
+			},
+		},
+		"multiple files snippet": {
+			files: map[string]string{
+				"main.go": mainSrc,
+				"util.go": "package main\n\nfunc bar() int {}\n",
+			},
+			errors: "./util.go:3:17: missing return\n" +
+				"./prog.go:5:2: undefined: foo\n" +
+				"./prog.go:7:1: separator line\n" +
+				"/usr/local/go/src/fmt/print.go:1:1: outside of workspace\n",
+			wantDiagnostics: []builder.Diagnostic{
+				{File: "util.go", Line: 3, Column: 17, Severity: builder.SeverityError, Message: "missing return"},
+				{File: "main.go", Line: 4, Column: 2, Severity: builder.SeverityError, Message: "undefined: foo"},
+			},
+		},
+		"multiple files with file names": {
+			files: map[string]string{
+				"main.go": mainSrc,
+				"util.go": "package main\n\nfunc bar() int {}\n",
+			},
+			errors: "# command-line-arguments\n" +
+				"./main.go:4:2: undefined: foo\n" +
+				"./util.go:3:17: missing return\n",
+			wantDiagnostics: []builder.Diagnostic{
+				{File: "main.go", Line: 4, Column: 2, Severity: builder.SeverityError, Message: "undefined: foo"},
+				{File: "util.go", Line: 3, Column: 17, Severity: builder.SeverityError, Message: "missing return"},
+			},
+		},
+		"errors without position": {
+			files:  map[string]string{"main.go": mainSrc},
+			errors: "timeout running program",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				WriteJSON(w, goplay.CompileResponse{Errors: c.errors})
+			}))
+			t.Cleanup(upstream.Close)
+
+			cfg := APIv2HandlerConfig{
+				Client: goplay.NewClient(upstream.URL, "test", time.Second),
+			}
+
+			body, err := json.Marshal(FilesPayload{Files: c.files})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/run", bytes.NewReader(body))
+			rec := httptest.NewRecorder()
+			WrapHandler(NewAPIv2Handler(cfg).HandleRun)(rec, req)
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+
+			var rsp ErrorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+			require.Equal(t, c.errors, rsp.Error)
+			if len(c.wantDiagnostics) == 0 {
+				require.Empty(t, rsp.Diagnostics)
+				return
+			}
+
+			require.Equal(t, c.wantDiagnostics, rsp.Diagnostics)
+		})
+	}
+}
+
+func TestNewQueueFullError(t *testing.T) {
+	queueErr := builder.QueueFullError{RetryAfter: 1500 * time.Millisecond}
+	rec := httptest.NewRecorder()
+	handleError(newQueueFullError(queueErr, queueErr), rec)
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "2", rec.Header().Get("Retry-After"))
+	require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
@@ -0,0 +1,14 @@
+package server
+
+import (
+	"os"
+	"testing"
+
+	"github.com/x1unix/go-playground/internal/sandbox"
+)
+
+func TestMain(m *testing.M) {
+	// Test binary is used as a sandbox helper process by local run backend tests.
+	sandbox.Init()
+	os.Exit(m.Run())
+}
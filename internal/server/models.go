@@ -3,7 +3,11 @@ package server
 import (
 	"net/http"
 
+	"typefox.dev/lsp"
+
 	"github.com/x1unix/go-playground/internal/announcements"
+	"github.com/x1unix/go-playground/internal/builder"
+	"github.com/x1unix/go-playground/internal/formatter"
 	"github.com/x1unix/go-playground/pkg/goplay"
 )
 
@@ -76,9 +80,15 @@ type BuildResponseV2 struct {
 	// FileName is file name
 	FileName string `json:"fileName,omitempty"`
 
+	// Target is WebAssembly build target OS.
+	Target string `json:"target,omitempty"`
+
 	// CompilerOutput contains stderr emitted by the compiler.
 	CompilerOutput string `json:"compilerOutput,omitempty"`
 
+	// Diagnostics is a list of compiler annotations parsed from compiler output.
+	Diagnostics []builder.Diagnostic `json:"diagnostics,omitempty"`
+
 	// IsUnitTest indicates whether payload is a Go test.
 	IsTest bool `json:"isTest,omitempty"`
 
@@ -89,6 +99,48 @@ type BuildResponseV2 struct {
 	HasFuzz bool `json:"hasFuzz,omitempty"`
 }
 
+// Format result types.
+const (
+	// FormatResultFiles returns formatted files contents.
+	FormatResultFiles = "files"
+
+	// FormatResultEdits returns list of text edits to apply to each file.
+	FormatResultEdits = "edits"
+
+	// FormatResultAll returns both formatted files and text edits.
+	FormatResultAll = "all"
+)
+
+// FormatResponse is code format response.
+type FormatResponse struct {
+	// Files contains formatted files contents.
+	Files map[string]string `json:"files,omitempty"`
+
+	// Edits contains list of LSP text edits per file.
+	//
+	// Empty list means that file is already formatted.
+	Edits map[string][]lsp.TextEdit `json:"edits,omitempty"`
+}
+
+// NewFormatResponse constructs a format response of a given result type from original and formatted files.
+func NewFormatResponse(orig, formatted map[string]string, resultType string) FormatResponse {
+	var rsp FormatResponse
+	if resultType != FormatResultEdits {
+		rsp.Files = formatted
+	}
+
+	if resultType == FormatResultFiles {
+		return rsp
+	}
+
+	rsp.Edits = make(map[string][]lsp.TextEdit, len(formatted))
+	for name, contents := range formatted {
+		rsp.Edits[name] = formatter.TextEdits(orig[name], contents)
+	}
+
+	return rsp
+}
+
 // RunResponse is code run response
 type RunResponse struct {
 	// Formatted contains goimport'ed code.
@@ -98,6 +150,9 @@ type RunResponse struct {
 
 	// Events is list of code execution outputs
 	Events []*goplay.CompileEvent `json:"events,omitempty"`
+
+	// Status is program exit code.
+	Status int `json:"status,omitempty"`
 }
 
 // PlaygroundVersions contains information about playground Go versions.
@@ -133,8 +188,13 @@ type VersionsInformation struct {
 	// Playground contains information about Go versions on Go Playground server.
 	Playground *PlaygroundVersions `json:"playground"`
 
-	// WebAssembly is host Go version used for building WebAssembly Go files.
+	// WebAssembly is default Go version used for building WebAssembly Go files.
 	WebAssembly string `json:"wasm"`
+
+	// WebAssemblyVersions is a list of Go versions available to build WebAssembly Go files.
+	//
+	// The first version is default.
+	WebAssemblyVersions []string `json:"wasmVersions"`
 }
 
 type GetAnnouncementResponse struct {
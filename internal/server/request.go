@@ -7,6 +7,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/x1unix/go-playground/internal/formatter"
+	"github.com/x1unix/go-playground/internal/sandbox"
+	"github.com/x1unix/go-playground/internal/wasmrun"
 	"github.com/x1unix/go-playground/pkg/goplay"
 )
 
@@ -22,6 +25,19 @@ type RunParams struct {
 
 	// Backend is Go run backend.
 	Backend string
+
+	// ClientID identifies requester for fair build queue scheduling.
+	ClientID string
+}
+
+// IsLocal returns whether program should be executed on a server instead of Go Playground.
+func (p RunParams) IsLocal() bool {
+	return isServerBackend(p.Backend)
+}
+
+// isServerBackend returns whether run backend executes programs on a server.
+func isServerBackend(backend string) bool {
+	return backend == sandbox.BackendName || backend == wasmrun.BackendName
 }
 
 func RunParamsFromQuery(query url.Values) (params RunParams, err error) {
@@ -39,6 +55,11 @@ func RunParamsFromQuery(query url.Values) (params RunParams, err error) {
 		return params, err
 	}
 
+	if backend := query.Get("backend"); isServerBackend(backend) {
+		params.Backend = backend
+		return params, nil
+	}
+
 	params.Backend, err = backendFromQuery(query)
 	return params, err
 }
@@ -60,6 +81,58 @@ func parseBoolQueryParam(query url.Values, key string, defaults bool) (bool, err
 	return boolVal, nil
 }
 
+// formatterFromQuery returns formatter provider from query params.
+//
+// Default provider is returned if parameter is empty.
+func formatterFromQuery(query url.Values, defaultProvider string) (string, error) {
+	provider := query.Get("formatter")
+	if provider == "" {
+		return defaultProvider, nil
+	}
+
+	if !formatter.ValidateProvider(provider) {
+		return "", fmt.Errorf("invalid formatter %q", provider)
+	}
+
+	return provider, nil
+}
+
+// formatOptionsFromQuery returns code formatting options from query params.
+//
+// goimports style is used by default.
+func formatOptionsFromQuery(query url.Values, defaultLocalPrefix string) (formatter.Options, error) {
+	opts := formatter.Options{
+		Style:       query.Get("style"),
+		LocalPrefix: query.Get("local"),
+	}
+
+	if opts.Style == "" {
+		opts.Style = formatter.StyleGoimports
+	} else if !formatter.ValidateStyle(opts.Style) {
+		return opts, fmt.Errorf("invalid formatting style %q", opts.Style)
+	}
+
+	if opts.LocalPrefix == "" {
+		opts.LocalPrefix = defaultLocalPrefix
+	}
+
+	return opts, nil
+}
+
+// formatResultFromQuery returns format result type from query params.
+//
+// Formatted files are returned by default.
+func formatResultFromQuery(query url.Values) (string, error) {
+	switch result := query.Get("result"); result {
+	case "":
+		return FormatResultFiles, nil
+	case FormatResultFiles, FormatResultEdits, FormatResultAll:
+		return result, nil
+	default:
+		return "", fmt.Errorf("invalid format result type %q", result)
+	}
+}
+
 func isContentLengthError(err error) bool {
 	if httpErr, ok := goplay.IsHTTPError(err); ok {
 		if httpErr.StatusCode == http.StatusRequestEntityTooLarge {
@@ -9,6 +9,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/x1unix/go-playground/internal/builder"
 	"github.com/x1unix/go-playground/pkg/goplay"
 )
 
@@ -17,6 +18,23 @@ const maxFilesCount = 10
 type FilesPayload struct {
 	Files           map[string]string `json:"files"`
 	CompilerOptions string            `json:"compilerOptions,omitempty"`
+
+	// Backend is run backend name saved with a shared snippet.
+	Backend string `json:"backend,omitempty"`
+
+	// Vet indicates whether go vet is enabled for a shared snippet.
+	Vet bool `json:"vet,omitempty"`
+
+	// GoVersion is Go toolchain version used to build WebAssembly program.
+	//
+	// Default toolchain is used if empty.
+	GoVersion string `json:"goVersion,omitempty"`
+
+	// Target is WebAssembly build target OS ("js" or "wasip1").
+	//
+	// Programs are built for "js" target if empty.
+	// Programs run by "wasm-server" backend are built for "wasip1" target if empty.
+	Target string `json:"target,omitempty"`
 }
 
 // Validate checks file name and contents and returns error on validation failure.
@@ -52,6 +70,16 @@ func (p FilesPayload) Validate() error {
 	return nil
 }
 
+// ByteFiles returns file contents as byte slices.
+func (p FilesPayload) ByteFiles() map[string][]byte {
+	files := make(map[string][]byte, len(p.Files))
+	for name, contents := range p.Files {
+		files[name] = []byte(contents)
+	}
+
+	return files
+}
+
 // HasUnitTests checks whether file list contains any unit test.
 //
 // Note: at the moment, func doesn't check file contents and only check file names.
@@ -71,6 +99,9 @@ type ErrorResponse struct {
 
 	// Error is error message
 	Error string `json:"error"`
+
+	// Diagnostics is a list of diagnostics parsed from build output.
+	Diagnostics []builder.Diagnostic `json:"diagnostics,omitempty"`
 }
 
 // NewErrorResponse is ErrorResponse constructor
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const eventStreamContentType = "text/event-stream"
+
+const (
+	// StreamEventOutput is event that contains program output chunk as goplay.CompileEvent.
+	StreamEventOutput = "output"
+
+	// StreamEventError is event that contains an error that occurred after stream started.
+	StreamEventError = "error"
+
+	// StreamEventQueued is event that contains program build position in a queue as QueueEvent.
+	StreamEventQueued = "queued"
+
+	// StreamEventDone is event that is sent when program finished as DoneEvent.
+	StreamEventDone = "done"
+)
+
+// errStreamClosed is returned when event is sent after stream was closed.
+var errStreamClosed = errors.New("event stream is closed")
+
+// QueueEvent is sent when program build waits for a free build worker.
+type QueueEvent struct {
+	// Position is build position in a queue.
+	Position int `json:"position"`
+}
+
+// DoneEvent is sent when program finished.
+type DoneEvent struct {
+	// Status is program exit code.
+	Status int `json:"status"`
+}
+
+// EventStream writes Server-Sent Events to an HTTP response.
+//
+// Response headers are sent with the first event which allows to
+// return a regular error response if request failed before any output.
+//
+// Events can be sent concurrently.
+type EventStream struct {
+	lock    sync.Mutex
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	started bool
+	closed  bool
+}
+
+// NewEventStream returns a new event stream writer.
+func NewEventStream(w http.ResponseWriter) *EventStream {
+	return &EventStream{
+		w:  w,
+		rc: http.NewResponseController(w),
+	}
+}
+
+// Started returns whether response headers were already sent.
+func (s *EventStream) Started() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.started
+}
+
+// Close discards all subsequent events.
+//
+// Stream should be closed before HTTP handler returns.
+func (s *EventStream) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+}
+
+// Send encodes value as JSON and sends it as a named event.
+func (s *EventStream) Send(event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %q event: %w", event, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return errStreamClosed
+	}
+
+	if !s.started {
+		s.started = true
+		h := s.w.Header()
+		h.Set("Content-Type", eventStreamContentType)
+		h.Set("Cache-Control", "no-cache")
+		// Disable response buffering in Nginx.
+		h.Set("X-Accel-Buffering", "no")
+		s.w.WriteHeader(http.StatusOK)
+	}
+
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+
+	return s.rc.Flush()
+}
+
+// SendError sends an error event.
+func (s *EventStream) SendError(err error) error {
+	httpErr := new(HTTPError)
+	if errors.As(err, &httpErr) {
+		err = httpErr.parent
+	}
+
+	return s.Send(StreamEventError, ErrorResponse{
+		Error:       err.Error(),
+		Diagnostics: errorDiagnostics(err),
+	})
+}
+
+// acceptsEventStream checks whether client requested response as Server-Sent Events stream.
+func acceptsEventStream(r *http.Request) bool {
+	for _, v := range r.Header.Values("Accept") {
+		if strings.Contains(v, eventStreamContentType) {
+			return true
+		}
+	}
+
+	return false
+}
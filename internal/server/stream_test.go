@@ -0,0 +1,205 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/x1unix/go-playground/internal/sandbox"
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+func TestAcceptsEventStream(t *testing.T) {
+	cases := map[string]struct {
+		accept []string
+		expect bool
+	}{
+		"no header": {},
+		"json": {
+			accept: []string{"application/json"},
+		},
+		"event stream": {
+			accept: []string{"text/event-stream"},
+			expect: true,
+		},
+		"multiple types": {
+			accept: []string{"application/json, text/event-stream;q=0.9"},
+			expect: true,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/v2/run", nil)
+			for _, v := range c.accept {
+				r.Header.Add("Accept", v)
+			}
+
+			require.Equal(t, c.expect, acceptsEventStream(r))
+		})
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	rec := httptest.NewRecorder()
+	stream := NewEventStream(rec)
+	require.False(t, stream.Started())
+
+	require.NoError(t, stream.Send(StreamEventQueued, QueueEvent{Position: 2}))
+	require.NoError(t, stream.Send(StreamEventOutput, goplay.CompileEvent{Message: "foo", Kind: "stdout"}))
+	require.NoError(t, stream.SendError(NewBadRequestError(errors.New("bar"))))
+	require.True(t, stream.Started())
+	require.True(t, rec.Flushed)
+
+	stream.Close()
+	require.ErrorIs(t, stream.Send(StreamEventDone, DoneEvent{}), errStreamClosed)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, eventStreamContentType, rec.Header().Get("Content-Type"))
+	require.Equal(t,
+		"event: queued\ndata: {\"position\":2}\n\n"+
+			"event: output\ndata: {\"Message\":\"foo\",\"Kind\":\"stdout\",\"Delay\":0}\n\n"+
+			"event: error\ndata: {\"error\":\"bar\"}\n\n",
+		rec.Body.String(),
+	)
+}
+
+func TestAPIv2Handler_HandleRun_Stream(t *testing.T) {
+	cases := map[string]struct {
+		upstream   goplay.CompileResponse
+		wantStatus int
+		wantBody   string
+	}{
+		"stream events": {
+			upstream: goplay.CompileResponse{
+				Events: []*goplay.CompileEvent{
+					{Message: "foo", Kind: "stdout"},
+					{Message: "bar", Kind: "stderr", Delay: time.Second},
+				},
+			},
+			wantStatus: http.StatusOK,
+			wantBody: "event: output\ndata: {\"Message\":\"foo\",\"Kind\":\"stdout\",\"Delay\":0}\n\n" +
+				"event: output\ndata: {\"Message\":\"bar\",\"Kind\":\"stderr\",\"Delay\":1000000000}\n\n" +
+				"event: done\ndata: {\"status\":0}\n\n",
+		},
+		"report exit status": {
+			upstream: goplay.CompileResponse{
+				Events: []*goplay.CompileEvent{
+					{Message: "panic: foo\n\nexit status 2\n", Kind: "stderr"},
+				},
+				Status: 2,
+			},
+			wantStatus: http.StatusOK,
+			wantBody: "event: output\ndata: {\"Message\":\"panic: foo\\n\\nexit status 2\\n\",\"Kind\":\"stderr\",\"Delay\":0}\n\n" +
+				"event: done\ndata: {\"status\":2}\n\n",
+		},
+		"return build error as response": {
+			upstream: goplay.CompileResponse{
+				Errors: "undefined: foo",
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "{\"error\":\"undefined: foo\"}\n",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				WriteJSON(w, c.upstream)
+			}))
+			t.Cleanup(upstream.Close)
+
+			h := NewAPIv2Handler(APIv2HandlerConfig{
+				Client: goplay.NewClient(upstream.URL, "test", time.Second),
+			})
+
+			body, err := json.Marshal(FilesPayload{
+				Files: map[string]string{"main.go": "package main"},
+			})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/run", bytes.NewReader(body))
+			req.Header.Set("Accept", eventStreamContentType)
+			rec := httptest.NewRecorder()
+			WrapHandler(h.HandleRun)(rec, req)
+
+			require.Equal(t, c.wantStatus, rec.Code)
+			require.Equal(t, c.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestAPIv2Handler_HandleRun_StreamLocal(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping sandbox test in short mode")
+	}
+
+	svc, err := sandbox.NewService(zaptest.NewLogger(t), sandbox.Config{
+		WorkDir:    t.TempDir(),
+		RunTimeout: 10 * time.Second,
+		BuildEnvironment: map[string]string{
+			"PATH":        os.Getenv("PATH"),
+			"GOCACHE":     os.Getenv("GOCACHE"),
+			"GOMODCACHE":  os.Getenv("GOMODCACHE"),
+			"GOFLAGS":     "-mod=mod",
+			"GOTOOLCHAIN": "local",
+		},
+	})
+	if errors.Is(err, sandbox.ErrUnsupportedPlatform) || errors.Is(err, sandbox.ErrPrivilegesRequired) {
+		t.Skip(err)
+	}
+	require.NoError(t, err)
+
+	const programDelay = 2 * time.Second
+	srv := httptest.NewServer(WrapHandler(NewAPIv2Handler(APIv2HandlerConfig{
+		Sandbox: svc,
+	}).HandleRun))
+	t.Cleanup(srv.Close)
+
+	body, err := json.Marshal(FilesPayload{
+		Files: map[string]string{
+			"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"time\"\n)\n\n" +
+				"func main() {\n\tfmt.Println(\"first\")\n\ttime.Sleep(2 * time.Second)\n\tfmt.Println(\"second\")\n}\n",
+		},
+	})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/v2/run?backend="+sandbox.BackendName, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Accept", eventStreamContentType)
+
+	rsp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	// Remember when each event is received to check that output isn't delayed until program exit.
+	var (
+		events     []string
+		receivedAt []time.Time
+	)
+	scanner := bufio.NewScanner(rsp.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			events = append(events, data)
+			receivedAt = append(receivedAt, time.Now())
+		}
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, events, 3)
+	require.Contains(t, events[0], `"Message":"first\n"`)
+	require.Contains(t, events[1], `"Message":"second\n"`)
+	require.Equal(t, `{"status":0}`, events[2])
+	require.GreaterOrEqual(t, receivedAt[2].Sub(receivedAt[0]), programDelay/2, "output wasn't streamed before program exit")
+}
@@ -3,18 +3,18 @@ package server
 import (
 	"encoding/json"
 	"errors"
+	"maps"
+	"net"
 	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
 
 	"github.com/x1unix/go-playground/pkg/goplay"
 )
 
-// evalPayloadFromRequest validates and extracts snippet payload for Go Playground API evaluate request.
-func evalPayloadFromRequest(r *http.Request) ([]byte, error) {
-	body, err := filesPayloadFromRequest(r)
-	if err != nil {
-		return nil, err
-	}
-
+// evalPayloadFromFiles validates and builds snippet payload for Go Playground API evaluate request.
+func evalPayloadFromFiles(body *FilesPayload) ([]byte, error) {
 	switch len(body.Files) {
 	case 0:
 		return nil, errNoGoFiles
@@ -37,9 +37,10 @@ func evalPayloadFromRequest(r *http.Request) ([]byte, error) {
 		)
 	}
 
+	// Sort files to get the same payload for the same set of files.
 	fileSet := goplay.NewFileSet(goplay.MaxSnippetSize)
-	for name, contents := range body.Files {
-		if err := fileSet.Add(name, []byte(contents)); err != nil {
+	for _, name := range slices.Sorted(maps.Keys(body.Files)) {
+		if err := fileSet.Add(name, []byte(body.Files[name])); err != nil {
 			return nil, NewBadRequestError(err)
 		}
 	}
@@ -51,40 +52,20 @@ var errNoGoFiles = NewBadRequestError(
 	errors.New("no Go files"),
 )
 
-func fileSetFromRequest(r *http.Request) (*goplay.FileSet, []string, error) {
-	body, err := filesPayloadFromRequest(r)
-	if err != nil {
-		return nil, nil, err
-	}
-
+func fileSetFromPayload(body *FilesPayload) (*goplay.FileSet, error) {
 	payload := goplay.NewFileSet(goplay.MaxSnippetSize)
-	fileNames := make([]string, 0, len(body.Files))
-	for name, contents := range body.Files {
-		fileNames = append(fileNames, name)
-		if err := payload.Add(name, []byte(contents)); err != nil {
-			return payload, fileNames, NewBadRequestError(err)
+	// Sort files to get the same payload for the same set of files.
+	for _, name := range slices.Sorted(maps.Keys(body.Files)) {
+		if err := payload.Add(name, []byte(body.Files[name])); err != nil {
+			return nil, NewBadRequestError(err)
 		}
 	}
 
 	if !payload.HasGoFiles() {
-		return nil, nil, errNoGoFiles
-	}
-
-	return payload, fileNames, nil
-}
-
-func buildFilesFromRequest(r *http.Request) (map[string][]byte, string, error) {
-	body, err := filesPayloadFromRequest(r)
-	if err != nil {
-		return nil, "", err
-	}
-
-	files := make(map[string][]byte, len(body.Files))
-	for name, contents := range body.Files {
-		files[name] = []byte(contents)
+		return nil, errNoGoFiles
 	}
 
-	return files, body.CompilerOptions, nil
+	return payload, nil
 }
 
 func filesPayloadFromRequest(r *http.Request) (*FilesPayload, error) {
@@ -111,3 +92,65 @@ func filesPayloadFromRequest(r *http.Request) (*FilesPayload, error) {
 
 	return body, nil
 }
+
+// clientIDFromRequest returns client IP address used to identify request origin.
+//
+// Proxy headers are used only if request came from one of trusted proxies,
+// as otherwise any client can spoof them. Remote address is used if proxy list is empty.
+func clientIDFromRequest(r *http.Request, trustedProxies []netip.Prefix) string {
+	addr, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return r.RemoteAddr
+		}
+
+		return host
+	}
+
+	clientIP := addr.Addr().Unmap()
+	if !isTrustedProxy(clientIP, trustedProxies) {
+		return clientIP.String()
+	}
+
+	forwardedFor := r.Header.Values("X-Forwarded-For")
+	if len(forwardedFor) == 0 {
+		if ip, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+			return ip.Unmap().String()
+		}
+
+		return clientIP.String()
+	}
+
+	// Each proxy appends address of its peer, so the list is traversed from the end
+	// until the first untrusted address is found.
+	forwardedFor = strings.Split(strings.Join(forwardedFor, ","), ",")
+	for i := len(forwardedFor) - 1; i >= 0; i-- {
+		v := strings.TrimSpace(forwardedFor[i])
+		if v == "" {
+			continue
+		}
+
+		ip, err := netip.ParseAddr(v)
+		if err != nil {
+			break
+		}
+
+		clientIP = ip.Unmap()
+		if !isTrustedProxy(clientIP, trustedProxies) {
+			return clientIP.String()
+		}
+	}
+
+	return clientIP.String()
+}
+
+func isTrustedProxy(ip netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
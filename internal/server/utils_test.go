@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIDFromRequest(t *testing.T) {
+	trustedProxies := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/24"),
+		netip.MustParsePrefix("fd00::/8"),
+	}
+
+	cases := map[string]struct {
+		remoteAddr     string
+		trustedProxies []netip.Prefix
+		headers        map[string]string
+		expect         string
+	}{
+		"remote address": {
+			remoteAddr: "10.0.0.1:4321",
+			expect:     "10.0.0.1",
+		},
+		"ignore headers without trusted proxies": {
+			remoteAddr: "10.0.0.1:4321",
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1",
+				"X-Real-IP":       "192.168.1.2",
+			},
+			expect: "10.0.0.1",
+		},
+		"ignore headers from untrusted proxy": {
+			remoteAddr:     "172.16.0.1:4321",
+			trustedProxies: trustedProxies,
+			headers: map[string]string{
+				"X-Forwarded-For": "192.168.1.1",
+				"X-Real-IP":       "192.168.1.2",
+			},
+			expect: "172.16.0.1",
+		},
+		"forwarded for": {
+			remoteAddr:     "10.0.0.1:4321",
+			trustedProxies: trustedProxies,
+			headers:        map[string]string{"X-Forwarded-For": "192.168.1.1, 10.0.0.2"},
+			expect:         "192.168.1.1",
+		},
+		"skip spoofed forwarded for": {
+			remoteAddr:     "10.0.0.1:4321",
+			trustedProxies: trustedProxies,
+			headers:        map[string]string{"X-Forwarded-For": "1.1.1.1, 192.168.1.1"},
+			expect:         "192.168.1.1",
+		},
+		"stop at malformed forwarded for": {
+			remoteAddr:     "10.0.0.1:4321",
+			trustedProxies: trustedProxies,
+			headers:        map[string]string{"X-Forwarded-For": "192.168.1.1, garbage, 10.0.0.2"},
+			expect:         "10.0.0.2",
+		},
+		"real ip": {
+			remoteAddr:     "10.0.0.1:4321",
+			trustedProxies: trustedProxies,
+			headers:        map[string]string{"X-Real-IP": "192.168.1.2"},
+			expect:         "192.168.1.2",
+		},
+		"ipv6 proxy": {
+			remoteAddr:     "[fd00::1]:4321",
+			trustedProxies: trustedProxies,
+			headers:        map[string]string{"X-Forwarded-For": "2001:db8::1"},
+			expect:         "2001:db8::1",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/v2/compile", nil)
+			r.RemoteAddr = c.remoteAddr
+			for k, v := range c.headers {
+				r.Header.Set(k, v)
+			}
+
+			require.Equal(t, c.expect, clientIDFromRequest(r, c.trustedProxies))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package snippets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// idLength is snippet ID length.
+	//
+	// Matches length of Go Playground snippet IDs.
+	idLength = 11
+
+	dirPerm  = 0755
+	filePerm = 0644
+)
+
+var _ Store = (*LocalStore)(nil)
+
+// LocalStore is snippet store which keeps snippets as files in a directory.
+//
+// Snippet ID is derived from snippet contents and metadata,
+// so sharing the same snippet twice returns the same ID.
+type LocalStore struct {
+	dir string
+}
+
+// NewLocalStore returns a new local snippet store which keeps snippets in a passed directory.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create snippets directory: %w", err)
+	}
+
+	return &LocalStore{dir: dir}, nil
+}
+
+// GetSnippet implements Store.
+func (s *LocalStore) GetSnippet(_ context.Context, id string) (*Snippet, error) {
+	if !isValidID(id) {
+		return nil, ErrNotFound
+	}
+
+	data, err := os.ReadFile(s.snippetPath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+
+		return nil, fmt.Errorf("failed to read snippet: %w", err)
+	}
+
+	snippet := new(Snippet)
+	if err := json.Unmarshal(data, snippet); err != nil {
+		return nil, fmt.Errorf("malformed snippet file %q: %w", id, err)
+	}
+
+	return snippet, nil
+}
+
+// SaveSnippet implements Store.
+func (s *LocalStore) SaveSnippet(_ context.Context, snippet *Snippet) (string, error) {
+	id := snippetID(snippet)
+	dst := s.snippetPath(id)
+	if _, err := os.Stat(dst); err == nil {
+		// Same snippet is already saved.
+		return id, nil
+	}
+
+	entry := *snippet
+	entry.CreatedAt = time.Now().UTC()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), dirPerm); err != nil {
+		return "", fmt.Errorf("failed to create snippet directory: %w", err)
+	}
+
+	// Write to a temporary file first to avoid partially written snippets.
+	f, err := os.CreateTemp(filepath.Dir(dst), id+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snippet file: %w", err)
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), filePerm)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), dst)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write snippet file: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *LocalStore) snippetPath(id string) string {
+	// Group snippets into subdirectories to avoid huge directories.
+	return filepath.Join(s.dir, id[:2], id+".json")
+}
+
+func snippetID(snippet *Snippet) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%q\n%q\n%t\n", snippet.CompilerOptions, snippet.Backend, snippet.Vet)
+	h.Write(snippet.Contents)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))[:idLength]
+}
+
+func isValidID(id string) bool {
+	if len(id) != idLength {
+		return false
+	}
+
+	for _, c := range id {
+		isValid := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '_'
+		if !isValid {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,83 @@
+package snippets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	snippet := &Snippet{
+		Metadata: Metadata{
+			CompilerOptions: "-tags=foo",
+			Backend:         "gotip",
+		},
+		Contents: []byte("-- main.go --\npackage main\n"),
+	}
+
+	id, err := store.SaveSnippet(ctx, snippet)
+	require.NoError(t, err)
+	require.Len(t, id, idLength)
+	require.FileExists(t, filepath.Join(dir, id[:2], id+".json"))
+
+	got, err := store.GetSnippet(ctx, id)
+	require.NoError(t, err)
+	require.NotZero(t, got.CreatedAt)
+	require.Equal(t, snippet.Contents, got.Contents)
+	require.Equal(t, snippet.CompilerOptions, got.CompilerOptions)
+	require.Equal(t, snippet.Backend, got.Backend)
+
+	t.Run("same snippet has same id", func(t *testing.T) {
+		sameID, err := store.SaveSnippet(ctx, snippet)
+		require.NoError(t, err)
+		require.Equal(t, id, sameID)
+	})
+
+	t.Run("metadata affects id", func(t *testing.T) {
+		otherID, err := store.SaveSnippet(ctx, &Snippet{Contents: snippet.Contents})
+		require.NoError(t, err)
+		require.NotEqual(t, id, otherID)
+	})
+}
+
+func TestLocalStore_GetSnippet(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ba"), dirPerm))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ba", "bad_snippet.json"), []byte("{"), filePerm))
+
+	cases := map[string]struct {
+		id        string
+		expectErr string
+	}{
+		"not found": {
+			id:        "aaaaaaaaaaa",
+			expectErr: ErrNotFound.Error(),
+		},
+		"invalid id": {
+			id:        "../../etc/x",
+			expectErr: ErrNotFound.Error(),
+		},
+		"malformed file": {
+			id:        "bad_snippet",
+			expectErr: "malformed snippet file",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			_, err := store.GetSnippet(context.Background(), c.id)
+			require.ErrorContains(t, err, c.expectErr)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package snippets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+var _ Store = (*PlaygroundStore)(nil)
+
+// PlaygroundStore is snippet store which proxies snippets to Go Playground.
+//
+// Go Playground keeps only source files, so snippet metadata is stored
+// as a reserved metadata file inside a snippet.
+type PlaygroundStore struct {
+	client *goplay.Client
+}
+
+// NewPlaygroundStore returns a new Go Playground snippet store.
+func NewPlaygroundStore(client *goplay.Client) *PlaygroundStore {
+	return &PlaygroundStore{client: client}
+}
+
+// GetSnippet implements Store.
+func (s *PlaygroundStore) GetSnippet(ctx context.Context, id string) (*Snippet, error) {
+	snippet, err := s.client.GetSnippet(ctx, id)
+	if err != nil {
+		if errors.Is(err, goplay.ErrSnippetNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	// Malformed metadata is ignored to keep snippet accessible.
+	contents, meta, _ := goplay.CutMetadata([]byte(snippet.Contents))
+	return &Snippet{
+		Metadata: Metadata{
+			CompilerOptions: meta.CompilerOptions,
+			Backend:         meta.Backend,
+			Vet:             meta.Vet,
+		},
+		Contents: contents,
+	}, nil
+}
+
+// SaveSnippet implements Store.
+func (s *PlaygroundStore) SaveSnippet(ctx context.Context, snippet *Snippet) (string, error) {
+	contents, err := goplay.AppendMetadata(snippet.Contents, goplay.SnippetMetadata{
+		CompilerOptions: snippet.CompilerOptions,
+		Backend:         snippet.Backend,
+		Vet:             snippet.Vet,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return s.client.Share(ctx, bytes.NewReader(contents))
+}
@@ -0,0 +1,56 @@
+package snippets
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+func TestPlaygroundStore(t *testing.T) {
+	var shared []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			shared, _ = io.ReadAll(r.Body)
+			_, _ = w.Write([]byte("abc"))
+			return
+		}
+
+		if r.URL.Query().Get("id") != "abc" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write(shared)
+	}))
+	defer srv.Close()
+
+	store := NewPlaygroundStore(goplay.NewClient(srv.URL, goplay.DefaultUserAgent, time.Second))
+	snippet := &Snippet{
+		Metadata: Metadata{
+			CompilerOptions: "-tags=foo",
+			Backend:         goplay.BackendGoTip,
+			Vet:             true,
+		},
+		Contents: []byte("-- main.go --\npackage main\n"),
+	}
+
+	ctx := context.Background()
+	id, err := store.SaveSnippet(ctx, snippet)
+	require.NoError(t, err)
+	require.Equal(t, "abc", id)
+	require.Contains(t, string(shared), "-- "+goplay.MetadataFileName+" --\n")
+
+	got, err := store.GetSnippet(ctx, id)
+	require.NoError(t, err)
+	require.Equal(t, snippet, got)
+
+	_, err = store.GetSnippet(ctx, "foo")
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,64 @@
+// Package snippets provides storage for shared snippets.
+package snippets
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// StoreTypePlayground is snippet store which proxies snippets to Go Playground.
+	StoreTypePlayground = "playground"
+
+	// StoreTypeLocal is snippet store which keeps snippets on a server.
+	StoreTypeLocal = "local"
+)
+
+// ErrNotFound is returned when snippet doesn't exist.
+var ErrNotFound = errors.New("snippet not found")
+
+// Metadata contains snippet settings which are not part of source files.
+type Metadata struct {
+	// CompilerOptions is additional Go compiler arguments.
+	CompilerOptions string `json:"compilerOptions,omitempty"`
+
+	// Backend is run backend name.
+	Backend string `json:"backend,omitempty"`
+
+	// Vet indicates whether go vet is enabled.
+	Vet bool `json:"vet,omitempty"`
+
+	// CreatedAt is snippet creation time.
+	//
+	// Zero value if store doesn't keep creation time.
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+}
+
+// Snippet is shared snippet.
+type Snippet struct {
+	Metadata
+
+	// Contents is a list of snippet files in txtar format.
+	Contents []byte `json:"contents"`
+}
+
+// Store stores shared snippets.
+type Store interface {
+	// GetSnippet returns snippet by ID.
+	// Returns ErrNotFound if snippet doesn't exist.
+	GetSnippet(ctx context.Context, id string) (*Snippet, error)
+
+	// SaveSnippet saves snippet and returns its ID.
+	SaveSnippet(ctx context.Context, snippet *Snippet) (string, error)
+}
+
+// ValidateStoreType checks whether store type is supported.
+func ValidateStoreType(storeType string) bool {
+	switch storeType {
+	case StoreTypePlayground, StoreTypeLocal:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,31 @@
+package syncx
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DoDetached executes and returns results of a function, making sure that
+// only one execution is in-flight for a given key at a time.
+//
+// Function runs in background without cancellation of a passed context,
+// so its result can be reused by other callers if current caller gives up.
+// Context error is returned if context is done before function returns.
+func DoDetached[T any](ctx context.Context, g *singleflight.Group, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	ch := g.DoChan(key, func() (any, error) {
+		return fn(context.WithoutCancel(ctx))
+	})
+
+	var zero T
+	select {
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	case result := <-ch:
+		if result.Err != nil {
+			return zero, result.Err
+		}
+
+		return result.Val.(T), nil
+	}
+}
@@ -0,0 +1,892 @@
+package wasmrun
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// gojsModuleName is a name of host module imported by js/wasm programs.
+const gojsModuleName = "gojs"
+
+const (
+	// nanHead is a high word of NaN-boxed value reference.
+	nanHead = 0x7FF80000
+
+	// argsOffset is linear memory offset where command line arguments are written.
+	argsOffset = 4096
+
+	// wasmMinDataAddr is linear memory offset where global data starts.
+	//
+	// Keep in sync with cmd/link/internal/ld/data.go:wasmMinDataAddr.
+	wasmMinDataAddr = 4096 + 8192
+
+	// deadlockExitCode is exit code used by Go runtime when all goroutines are asleep.
+	deadlockExitCode = 2
+
+	// jsValueOverhead is approximate host memory size used by a referenced value.
+	jsValueOverhead = 64
+)
+
+// Reference ids of values predefined by syscall/js package.
+const (
+	refNaN = iota
+	refZero
+	refNull
+	refTrue
+	refFalse
+	refGlobal
+	refGo
+)
+
+var (
+	errMemoryOutOfRange = errors.New("memory access out of range")
+	errHostMemoryLimit  = errors.New("out of memory: JavaScript values exceed memory limit")
+)
+
+// isJSProgram reports whether a module is built for js/wasm target.
+func isJSProgram(compiled wazero.CompiledModule) bool {
+	for _, fn := range compiled.ImportedFunctions() {
+		if moduleName, _, _ := fn.Import(); moduleName == gojsModuleName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsHost is a Go-side replacement of wasm_exec.js glue code for js/wasm programs.
+//
+// Host provides a minimal JavaScript environment which is enough for Go runtime and standard library:
+// stdout and stderr writes, timers, random data and stub fs and process objects without file system access.
+//
+// JavaScript values are stored in host memory which isn't limited by module memory limit,
+// so their approximate size is accounted separately and limited by the same amount.
+type jsHost struct {
+	stdout io.Writer
+	stderr io.Writer
+	start  time.Time
+	mod    api.Module
+
+	allocated    uint64
+	maxAllocated uint64
+
+	// values are JavaScript values referenced by a program, indexed by reference id.
+	values    []any
+	refCounts []int
+	ids       map[any]uint32
+	idPool    []uint32
+
+	goObj         *jsObject
+	timeouts      map[int32]time.Time
+	nextTimeoutID int32
+}
+
+func newJSHost(stdout, stderr io.Writer, maxMemory uint64) *jsHost {
+	h := &jsHost{
+		stdout:        stdout,
+		stderr:        stderr,
+		start:         time.Now(),
+		maxAllocated:  maxMemory,
+		timeouts:      make(map[int32]time.Time),
+		nextTimeoutID: 1,
+	}
+
+	h.goObj = newJSObject(map[string]any{
+		"_pendingEvent":    jsNull,
+		"_makeFuncWrapper": newJSFunc(h.makeFuncWrapper),
+	})
+
+	global := h.newGlobal()
+	h.values = []any{math.NaN(), float64(0), jsNull, true, false, global, h.goObj}
+	h.refCounts = make([]int, len(h.values))
+	for i := range h.refCounts {
+		h.refCounts[i] = math.MaxInt
+	}
+
+	h.ids = map[any]uint32{
+		float64(0): refZero,
+		jsNull:     refNull,
+		true:       refTrue,
+		false:      refFalse,
+		global:     refGlobal,
+		h.goObj:    refGo,
+	}
+
+	return h
+}
+
+// instantiate registers "gojs" host module in a runtime.
+func (h *jsHost) instantiate(ctx context.Context, rt wazero.Runtime) error {
+	funcs := []struct {
+		name string
+		fn   func(ctx context.Context, m memory, sp uint32)
+	}{
+		{name: "runtime.wasmExit", fn: h.wasmExit},
+		{name: "runtime.wasmWrite", fn: h.wasmWrite},
+		{name: "runtime.resetMemoryDataView", fn: func(context.Context, memory, uint32) {}},
+		{name: "runtime.nanotime1", fn: h.nanotime},
+		{name: "runtime.walltime", fn: h.walltime},
+		{name: "runtime.scheduleTimeoutEvent", fn: h.scheduleTimeoutEvent},
+		{name: "runtime.clearTimeoutEvent", fn: h.clearTimeoutEvent},
+		{name: "runtime.getRandomData", fn: h.getRandomData},
+		{name: "syscall/js.finalizeRef", fn: h.finalizeRef},
+		{name: "syscall/js.stringVal", fn: h.stringVal},
+		{name: "syscall/js.valueGet", fn: h.valueGet},
+		{name: "syscall/js.valueSet", fn: h.valueSet},
+		{name: "syscall/js.valueDelete", fn: h.valueDelete},
+		{name: "syscall/js.valueIndex", fn: h.valueIndex},
+		{name: "syscall/js.valueSetIndex", fn: h.valueSetIndex},
+		{name: "syscall/js.valueCall", fn: h.valueCall},
+		{name: "syscall/js.valueInvoke", fn: h.valueInvoke},
+		{name: "syscall/js.valueNew", fn: h.valueNew},
+		{name: "syscall/js.valueLength", fn: h.valueLength},
+		{name: "syscall/js.valuePrepareString", fn: h.valuePrepareString},
+		{name: "syscall/js.valueLoadString", fn: h.valueLoadString},
+		{name: "syscall/js.valueInstanceOf", fn: h.valueInstanceOf},
+		{name: "syscall/js.copyBytesToGo", fn: h.copyBytesToGo},
+		{name: "syscall/js.copyBytesToJS", fn: h.copyBytesToJS},
+		{name: "debug", fn: func(context.Context, memory, uint32) {}},
+	}
+
+	b := rt.NewHostModuleBuilder(gojsModuleName)
+	for _, f := range funcs {
+		fn := f.fn
+		b.NewFunctionBuilder().
+			WithGoModuleFunction(api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+				fn(ctx, memory{mod.Memory()}, api.DecodeU32(stack[0]))
+			}), []api.ValueType{api.ValueTypeI32}, nil).
+			WithParameterNames("sp").
+			Export(f.name)
+	}
+
+	_, err := b.Instantiate(ctx)
+	return err
+}
+
+// run starts a program and handles scheduled events until program exits.
+//
+// Program exit is reported as sys.ExitError like for WASI programs.
+func (h *jsHost) run(ctx context.Context, rt wazero.Runtime, compiled wazero.CompiledModule, args []string) error {
+	// Program is started by calling exported "run" function instead of a start function.
+	mod, err := rt.InstantiateModule(ctx, compiled, wazero.NewModuleConfig().WithName("").WithStartFunctions())
+	if err != nil {
+		return err
+	}
+
+	h.mod = mod
+	argc, argv, err := writeArgs(memory{mod.Memory()}, args)
+	if err != nil {
+		return err
+	}
+
+	if _, err := mod.ExportedFunction("run").Call(ctx, uint64(argc), uint64(argv)); err != nil {
+		return err
+	}
+
+	for {
+		id, deadline, ok := h.nextTimeout()
+		if !ok {
+			// Nothing can wake up a program.
+			//
+			// Unlike wasm_exec_node.js, deadlock isn't passed to Go runtime as a zero id event,
+			// because programs which don't use syscall/js don't have an event handler.
+			_, _ = io.WriteString(h.stderr, "fatal error: all goroutines are asleep - deadlock!\n")
+			_ = h.mod.CloseWithExitCode(ctx, deadlockExitCode)
+			return sys.NewExitError(deadlockExitCode)
+		}
+
+		timer := time.NewTimer(time.Until(deadline))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		// Timeout event stays scheduled until it's handled by Go runtime.
+		for ok {
+			if err := h.resume(ctx); err != nil {
+				return err
+			}
+
+			_, ok = h.timeouts[id]
+		}
+	}
+}
+
+// resume resumes program execution to handle a pending event or a timeout.
+func (h *jsHost) resume(ctx context.Context) error {
+	_, err := h.mod.ExportedFunction("resume").Call(ctx)
+	return err
+}
+
+// getsp returns current stack pointer.
+//
+// Go code may be executed in the middle of imported function call if a call triggers a Go event handler.
+// A goroutine can switch to a new stack, so imported function has to refresh stack pointer before
+// writing results.
+func (h *jsHost) getsp(ctx context.Context) uint32 {
+	res, err := h.mod.ExportedFunction("getsp").Call(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	return api.DecodeU32(res[0])
+}
+
+// nextTimeout returns the earliest scheduled timeout event.
+func (h *jsHost) nextTimeout() (id int32, deadline time.Time, ok bool) {
+	for i, t := range h.timeouts {
+		if !ok || t.Before(deadline) || (t.Equal(deadline) && i < id) {
+			id, deadline, ok = i, t, true
+		}
+	}
+
+	return id, deadline, ok
+}
+
+// makeFuncWrapper returns a function which calls a js.Func with a given id.
+func (h *jsHost) makeFuncWrapper(_ context.Context, _ any, args []any) (any, error) {
+	id := jsArgInt(args, 0)
+	return newJSFunc(func(ctx context.Context, this any, args []any) (any, error) {
+		event := newJSObject(map[string]any{
+			"id":   float64(id),
+			"this": this,
+			"args": &jsArray{elems: args},
+		})
+
+		h.goObj.props["_pendingEvent"] = event
+		if err := h.resume(ctx); err != nil {
+			// Program exit or interrupt has to stop all pending imported function calls.
+			panic(err)
+		}
+
+		return event.props["result"], nil
+	}), nil
+}
+
+// writer returns output writer for a file descriptor.
+func (h *jsHost) writer(fd int64) io.Writer {
+	switch fd {
+	case 1:
+		return h.stdout
+	case 2:
+		return h.stderr
+	}
+
+	return nil
+}
+
+// newGlobal returns globalThis object with a subset of JavaScript and Node.js APIs used by Go.
+func (h *jsHost) newGlobal() *jsObject {
+	enosysFn := newJSFunc(func(context.Context, any, []any) (any, error) {
+		return nil, jsException{value: enosys()}
+	})
+
+	fs := newJSObject(map[string]any{
+		"constants": newJSObject(map[string]any{
+			// Unused as files can't be opened.
+			"O_WRONLY": float64(-1), "O_RDWR": float64(-1), "O_CREAT": float64(-1), "O_TRUNC": float64(-1),
+			"O_APPEND": float64(-1), "O_EXCL": float64(-1), "O_DIRECTORY": float64(-1),
+		}),
+		"write": newJSFunc(h.fsWrite),
+		"fsync": newJSFunc(func(ctx context.Context, _ any, args []any) (any, error) {
+			return jsCallback(ctx, args, jsNull)
+		}),
+	})
+
+	for _, name := range []string{
+		"chmod", "chown", "close", "fchmod", "fchown", "fstat", "ftruncate", "lchown", "link", "lstat",
+		"mkdir", "open", "read", "readdir", "readlink", "rename", "rmdir", "stat", "symlink", "truncate",
+		"unlink", "utimes",
+	} {
+		fs.props[name] = newJSFunc(func(ctx context.Context, _ any, args []any) (any, error) {
+			_, err := jsCallback(ctx, args, enosys())
+			return nil, err
+		})
+	}
+
+	idFn := newJSFunc(func(context.Context, any, []any) (any, error) {
+		return float64(-1), nil
+	})
+
+	process := newJSObject(map[string]any{
+		"getuid":    idFn,
+		"getgid":    idFn,
+		"geteuid":   idFn,
+		"getegid":   idFn,
+		"getgroups": enosysFn,
+		"pid":       float64(-1),
+		"ppid":      float64(-1),
+		"umask":     enosysFn,
+		"cwd":       enosysFn,
+		"chdir":     enosysFn,
+	})
+
+	path := newJSObject(map[string]any{
+		"resolve": newJSFunc(func(_ context.Context, _ any, args []any) (any, error) {
+			segments := make([]string, len(args))
+			for i, arg := range args {
+				segments[i] = jsToString(arg)
+			}
+
+			return strings.Join(segments, "/"), nil
+		}),
+	})
+
+	console := newJSObject(map[string]any{
+		"log":   newJSFunc(jsConsoleWriter(h.stdout)),
+		"warn":  newJSFunc(jsConsoleWriter(h.stderr)),
+		"error": newJSFunc(jsConsoleWriter(h.stderr)),
+	})
+
+	object := &jsObject{
+		props: make(map[string]any),
+		construct: func([]any) (any, error) {
+			return newJSObject(nil), nil
+		},
+		isInstance: func(v any) bool {
+			switch v.(type) {
+			case *jsObject, *jsArray, *jsBytes:
+				return true
+			}
+
+			return false
+		},
+	}
+
+	array := &jsObject{
+		props: make(map[string]any),
+		construct: func(args []any) (any, error) {
+			n := max(jsArgInt(args, 0), 0)
+			if !h.canAlloc(jsArraySize(n)) {
+				return nil, throwError("Invalid array length")
+			}
+
+			return &jsArray{elems: make([]any, n)}, nil
+		},
+		isInstance: func(v any) bool {
+			_, ok := v.(*jsArray)
+			return ok
+		},
+	}
+
+	uint8Array := &jsObject{
+		props: make(map[string]any),
+		construct: func(args []any) (any, error) {
+			n := max(jsArgInt(args, 0), 0)
+			if !h.canAlloc(uint64(n)) {
+				return nil, throwError("Array buffer allocation failed")
+			}
+
+			return &jsBytes{b: make([]byte, n)}, nil
+		},
+		isInstance: func(v any) bool {
+			_, ok := v.(*jsBytes)
+			return ok
+		},
+	}
+
+	date := &jsObject{
+		props: make(map[string]any),
+		construct: func([]any) (any, error) {
+			// Programs run in UTC time zone.
+			return newJSObject(map[string]any{
+				"getTimezoneOffset": newJSFunc(func(context.Context, any, []any) (any, error) {
+					return float64(0), nil
+				}),
+			}), nil
+		},
+	}
+
+	return newJSObject(map[string]any{
+		"Object":     object,
+		"Array":      array,
+		"Uint8Array": uint8Array,
+		"Date":       date,
+		"fs":         fs,
+		"process":    process,
+		"path":       path,
+		"console":    console,
+	})
+}
+
+// fsWrite implements fs.write(fd, buffer, offset, length, position, callback).
+//
+// Only writes to stdout and stderr are supported.
+func (h *jsHost) fsWrite(ctx context.Context, _ any, args []any) (any, error) {
+	var buf *jsBytes
+	if len(args) > 1 {
+		buf, _ = args[1].(*jsBytes)
+	}
+
+	w := h.writer(jsArgInt(args, 0))
+	if w == nil || buf == nil || len(args) < 6 || jsArgInt(args, 2) != 0 ||
+		jsArgInt(args, 3) != int64(len(buf.b)) || args[4] != jsNull {
+		_, err := jsCallback(ctx, args, enosys())
+		return nil, err
+	}
+
+	n, _ := w.Write(buf.b)
+	_, err := jsCallback(ctx, args, jsNull, float64(n))
+	return nil, err
+}
+
+// jsCallback calls a Node.js-style callback passed as the last function argument.
+func jsCallback(ctx context.Context, args []any, cbArgs ...any) (any, error) {
+	if len(args) == 0 {
+		return nil, throwError("callback is not a function")
+	}
+
+	return jsApply(ctx, args[len(args)-1], nil, cbArgs)
+}
+
+func jsConsoleWriter(w io.Writer) jsFunc {
+	return func(_ context.Context, _ any, args []any) (any, error) {
+		parts := make([]string, len(args))
+		for i, arg := range args {
+			parts[i] = jsToString(arg)
+		}
+
+		_, _ = io.WriteString(w, strings.Join(parts, " ")+"\n")
+		return nil, nil
+	}
+}
+
+// writeArgs writes command line arguments to linear memory and returns argc and argv.
+func writeArgs(m memory, args []string) (argc, argv uint32, err error) {
+	offset := uint32(argsOffset)
+	ptrs := make([]uint32, 0, len(args)+2)
+	for _, arg := range args {
+		ptrs = append(ptrs, offset)
+		if !m.Write(offset, append([]byte(arg), 0)) {
+			return 0, 0, errMemoryOutOfRange
+		}
+
+		offset += uint32(len(arg) + 1)
+		if rem := offset % 8; rem != 0 {
+			offset += 8 - rem
+		}
+	}
+
+	// Argument and environment variable lists are terminated by null pointers.
+	// Program environment is empty.
+	ptrs = append(ptrs, 0, 0)
+
+	argv = offset
+	for _, ptr := range ptrs {
+		if !m.WriteUint64Le(offset, uint64(ptr)) {
+			return 0, 0, errMemoryOutOfRange
+		}
+
+		offset += 8
+	}
+
+	if offset >= wasmMinDataAddr {
+		return 0, 0, errors.New("total length of command line and environment variables exceeds limit")
+	}
+
+	return uint32(len(args)), argv, nil
+}
+
+// wasmExit implements "func wasmExit(code int32)".
+//
+// Exit is reported in the same way as WASI proc_exit does.
+func (h *jsHost) wasmExit(ctx context.Context, m memory, sp uint32) {
+	code := m.getUint32(sp + 8)
+	_ = h.mod.CloseWithExitCode(ctx, code)
+	panic(sys.NewExitError(code))
+}
+
+// wasmWrite implements "func wasmWrite(fd uintptr, p unsafe.Pointer, n int32)".
+func (h *jsHost) wasmWrite(_ context.Context, m memory, sp uint32) {
+	fd := m.getInt64(sp + 8)
+	p := m.getInt64(sp + 16)
+	n := m.getUint32(sp + 24)
+	if w := h.writer(fd); w != nil {
+		_, _ = w.Write(m.read(uint32(p), n))
+	}
+}
+
+// nanotime implements "func nanotime1() int64".
+func (h *jsHost) nanotime(_ context.Context, m memory, sp uint32) {
+	m.setInt64(sp+8, h.start.UnixNano()+time.Since(h.start).Nanoseconds())
+}
+
+// walltime implements "func walltime() (sec int64, nsec int32)".
+func (h *jsHost) walltime(_ context.Context, m memory, sp uint32) {
+	now := time.Now()
+	m.setInt64(sp+8, now.Unix())
+	m.setUint32(sp+16, uint32(now.Nanosecond()))
+}
+
+// scheduleTimeoutEvent implements "func scheduleTimeoutEvent(delay int64) int32".
+func (h *jsHost) scheduleTimeoutEvent(_ context.Context, m memory, sp uint32) {
+	delay := time.Duration(m.getInt64(sp+8)) * time.Millisecond
+	id := h.nextTimeoutID
+	h.nextTimeoutID++
+	h.timeouts[id] = time.Now().Add(delay)
+	m.setUint32(sp+16, uint32(id))
+}
+
+// clearTimeoutEvent implements "func clearTimeoutEvent(id int32)".
+func (h *jsHost) clearTimeoutEvent(_ context.Context, m memory, sp uint32) {
+	delete(h.timeouts, int32(m.getUint32(sp+8)))
+}
+
+// getRandomData implements "func getRandomData(r []byte)".
+func (h *jsHost) getRandomData(_ context.Context, m memory, sp uint32) {
+	_, _ = rand.Read(m.loadSlice(sp + 8))
+}
+
+// finalizeRef implements "func finalizeRef(v ref)".
+func (h *jsHost) finalizeRef(_ context.Context, m memory, sp uint32) {
+	id := m.getUint32(sp + 8)
+	h.refCounts[id]--
+	if h.refCounts[id] == 0 {
+		h.free(jsValueOverhead + jsValueSize(h.values[id]))
+		delete(h.ids, h.values[id])
+		h.values[id] = nil
+		h.idPool = append(h.idPool, id)
+	}
+}
+
+// stringVal implements "func stringVal(value string) ref".
+func (h *jsHost) stringVal(_ context.Context, m memory, sp uint32) {
+	h.storeValue(m, sp+24, m.loadString(sp+8))
+}
+
+// valueGet implements "func valueGet(v ref, p string) ref".
+func (h *jsHost) valueGet(_ context.Context, m memory, sp uint32) {
+	result, err := jsGet(h.loadValue(m, sp+8), m.loadString(sp+16))
+	if err != nil {
+		panic(err)
+	}
+
+	h.storeValue(m, sp+32, result)
+}
+
+// valueSet implements "func valueSet(v ref, p string, x ref)".
+//
+// Stored value is accounted as it stays referenced by an object after Go releases it.
+func (h *jsHost) valueSet(_ context.Context, m memory, sp uint32) {
+	key, x := m.loadString(sp+16), h.loadValue(m, sp+32)
+	h.alloc(jsValueOverhead + uint64(len(key)) + jsValueSize(x))
+	if err := jsSet(h.loadValue(m, sp+8), key, x); err != nil {
+		panic(err)
+	}
+}
+
+// valueDelete implements "func valueDelete(v ref, p string)".
+func (h *jsHost) valueDelete(_ context.Context, m memory, sp uint32) {
+	if obj, ok := h.loadValue(m, sp+8).(*jsObject); ok {
+		delete(obj.props, m.loadString(sp+16))
+	}
+}
+
+// valueIndex implements "func valueIndex(v ref, i int) ref".
+func (h *jsHost) valueIndex(_ context.Context, m memory, sp uint32) {
+	result, err := jsIndex(h.loadValue(m, sp+8), m.getInt64(sp+16))
+	if err != nil {
+		panic(err)
+	}
+
+	h.storeValue(m, sp+24, result)
+}
+
+// valueSetIndex implements "func valueSetIndex(v ref, i int, x ref)".
+//
+// Array growth and stored value are accounted like in valueSet.
+func (h *jsHost) valueSetIndex(_ context.Context, m memory, sp uint32) {
+	v, i, x := h.loadValue(m, sp+8), m.getInt64(sp+16), h.loadValue(m, sp+24)
+	if arr, ok := v.(*jsArray); ok && i >= int64(len(arr.elems)) {
+		h.alloc(jsArraySize(i + 1 - int64(len(arr.elems))))
+	}
+
+	h.alloc(jsValueOverhead + jsValueSize(x))
+	if err := jsSetIndex(v, i, x); err != nil {
+		panic(err)
+	}
+}
+
+// valueCall implements "func valueCall(v ref, m string, args []ref) (ref, bool)".
+func (h *jsHost) valueCall(ctx context.Context, m memory, sp uint32) {
+	v := h.loadValue(m, sp+8)
+	fn, err := jsGet(v, m.loadString(sp+16))
+	var result any
+	if err == nil {
+		result, err = jsApply(ctx, fn, v, h.loadSliceOfValues(m, sp+32))
+	}
+
+	sp = h.getsp(ctx)
+	h.storeResult(m, sp+56, result, err)
+}
+
+// valueInvoke implements "func valueInvoke(v ref, args []ref) (ref, bool)".
+func (h *jsHost) valueInvoke(ctx context.Context, m memory, sp uint32) {
+	result, err := jsApply(ctx, h.loadValue(m, sp+8), nil, h.loadSliceOfValues(m, sp+16))
+	sp = h.getsp(ctx)
+	h.storeResult(m, sp+40, result, err)
+}
+
+// valueNew implements "func valueNew(v ref, args []ref) (ref, bool)".
+func (h *jsHost) valueNew(ctx context.Context, m memory, sp uint32) {
+	result, err := jsConstruct(h.loadValue(m, sp+8), h.loadSliceOfValues(m, sp+16))
+	sp = h.getsp(ctx)
+	h.storeResult(m, sp+40, result, err)
+}
+
+// valueLength implements "func valueLength(v ref) int".
+func (h *jsHost) valueLength(_ context.Context, m memory, sp uint32) {
+	length, err := jsGet(h.loadValue(m, sp+8), "length")
+	if err != nil {
+		panic(err)
+	}
+
+	f, _ := length.(float64)
+	m.setInt64(sp+16, int64(f))
+}
+
+// valuePrepareString implements "func valuePrepareString(v ref) (ref, int)".
+func (h *jsHost) valuePrepareString(_ context.Context, m memory, sp uint32) {
+	str := jsToString(h.loadValue(m, sp+8))
+	h.storeValue(m, sp+16, &jsBytes{b: []byte(str)})
+	m.setInt64(sp+24, int64(len(str)))
+}
+
+// valueLoadString implements "func valueLoadString(v ref, b []byte)".
+func (h *jsHost) valueLoadString(_ context.Context, m memory, sp uint32) {
+	if str, ok := h.loadValue(m, sp+8).(*jsBytes); ok {
+		copy(m.loadSlice(sp+16), str.b)
+	}
+}
+
+// valueInstanceOf implements "func valueInstanceOf(v ref, t ref) bool".
+func (h *jsHost) valueInstanceOf(_ context.Context, m memory, sp uint32) {
+	m.setBool(sp+24, jsInstanceOf(h.loadValue(m, sp+8), h.loadValue(m, sp+16)))
+}
+
+// copyBytesToGo implements "func copyBytesToGo(dst []byte, src ref) (int, bool)".
+func (h *jsHost) copyBytesToGo(_ context.Context, m memory, sp uint32) {
+	dst := m.loadSlice(sp + 8)
+	src, ok := h.loadValue(m, sp+32).(*jsBytes)
+	if !ok {
+		m.setBool(sp+48, false)
+		return
+	}
+
+	m.setInt64(sp+40, int64(copy(dst, src.b)))
+	m.setBool(sp+48, true)
+}
+
+// copyBytesToJS implements "func copyBytesToJS(dst ref, src []byte) (int, bool)".
+func (h *jsHost) copyBytesToJS(_ context.Context, m memory, sp uint32) {
+	dst, ok := h.loadValue(m, sp+8).(*jsBytes)
+	if !ok {
+		m.setBool(sp+48, false)
+		return
+	}
+
+	m.setInt64(sp+40, int64(copy(dst.b, m.loadSlice(sp+16))))
+	m.setBool(sp+48, true)
+}
+
+// canAlloc reports whether n bytes of host memory can be used by JavaScript values.
+func (h *jsHost) canAlloc(n uint64) bool {
+	return n <= h.maxAllocated-h.allocated
+}
+
+// alloc accounts host memory used by JavaScript values.
+//
+// Program is aborted if memory limit is exceeded.
+func (h *jsHost) alloc(n uint64) {
+	if !h.canAlloc(n) {
+		panic(errHostMemoryLimit)
+	}
+
+	h.allocated += n
+}
+
+// free releases host memory accounted by alloc.
+func (h *jsHost) free(n uint64) {
+	h.allocated -= min(n, h.allocated)
+}
+
+// storeResult stores a function call result or a thrown exception followed by success flag.
+func (h *jsHost) storeResult(m memory, addr uint32, result any, err error) {
+	var exc jsException
+	if errors.As(err, &exc) {
+		h.storeValue(m, addr, exc.value)
+		m.setBool(addr+8, false)
+		return
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	h.storeValue(m, addr, result)
+	m.setBool(addr+8, true)
+}
+
+// loadValue reads NaN-boxed value reference.
+func (h *jsHost) loadValue(m memory, addr uint32) any {
+	f := m.getFloat64(addr)
+	if f == 0 {
+		return nil
+	}
+
+	if !math.IsNaN(f) {
+		return f
+	}
+
+	id := m.getUint32(addr)
+	if id >= uint32(len(h.values)) {
+		panic(fmt.Errorf("invalid value reference %d", id))
+	}
+
+	return h.values[id]
+}
+
+// storeValue writes NaN-boxed value reference.
+func (h *jsHost) storeValue(m memory, addr uint32, v any) {
+	if f, ok := v.(float64); ok && f != 0 {
+		if math.IsNaN(f) {
+			m.setUint32(addr+4, nanHead)
+			m.setUint32(addr, refNaN)
+			return
+		}
+
+		m.setFloat64(addr, f)
+		return
+	}
+
+	if v == nil {
+		m.setFloat64(addr, 0)
+		return
+	}
+
+	id, ok := h.ids[v]
+	if !ok {
+		if n := len(h.idPool); n > 0 {
+			id = h.idPool[n-1]
+			h.idPool = h.idPool[:n-1]
+			h.values[id] = v
+			h.refCounts[id] = 0
+		} else {
+			id = uint32(len(h.values))
+			h.values = append(h.values, v)
+			h.refCounts = append(h.refCounts, 0)
+		}
+
+		h.alloc(jsValueOverhead + jsValueSize(v))
+		h.ids[v] = id
+	}
+
+	h.refCounts[id]++
+	m.setUint32(addr+4, nanHead|jsTypeFlag(v))
+	m.setUint32(addr, id)
+}
+
+func (h *jsHost) loadSliceOfValues(m memory, addr uint32) []any {
+	array := uint32(m.getInt64(addr))
+	length := uint32(m.getInt64(addr + 8))
+	values := make([]any, length)
+	for i := range values {
+		values[i] = h.loadValue(m, array+uint32(i)*8)
+	}
+
+	return values
+}
+
+// memory provides access to linear memory with the same semantics as DataView in wasm_exec.js.
+//
+// Out of range access panics and aborts a program.
+type memory struct {
+	api.Memory
+}
+
+func (m memory) getUint32(addr uint32) uint32 {
+	v, ok := m.ReadUint32Le(addr)
+	if !ok {
+		panic(errMemoryOutOfRange)
+	}
+
+	return v
+}
+
+func (m memory) getInt64(addr uint32) int64 {
+	v, ok := m.ReadUint64Le(addr)
+	if !ok {
+		panic(errMemoryOutOfRange)
+	}
+
+	return int64(v)
+}
+
+func (m memory) getFloat64(addr uint32) float64 {
+	v, ok := m.ReadFloat64Le(addr)
+	if !ok {
+		panic(errMemoryOutOfRange)
+	}
+
+	return v
+}
+
+func (m memory) setUint32(addr, v uint32) {
+	if !m.WriteUint32Le(addr, v) {
+		panic(errMemoryOutOfRange)
+	}
+}
+
+func (m memory) setInt64(addr uint32, v int64) {
+	if !m.WriteUint64Le(addr, uint64(v)) {
+		panic(errMemoryOutOfRange)
+	}
+}
+
+func (m memory) setFloat64(addr uint32, v float64) {
+	if !m.WriteFloat64Le(addr, v) {
+		panic(errMemoryOutOfRange)
+	}
+}
+
+func (m memory) setBool(addr uint32, v bool) {
+	var b byte
+	if v {
+		b = 1
+	}
+
+	if !m.WriteByte(addr, b) {
+		panic(errMemoryOutOfRange)
+	}
+}
+
+func (m memory) read(addr, n uint32) []byte {
+	b, ok := m.Read(addr, n)
+	if !ok {
+		panic(errMemoryOutOfRange)
+	}
+
+	return b
+}
+
+// loadSlice returns a view of a Go byte slice located at addr.
+func (m memory) loadSlice(addr uint32) []byte {
+	return m.read(uint32(m.getInt64(addr)), uint32(m.getInt64(addr+8)))
+}
+
+// loadString returns a copy of a Go string located at addr.
+func (m memory) loadString(addr uint32) string {
+	return string(m.loadSlice(addr))
+}
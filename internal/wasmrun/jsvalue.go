@@ -0,0 +1,319 @@
+package wasmrun
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// JavaScript values are represented using Go types:
+//
+//   - undefined is nil
+//   - null is jsNull
+//   - numbers are float64
+//   - booleans are bool
+//   - strings are string
+//   - objects and functions are *jsObject
+//   - arrays are *jsArray
+//   - Uint8Array instances are *jsBytes
+
+// jsNullValue is a type of JavaScript null value.
+type jsNullValue struct{}
+
+// jsNull is JavaScript null value.
+var jsNull = jsNullValue{}
+
+// jsFunc is a JavaScript function implementation.
+//
+// Returned error is a thrown JavaScript exception.
+type jsFunc func(ctx context.Context, this any, args []any) (any, error)
+
+// jsObject is a JavaScript object.
+//
+// Object is a function if call is set and a constructor if construct is set.
+type jsObject struct {
+	props map[string]any
+
+	call      jsFunc
+	construct func(args []any) (any, error)
+
+	// isInstance reports whether a value was created by a constructor, used by instanceof operator.
+	isInstance func(v any) bool
+}
+
+func newJSObject(props map[string]any) *jsObject {
+	if props == nil {
+		props = make(map[string]any)
+	}
+
+	return &jsObject{props: props}
+}
+
+func newJSFunc(fn jsFunc) *jsObject {
+	return &jsObject{props: make(map[string]any), call: fn}
+}
+
+// jsArray is a JavaScript array.
+type jsArray struct {
+	elems []any
+}
+
+// jsBytes is a JavaScript Uint8Array.
+type jsBytes struct {
+	b []byte
+}
+
+// jsException is a thrown JavaScript value.
+type jsException struct {
+	value any
+}
+
+func (e jsException) Error() string {
+	return "JavaScript exception: " + jsToString(e.value)
+}
+
+// newJSError returns a JavaScript Error object with a message and optional code.
+func newJSError(code, msg string) *jsObject {
+	props := map[string]any{"message": msg}
+	if code != "" {
+		props["code"] = code
+	}
+
+	return newJSObject(props)
+}
+
+// enosys returns "not implemented" error used by wasm_exec.js stubs.
+func enosys() *jsObject {
+	return newJSError("ENOSYS", "not implemented")
+}
+
+// throwError returns exception which throws a JavaScript Error.
+func throwError(msg string) error {
+	return jsException{value: newJSError("", msg)}
+}
+
+// jsTypeFlag returns type flag used in a NaN-boxed value reference.
+func jsTypeFlag(v any) uint32 {
+	switch t := v.(type) {
+	case *jsObject:
+		if t.call != nil || t.construct != nil {
+			return 4
+		}
+
+		return 1
+	case *jsArray, *jsBytes:
+		return 1
+	case string:
+		return 2
+	}
+
+	return 0
+}
+
+// jsGet implements Reflect.get.
+func jsGet(v any, key string) (any, error) {
+	switch t := v.(type) {
+	case *jsObject:
+		return t.props[key], nil
+	case *jsArray:
+		if key == "length" {
+			return float64(len(t.elems)), nil
+		}
+	case *jsBytes:
+		if key == "length" || key == "byteLength" {
+			return float64(len(t.b)), nil
+		}
+	case string:
+		if key == "length" {
+			return float64(len(t)), nil
+		}
+	case nil, jsNullValue:
+		return nil, throwError(fmt.Sprintf("Cannot read properties of %s (reading '%s')", jsToString(v), key))
+	}
+
+	return nil, nil
+}
+
+// jsSet implements Reflect.set.
+func jsSet(v any, key string, x any) error {
+	switch t := v.(type) {
+	case *jsObject:
+		t.props[key] = x
+	case nil, jsNullValue:
+		return throwError(fmt.Sprintf("Cannot set properties of %s (setting '%s')", jsToString(v), key))
+	}
+
+	return nil
+}
+
+// jsIndex implements Reflect.get with an integer key.
+func jsIndex(v any, i int64) (any, error) {
+	switch t := v.(type) {
+	case *jsArray:
+		if i >= 0 && i < int64(len(t.elems)) {
+			return t.elems[i], nil
+		}
+	case *jsBytes:
+		if i >= 0 && i < int64(len(t.b)) {
+			return float64(t.b[i]), nil
+		}
+	case *jsObject:
+		return t.props[strconv.FormatInt(i, 10)], nil
+	case nil, jsNullValue:
+		return nil, throwError(fmt.Sprintf("Cannot read properties of %s (reading '%d')", jsToString(v), i))
+	}
+
+	return nil, nil
+}
+
+// jsSetIndex implements Reflect.set with an integer key.
+func jsSetIndex(v any, i int64, x any) error {
+	switch t := v.(type) {
+	case *jsArray:
+		if i < 0 {
+			return nil
+		}
+
+		for int64(len(t.elems)) <= i {
+			t.elems = append(t.elems, nil)
+		}
+
+		t.elems[i] = x
+	case *jsBytes:
+		if i >= 0 && i < int64(len(t.b)) {
+			f, _ := x.(float64)
+			t.b[i] = byte(int64(f))
+		}
+	case *jsObject:
+		t.props[strconv.FormatInt(i, 10)] = x
+	case nil, jsNullValue:
+		return throwError(fmt.Sprintf("Cannot set properties of %s (setting '%d')", jsToString(v), i))
+	}
+
+	return nil
+}
+
+// jsApply implements Reflect.apply.
+func jsApply(ctx context.Context, fn, this any, args []any) (any, error) {
+	obj, ok := fn.(*jsObject)
+	if !ok || obj.call == nil {
+		return nil, throwError(jsToString(fn) + " is not a function")
+	}
+
+	return obj.call(ctx, this, args)
+}
+
+// jsConstruct implements Reflect.construct.
+func jsConstruct(fn any, args []any) (any, error) {
+	obj, ok := fn.(*jsObject)
+	if !ok || obj.construct == nil {
+		return nil, throwError(jsToString(fn) + " is not a constructor")
+	}
+
+	return obj.construct(args)
+}
+
+// jsInstanceOf implements instanceof operator.
+func jsInstanceOf(v, t any) bool {
+	obj, ok := t.(*jsObject)
+	if !ok || obj.isInstance == nil {
+		return false
+	}
+
+	return obj.isInstance(v)
+}
+
+// jsToString converts a value to string like String() function does.
+func jsToString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "undefined"
+	case jsNullValue:
+		return "null"
+	case bool:
+		return strconv.FormatBool(t)
+	case string:
+		return t
+	case float64:
+		return jsNumberToString(t)
+	case *jsArray:
+		parts := make([]string, len(t.elems))
+		for i, e := range t.elems {
+			if e != nil && e != jsNull {
+				parts[i] = jsToString(e)
+			}
+		}
+
+		return strings.Join(parts, ",")
+	case *jsBytes:
+		parts := make([]string, len(t.b))
+		for i, b := range t.b {
+			parts[i] = strconv.Itoa(int(b))
+		}
+
+		return strings.Join(parts, ",")
+	case *jsObject:
+		if t.call != nil || t.construct != nil {
+			return "function () { [native code] }"
+		}
+
+		if msg, ok := t.props["message"].(string); ok {
+			return "Error: " + msg
+		}
+
+		return "[object Object]"
+	}
+
+	return fmt.Sprint(v)
+}
+
+func jsNumberToString(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	case math.Abs(f) >= 1e21 || (f != 0 && math.Abs(f) < 1e-6):
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// jsValueSize returns approximate host memory size of value data.
+func jsValueSize(v any) uint64 {
+	switch t := v.(type) {
+	case string:
+		return uint64(len(t))
+	case *jsBytes:
+		return uint64(len(t.b))
+	case *jsArray:
+		return jsArraySize(int64(len(t.elems)))
+	}
+
+	return 0
+}
+
+// jsArraySize returns approximate host memory size of array elements.
+func jsArraySize(n int64) uint64 {
+	const elemSize = 16
+	if n < 0 || uint64(n) > math.MaxUint64/elemSize {
+		return math.MaxUint64
+	}
+
+	return uint64(n) * elemSize
+}
+
+// jsArgInt returns integer value of a numeric function argument.
+func jsArgInt(args []any, i int) int64 {
+	if i >= len(args) {
+		return 0
+	}
+
+	f, _ := args[i].(float64)
+	return int64(f)
+}
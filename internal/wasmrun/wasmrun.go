@@ -0,0 +1,241 @@
+// Package wasmrun implements a server-side run backend which executes
+// WebAssembly programs using wazero runtime.
+//
+// Both WASI (GOOS=wasip1) and js/wasm programs are supported.
+// JavaScript host environment required by js/wasm programs is emulated by a Go-side
+// replacement of wasm_exec.js glue code.
+package wasmrun
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+	"go.uber.org/zap"
+
+	"github.com/x1unix/go-playground/internal/sandbox"
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+const (
+	// BackendName is run backend name used to select executor in API requests.
+	BackendName = "wasm-server"
+
+	// wasmPageSize is WebAssembly memory page size.
+	wasmPageSize = 64 * 1024
+
+	// maxMemoryPages is max number of pages addressable by 32-bit WebAssembly module.
+	maxMemoryPages = 65536
+
+	programName     = "prog.wasm"
+	timeoutErrorMsg = "timeout running program"
+)
+
+// Config is executor configuration.
+type Config struct {
+	// RunTimeout is max program execution time.
+	RunTimeout time.Duration
+
+	// MaxMemory is max program linear memory size in bytes.
+	//
+	// Zero value means no limit except 4GiB addressable by a module.
+	MaxMemory uint64
+
+	// MaxOutputSize is max size of program output in bytes.
+	MaxOutputSize int
+}
+
+// RunOptions are program run options.
+type RunOptions struct {
+	// Args is a list of program arguments.
+	Args []string
+
+	// CompilerOutput is optional compiler diagnostics reported before program output.
+	CompilerOutput string
+
+	// OnEvent is optional handler to receive program output events as they arrive.
+	OnEvent sandbox.EventHandler
+}
+
+// Executor runs WebAssembly programs on a server.
+//
+// Each program is executed in a separate runtime without access to host file system and network.
+type Executor struct {
+	log *zap.Logger
+	cfg Config
+}
+
+// NewExecutor returns a new executor.
+//
+// Programs are compiled on each run and compiled code isn't cached,
+// so memory used by compiled code is released once program exits.
+func NewExecutor(log *zap.Logger, cfg Config) *Executor {
+	return &Executor{
+		log: log.Named("wasmrun"),
+		cfg: cfg,
+	}
+}
+
+// Run executes a WebAssembly program.
+//
+// Program exit code and timeouts are reported as stderr output
+// to keep behavior consistent with the Go Playground API.
+// Exit code is also returned in CompileResponse.Status field.
+func (e *Executor) Run(ctx context.Context, program []byte, opts RunOptions) (*goplay.CompileResponse, error) {
+	if e.cfg.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.cfg.RunTimeout)
+		defer cancel()
+	}
+
+	rt := wazero.NewRuntimeWithConfig(ctx, e.runtimeConfig())
+	defer func() {
+		// Runtime has to be closed even if run context is done.
+		if err := rt.Close(context.WithoutCancel(ctx)); err != nil {
+			e.log.Warn("failed to close runtime", zap.Error(err))
+		}
+	}()
+
+	compiled, err := rt.CompileModule(ctx, program)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		return &goplay.CompileResponse{Errors: "invalid WebAssembly module: " + err.Error()}, nil
+	}
+
+	rec := sandbox.NewRecorder(e.cfg.MaxOutputSize, opts.OnEvent)
+	defer rec.Close()
+	if opts.CompilerOutput != "" {
+		_, _ = rec.Stderr().Write([]byte(opts.CompilerOutput))
+	}
+
+	args := append([]string{programName}, opts.Args...)
+	if isJSProgram(compiled) {
+		err = runJS(ctx, rt, compiled, rec, args, e.maxMemory())
+	} else {
+		err = runWASI(ctx, rt, compiled, rec, args)
+	}
+
+	var setupErr hostSetupError
+	if errors.As(err, &setupErr) {
+		return nil, setupErr.err
+	}
+
+	status, err := handleExit(ctx, rec, err)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.Close()
+	return &goplay.CompileResponse{Events: rec.Events(), Status: status}, nil
+}
+
+// hostSetupError is host module instantiation error which isn't caused by a program.
+type hostSetupError struct {
+	err error
+}
+
+func (err hostSetupError) Error() string {
+	return err.err.Error()
+}
+
+// runWASI runs a program built for WASI target.
+func runWASI(ctx context.Context, rt wazero.Runtime, compiled wazero.CompiledModule, rec *sandbox.Recorder, args []string) error {
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, rt); err != nil {
+		return hostSetupError{err: fmt.Errorf("failed to instantiate WASI host module: %w", err)}
+	}
+
+	modCfg := wazero.NewModuleConfig().
+		WithName("").
+		WithArgs(args...).
+		WithStdout(rec.Stdout()).
+		WithStderr(rec.Stderr()).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader)
+
+	_, err := rt.InstantiateModule(ctx, compiled, modCfg)
+	return err
+}
+
+// runJS runs a program built for js/wasm target using Go-side JavaScript host.
+func runJS(ctx context.Context, rt wazero.Runtime, compiled wazero.CompiledModule, rec *sandbox.Recorder, args []string, maxMemory uint64) error {
+	host := newJSHost(rec.Stdout(), rec.Stderr(), maxMemory)
+	if err := host.instantiate(ctx, rt); err != nil {
+		return hostSetupError{err: fmt.Errorf("failed to instantiate js host module: %w", err)}
+	}
+
+	return host.run(ctx, rt, compiled, args)
+}
+
+func (e *Executor) runtimeConfig() wazero.RuntimeConfig {
+	cfg := wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true)
+
+	if pages := memoryLimitPages(e.cfg.MaxMemory); pages > 0 {
+		cfg = cfg.WithMemoryLimitPages(pages)
+	}
+
+	return cfg
+}
+
+// maxMemory returns program memory limit in bytes.
+func (e *Executor) maxMemory() uint64 {
+	if pages := memoryLimitPages(e.cfg.MaxMemory); pages > 0 {
+		return uint64(pages) * wasmPageSize
+	}
+
+	return maxMemoryPages * wasmPageSize
+}
+
+// handleExit reports program termination reason to stderr and returns program exit code.
+//
+// Returns error only if program was interrupted by a client.
+func handleExit(ctx context.Context, rec *sandbox.Recorder, err error) (int, error) {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		if errors.Is(ctxErr, context.DeadlineExceeded) {
+			_, _ = rec.Stderr().Write([]byte("\n" + timeoutErrorMsg + "\n"))
+			return sandbox.ExitCodeTerminated, nil
+		}
+
+		return 0, ctxErr
+	}
+
+	if err == nil {
+		return 0, nil
+	}
+
+	var exitErr *sys.ExitError
+	if errors.As(err, &exitErr) {
+		code := int(exitErr.ExitCode())
+		if code == 0 {
+			return 0, nil
+		}
+
+		// Mimic "go run" behavior
+		_, _ = fmt.Fprintf(rec.Stderr(), "\nexit status %d\n", code)
+		return code, nil
+	}
+
+	// Runtime traps like out of bounds memory access.
+	_, _ = rec.Stderr().Write([]byte("\n" + err.Error() + "\n"))
+	return sandbox.ExitCodeTerminated, nil
+}
+
+// memoryLimitPages returns max number of memory pages for a memory size in bytes.
+func memoryLimitPages(size uint64) uint32 {
+	if size == 0 {
+		return 0
+	}
+
+	pages := max(size/wasmPageSize, 1)
+	return uint32(min(pages, maxMemoryPages))
+}
@@ -0,0 +1,246 @@
+package wasmrun
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/x1unix/go-playground/internal/sandbox"
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+func TestExecutor_Run(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping WebAssembly run test in short mode")
+	}
+
+	cases := map[string]struct {
+		src        string
+		opts       RunOptions
+		wantStatus int
+		check      func(t *testing.T, stdout, stderr string)
+	}{
+		"run program": {
+			src: "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "hello\n", stdout)
+				require.Empty(t, stderr)
+			},
+		},
+		"pass arguments": {
+			src:  "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() {\n\tfmt.Println(os.Args[1:])\n}\n",
+			opts: RunOptions{Args: []string{"-test.v"}},
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "[-test.v]\n", stdout)
+			},
+		},
+		"report compiler output": {
+			src:  "package main\n\nfunc main() {}\n",
+			opts: RunOptions{CompilerOutput: "./main.go:3:6: can inline main\n"},
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "./main.go:3:6: can inline main\n", stderr)
+			},
+		},
+		"report exit code": {
+			src:        "package main\n\nimport \"os\"\n\nfunc main() {\n\tos.Stderr.WriteString(\"bye\\n\")\n\tos.Exit(3)\n}\n",
+			wantStatus: 3,
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Empty(t, stdout)
+				require.Equal(t, "bye\n\nexit status 3\n", stderr)
+			},
+		},
+		"no file system access": {
+			src: "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() {\n\t_, err := os.ReadFile(\"/etc/passwd\")\n\tfmt.Println(err != nil)\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "true\n", stdout)
+			},
+		},
+		"limit memory": {
+			src:        "package main\n\nfunc main() {\n\tvar s [][]byte\n\tfor i := 0; i < 100; i++ {\n\t\ts = append(s, make([]byte, 10<<20))\n\t}\n\tprintln(len(s))\n}\n",
+			wantStatus: 2,
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Contains(t, stderr, "out of memory")
+			},
+		},
+		"stop on timeout": {
+			src:        "package main\n\nfunc main() {\n\tfor {\n\t}\n}\n",
+			wantStatus: sandbox.ExitCodeTerminated,
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Contains(t, stderr, timeoutErrorMsg)
+			},
+		},
+	}
+
+	executor := NewExecutor(zaptest.NewLogger(t), Config{
+		RunTimeout:    3 * time.Second,
+		MaxMemory:     256 << 20,
+		MaxOutputSize: 1024,
+	})
+
+	for _, goos := range []string{"wasip1", "js"} {
+		for n, c := range cases {
+			t.Run(goos+"/"+n, func(t *testing.T) {
+				program := buildProgram(t, goos, c.src)
+				rsp, err := executor.Run(context.Background(), program, c.opts)
+				require.NoError(t, err)
+				require.Empty(t, rsp.Errors)
+				require.Equal(t, c.wantStatus, rsp.Status)
+				stdout, stderr := joinEvents(rsp.Events)
+				c.check(t, stdout, stderr)
+			})
+		}
+	}
+}
+
+func TestExecutor_RunJS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping WebAssembly run test in short mode")
+	}
+
+	cases := map[string]struct {
+		src   string
+		check func(t *testing.T, stdout, stderr string)
+	}{
+		"call JavaScript functions": {
+			src: "package main\n\nimport \"syscall/js\"\n\nfunc main() {\n\tjs.Global().Get(\"console\").Call(\"log\", \"hello\", 42)\n\tarr := js.Global().Get(\"Array\").New(2)\n\tarr.SetIndex(1, \"x\")\n\tprintln(arr.Length(), arr.Index(1).String(), arr.InstanceOf(js.Global().Get(\"Array\")))\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "hello 42\n", stdout)
+				require.Equal(t, "2 x true\n", stderr)
+			},
+		},
+		"call Go functions": {
+			src: "package main\n\nimport (\n\t\"fmt\"\n\t\"syscall/js\"\n)\n\nfunc main() {\n\tf := js.FuncOf(func(this js.Value, args []js.Value) any {\n\t\treturn args[0].Int() * 2\n\t})\n\tdefer f.Release()\n\tfmt.Println(f.Invoke(21).Int())\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "42\n", stdout)
+				require.Empty(t, stderr)
+			},
+		},
+		"handle JavaScript exceptions": {
+			src: "package main\n\nimport (\n\t\"fmt\"\n\t\"syscall/js\"\n)\n\nfunc main() {\n\tdefer func() {\n\t\tfmt.Println(recover())\n\t}()\n\tjs.Global().Get(\"process\").Call(\"cwd\")\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "JavaScript error: not implemented\n", stdout)
+			},
+		},
+		"wait for timers": {
+			src: "package main\n\nimport (\n\t\"fmt\"\n\t\"time\"\n)\n\nfunc main() {\n\tstart := time.Now()\n\ttime.Sleep(100 * time.Millisecond)\n\tfmt.Println(time.Since(start) >= 100*time.Millisecond)\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "true\n", stdout)
+			},
+		},
+		"limit JavaScript memory": {
+			src: "package main\n\nimport (\n\t\"fmt\"\n\t\"syscall/js\"\n)\n\nfunc main() {\n\tdefer func() {\n\t\tfmt.Println(recover())\n\t}()\n\tjs.Global().Get(\"Uint8Array\").New(1 << 40)\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "JavaScript error: Array buffer allocation failed\n", stdout)
+			},
+		},
+		"abort when JavaScript values exceed memory limit": {
+			src: "package main\n\nimport (\n\t\"strings\"\n\t\"syscall/js\"\n)\n\nfunc main() {\n\tarr := js.Global().Get(\"Array\").New()\n\tfor i := 0; i < 1000; i++ {\n\t\tarr.SetIndex(i, strings.Repeat(\"x\", 1<<20)+string(rune(i)))\n\t}\n\tprintln(\"done\")\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Contains(t, stderr, "JavaScript values exceed memory limit")
+				require.NotContains(t, stderr, "done")
+			},
+		},
+		"report deadlock": {
+			src: "package main\n\nfunc main() {\n\tselect {}\n}\n",
+			check: func(t *testing.T, stdout, stderr string) {
+				require.Equal(t, "fatal error: all goroutines are asleep - deadlock!\n\nexit status 2\n", stderr)
+			},
+		},
+	}
+
+	executor := NewExecutor(zaptest.NewLogger(t), Config{
+		RunTimeout:    3 * time.Second,
+		MaxMemory:     256 << 20,
+		MaxOutputSize: 1024,
+	})
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			program := buildProgram(t, "js", c.src)
+			rsp, err := executor.Run(context.Background(), program, RunOptions{})
+			require.NoError(t, err)
+			require.Empty(t, rsp.Errors)
+			stdout, stderr := joinEvents(rsp.Events)
+			c.check(t, stdout, stderr)
+		})
+	}
+}
+
+func TestExecutor_RunInvalidModule(t *testing.T) {
+	executor := NewExecutor(zaptest.NewLogger(t), Config{})
+
+	rsp, err := executor.Run(context.Background(), []byte("not a wasm file"), RunOptions{})
+	require.NoError(t, err)
+	require.Contains(t, rsp.Errors, "invalid WebAssembly module")
+}
+
+func TestMemoryLimitPages(t *testing.T) {
+	cases := map[string]struct {
+		size   uint64
+		expect uint32
+	}{
+		"no limit": {
+			size:   0,
+			expect: 0,
+		},
+		"round down": {
+			size:   3*wasmPageSize + 100,
+			expect: 3,
+		},
+		"at least one page": {
+			size:   100,
+			expect: 1,
+		},
+		"max addressable memory": {
+			size:   8 << 30,
+			expect: maxMemoryPages,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			require.Equal(t, c.expect, memoryLimitPages(c.size))
+		})
+	}
+}
+
+// buildProgram compiles a program for WebAssembly target using host Go toolchain.
+func buildProgram(t *testing.T, goos, src string) []byte {
+	t.Helper()
+
+	workDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "go.mod"), []byte("module app\n\ngo 1.21\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "main.go"), []byte(src), 0644))
+
+	outFile := filepath.Join(workDir, "main.wasm")
+	cmd := exec.Command("go", "build", "-o", outFile, ".")
+	cmd.Dir = workDir
+	cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH=wasm", "GOTOOLCHAIN=local", "GOFLAGS=")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	program, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	return program
+}
+
+func joinEvents(events []*goplay.CompileEvent) (string, string) {
+	var stdout, stderr strings.Builder
+	for _, e := range events {
+		switch e.Kind {
+		case sandbox.KindStdout:
+			stdout.WriteString(e.Message)
+		case sandbox.KindStderr:
+			stderr.WriteString(e.Message)
+		}
+	}
+
+	return stdout.String(), stderr.String()
+}
@@ -47,6 +47,13 @@ func NewClient(baseUrl, userAgent string, timeout time.Duration) *Client {
 	}
 }
 
+// WrapTransport wraps client HTTP transport with a middleware.
+//
+// Can be used to instrument upstream requests. Method is not safe for concurrent use with requests.
+func (c *Client) WrapTransport(wrap func(rt http.RoundTripper) http.RoundTripper) {
+	c.client.Transport = wrap(c.client.Transport)
+}
+
 // NewDefaultClient returns Go Playground client with defaults
 func NewDefaultClient() *Client {
 	return NewClient(DefaultPlaygroundURL, DefaultUserAgent, 15*time.Second)
@@ -0,0 +1,104 @@
+package goplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MetadataFileName is a reserved snippet file name which contains snippet settings.
+//
+// Go Playground stores only snippet files, so settings like compiler options
+// are persisted as a separate file in a snippet txtar stream.
+const MetadataFileName = ".goplay"
+
+// SnippetMetadata contains snippet settings persisted in a metadata file.
+type SnippetMetadata struct {
+	// CompilerOptions is additional Go compiler arguments.
+	CompilerOptions string `json:"compilerOptions,omitempty"`
+
+	// Backend is run backend name.
+	Backend string `json:"backend,omitempty"`
+
+	// Vet indicates whether go vet is enabled.
+	Vet bool `json:"vet,omitempty"`
+}
+
+// IsZero returns whether metadata is empty.
+func (m SnippetMetadata) IsZero() bool {
+	return m == SnippetMetadata{}
+}
+
+// AppendMetadata appends metadata file to a snippet source in txtar format.
+//
+// Source is returned as-is if metadata is empty.
+func AppendMetadata(src []byte, m SnippetMetadata) ([]byte, error) {
+	if m.IsZero() {
+		return src, nil
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(src)+len(data)+len(MetadataFileName)+8))
+	buf.Write(src)
+	if len(src) > 0 && src[len(src)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+
+	txtarAppendFile(buf, MetadataFileName, data)
+	return buf.Bytes(), nil
+}
+
+// CutMetadata removes metadata file from a snippet source and returns parsed metadata.
+//
+// Returned source doesn't contain metadata file even if metadata is malformed.
+func CutMetadata(src []byte) ([]byte, SnippetMetadata, error) {
+	var m SnippetMetadata
+	rest, data, ok := cutMetadataFile(src)
+	if !ok {
+		return src, m, nil
+	}
+
+	if err := json.Unmarshal(data, &m); err != nil {
+		return rest, m, fmt.Errorf("malformed snippet metadata: %w", err)
+	}
+
+	return rest, m, nil
+}
+
+func cutMetadataFile(src []byte) (rest, data []byte, found bool) {
+	start, dataStart, end := -1, -1, len(src)
+	for offset := 0; offset < len(src); {
+		lineEnd := len(src)
+		if i := bytes.IndexByte(src[offset:], '\n'); i != -1 {
+			lineEnd = offset + i
+		}
+
+		fileName, isFileLine := isSeparatorLine(string(src[offset:lineEnd]))
+		if isFileLine {
+			if start != -1 {
+				end = offset
+				break
+			}
+
+			if fileName == MetadataFileName {
+				start = offset
+				dataStart = min(lineEnd+1, len(src))
+			}
+		}
+
+		offset = lineEnd + 1
+	}
+
+	if start == -1 {
+		return src, nil, false
+	}
+
+	rest = make([]byte, 0, len(src)-(end-start))
+	rest = append(rest, src[:start]...)
+	rest = append(rest, src[end:]...)
+	return rest, src[dataStart:end], true
+}
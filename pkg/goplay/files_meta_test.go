@@ -0,0 +1,81 @@
+package goplay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendMetadata(t *testing.T) {
+	cases := map[string]struct {
+		src    string
+		meta   SnippetMetadata
+		expect string
+	}{
+		"empty metadata": {
+			src:    "-- main.go --\npackage main\n",
+			expect: "-- main.go --\npackage main\n",
+		},
+		"append metadata": {
+			src:    "-- main.go --\npackage main\n",
+			meta:   SnippetMetadata{CompilerOptions: "-tags=foo", Backend: BackendGoTip, Vet: true},
+			expect: "-- main.go --\npackage main\n-- .goplay --\n{\"compilerOptions\":\"-tags=foo\",\"backend\":\"gotip\",\"vet\":true}\n",
+		},
+		"source without trailing newline": {
+			src:    "package main",
+			meta:   SnippetMetadata{Vet: true},
+			expect: "package main\n-- .goplay --\n{\"vet\":true}\n",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, err := AppendMetadata([]byte(c.src), c.meta)
+			require.NoError(t, err)
+			require.Equal(t, c.expect, string(got))
+		})
+	}
+}
+
+func TestCutMetadata(t *testing.T) {
+	cases := map[string]struct {
+		src        string
+		expectSrc  string
+		expectMeta SnippetMetadata
+		expectErr  string
+	}{
+		"no metadata": {
+			src:       "-- main.go --\npackage main\n",
+			expectSrc: "-- main.go --\npackage main\n",
+		},
+		"metadata at the end": {
+			src:        "-- main.go --\npackage main\n-- .goplay --\n{\"backend\":\"goprev\"}\n",
+			expectSrc:  "-- main.go --\npackage main\n",
+			expectMeta: SnippetMetadata{Backend: BackendGoPrev},
+		},
+		"metadata between files": {
+			src:        "-- main.go --\npackage main\n-- .goplay --\n{\"compilerOptions\":\"-race\"}\n-- foo.go --\npackage foo\n",
+			expectSrc:  "-- main.go --\npackage main\n-- foo.go --\npackage foo\n",
+			expectMeta: SnippetMetadata{CompilerOptions: "-race"},
+		},
+		"malformed metadata": {
+			src:       "-- main.go --\npackage main\n-- .goplay --\n{",
+			expectSrc: "-- main.go --\npackage main\n",
+			expectErr: "malformed snippet metadata",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			src, meta, err := CutMetadata([]byte(c.src))
+			require.Equal(t, c.expectSrc, string(src))
+			if c.expectErr != "" {
+				require.ErrorContains(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expectMeta, meta)
+		})
+	}
+}
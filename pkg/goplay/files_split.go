@@ -10,6 +10,10 @@ import (
 	"strings"
 )
 
+// DefaultProgramFileName is a file name used by Go Playground
+// for snippet contents before the first file separator.
+const DefaultProgramFileName = "prog.go"
+
 var delimiterRegEx = regexp.MustCompile(`(?i)^-- (.*) --$`)
 
 // supportedFileExtensions is a list of supported extensions except ".go" files.
@@ -95,7 +99,13 @@ type SplitFileOpts struct {
 //	func Foo() {
 //		...
 //	}
+//
+// Snippet metadata file is skipped, use CutMetadata to read it.
 func SplitFileSet(src string, opts SplitFileOpts) (map[string]string, error) {
+	if rest, _, ok := cutMetadataFile([]byte(src)); ok {
+		src = string(rest)
+	}
+
 	files := make(map[string]string)
 
 	currentFileName := opts.DefaultFileName
@@ -156,3 +166,31 @@ func SplitFileSet(src string, opts SplitFileOpts) (map[string]string, error) {
 
 	return files, nil
 }
+
+// LocateLine returns a file name and a line number inside that file for a 1-based line number of a multi-file snippet.
+//
+// Lines before the first file separator belong to a default file, empty file name is returned for them.
+// Returns false if line is a file separator or is out of range.
+func LocateLine(src string, line int) (fileName string, fileLine int, ok bool) {
+	lines := strings.Split(strings.TrimSuffix(src, "\n"), "\n")
+	if line < 1 || line > len(lines) {
+		return "", 0, false
+	}
+
+	fileStart := 0
+	for i, l := range lines[:line] {
+		name, isFileLine := isSeparatorLine(l)
+		if !isFileLine {
+			continue
+		}
+
+		if i == line-1 {
+			return "", 0, false
+		}
+
+		fileName = name
+		fileStart = i + 1
+	}
+
+	return fileName, line - fileStart, true
+}
@@ -68,6 +68,14 @@ func TestSplitFileSet(t *testing.T) {
 			defaultFileName: "init.go",
 			expectError:     true,
 		},
+		"Skip metadata file": {
+			src:             "-- main.go --\npackage main\n-- .goplay --\n{\"vet\":true}\n-- foo.go --\npackage foo",
+			defaultFileName: "init.go",
+			expected: map[string]string{
+				"main.go": "package main",
+				"foo.go":  "package foo",
+			},
+		},
 		"Unsupported file": {
 			src:             "-- main.go2 --\npackage main\n\nfunc main() {}",
 			defaultFileName: "init.go",
@@ -90,3 +98,55 @@ func TestSplitFileSet(t *testing.T) {
 		})
 	}
 }
+
+func TestLocateLine(t *testing.T) {
+	const src = "package main\n\nfunc main() {}\n-- foo.go --\npackage foo\n\nfunc Foo() {}\n-- go.mod --\nmodule example\n"
+	cases := map[string]struct {
+		line       int
+		expectFile string
+		expectLine int
+		expectOk   bool
+	}{
+		"default file": {
+			line:       3,
+			expectLine: 3,
+			expectOk:   true,
+		},
+		"first line of named file": {
+			line:       5,
+			expectFile: "foo.go",
+			expectLine: 1,
+			expectOk:   true,
+		},
+		"named file": {
+			line:       7,
+			expectFile: "foo.go",
+			expectLine: 3,
+			expectOk:   true,
+		},
+		"last file": {
+			line:       9,
+			expectFile: "go.mod",
+			expectLine: 1,
+			expectOk:   true,
+		},
+		"separator line": {
+			line: 4,
+		},
+		"out of range": {
+			line: 10,
+		},
+		"zero line": {
+			line: 0,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			fileName, fileLine, ok := LocateLine(src, c.line)
+			require.Equal(t, c.expectOk, ok)
+			require.Equal(t, c.expectFile, fileName)
+			require.Equal(t, c.expectLine, fileLine)
+		})
+	}
+}
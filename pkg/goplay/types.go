@@ -71,6 +71,9 @@ type CompileResponse struct {
 	Body   *string
 	Events []*CompileEvent
 	Errors string
+
+	// Status is program exit code.
+	Status int
 }
 
 // GetBody returns response body
@@ -7,6 +7,9 @@ import (
 	"net/http"
 )
 
+// ErrModuleNotFound is returned when module or its version is not available in proxy.
+var ErrModuleNotFound = errors.New("module not found")
+
 type HTTPError struct {
 	Code    int
 	URL     string
@@ -53,3 +56,26 @@ func IsNotFoundError(err error) (*HTTPError, bool) {
 
 	return httpErr, true
 }
+
+// IsNotFound returns whether error is a proxy response for a missing module or version.
+//
+// Official proxy returns 410 for modules which can't be fetched.
+func IsNotFound(err error) bool {
+	httpErr, ok := IsHTTPError(err)
+	if !ok {
+		return false
+	}
+
+	return httpErr.Code == http.StatusNotFound || httpErr.Code == http.StatusGone
+}
+
+// ModuleError wraps proxy request error for a module.
+//
+// Not found errors are replaced with ErrModuleNotFound.
+func ModuleError(module string, err error) error {
+	if IsNotFound(err) {
+		return fmt.Errorf("%w: %s", ErrModuleNotFound, module)
+	}
+
+	return fmt.Errorf("failed to fetch module %s: %w", module, err)
+}
@@ -0,0 +1,29 @@
+package goproxy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EscapePath escapes module path or version to be used in proxy request URL.
+//
+// Go modules proxy protocol requires each upper-case letter to be replaced
+// with an exclamation mark followed by the letter's lower-case equivalent.
+func EscapePath(s string) string {
+	if !strings.ContainsFunc(s, unicode.IsUpper) {
+		return s
+	}
+
+	sb := new(strings.Builder)
+	sb.Grow(len(s) + 4)
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			sb.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,20 @@
+package goproxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapePath(t *testing.T) {
+	cases := map[string]string{
+		"github.com/google/uuid":     "github.com/google/uuid",
+		"github.com/BurntSushi/toml": "github.com/!burnt!sushi/toml",
+		"v1.0.0-RC1":                 "v1.0.0-!r!c1",
+		"":                           "",
+	}
+
+	for input, expect := range cases {
+		require.Equal(t, expect, EscapePath(input), input)
+	}
+}
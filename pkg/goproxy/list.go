@@ -0,0 +1,203 @@
+package goproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+const (
+	proxyDirect = "direct"
+	proxyOff    = "off"
+)
+
+var (
+	// ErrProxyDisabled is returned when module lookup is disabled by "off" keyword in proxy list.
+	ErrProxyDisabled = errors.New("module lookup disabled by GOPROXY=off")
+
+	// ErrPrivateModule is returned for modules which match private modules patterns.
+	ErrPrivateModule = errors.New("module is private")
+)
+
+var (
+	_ Proxy = (*Client)(nil)
+	_ Proxy = (*ProxyList)(nil)
+)
+
+// Proxy is Go modules proxy API.
+//
+// Module paths and versions should be escaped using EscapePath.
+type Proxy interface {
+	// GetVersions returns a list of module versions.
+	GetVersions(ctx context.Context, pkgUrl string) ([]string, error)
+
+	// GetLatestVersion returns information about a latest module version.
+	GetLatestVersion(ctx context.Context, pkgUrl string) (*VersionInfo, error)
+
+	// GetVersionInfo returns information about module version.
+	GetVersionInfo(ctx context.Context, pkgUrl, version string) (*VersionInfo, error)
+
+	// GetModuleFile returns go.mod file of specific version of a module.
+	GetModuleFile(ctx context.Context, pkgUrl, version string) ([]byte, error)
+
+	// GetModuleSource returns zip archive stream for that version of the given module.
+	GetModuleSource(ctx context.Context, pkgUrl, version string) (*ArchiveReadCloser, error)
+}
+
+// ProxyConfig is Go modules proxies configuration.
+type ProxyConfig struct {
+	// Proxies is a list of proxy URLs in GOPROXY environment variable format.
+	//
+	// URLs separated by comma are used as a fallback only if module is not found.
+	// URLs separated by pipe are used as a fallback on any error.
+	//
+	// "direct" keyword is ignored as server can't fetch modules from version control systems.
+	Proxies string
+
+	// Private is a comma-separated list of glob patterns of private module path prefixes
+	// in GONOSUMDB or GOPRIVATE environment variable format.
+	//
+	// Private modules are never requested from proxies.
+	Private string
+}
+
+type proxyEntry struct {
+	// client is proxy client. Nil value means that module lookup is disabled.
+	client *Client
+
+	// fallbackOnError allows to fall back to next proxy on any error.
+	//
+	// Otherwise, next proxy is used only if module is not found.
+	fallbackOnError bool
+}
+
+// ProxyList queries a list of Go module proxies in order, like go command does with GOPROXY.
+type ProxyList struct {
+	entries []proxyEntry
+	private string
+}
+
+// NewProxyList returns a new proxies list from config.
+func NewProxyList(client *http.Client, cfg ProxyConfig) (*ProxyList, error) {
+	list := &ProxyList{private: cfg.Private}
+	value := cfg.Proxies
+	for value != "" {
+		var (
+			proxyURL        string
+			fallbackOnError bool
+		)
+
+		i := strings.IndexAny(value, ",|")
+		if i == -1 {
+			proxyURL, value = value, ""
+		} else {
+			proxyURL, fallbackOnError, value = value[:i], value[i] == '|', value[i+1:]
+		}
+
+		proxyURL = strings.TrimSpace(proxyURL)
+		switch proxyURL {
+		case "", proxyDirect:
+			continue
+		case proxyOff:
+			list.entries = append(list.entries, proxyEntry{})
+			continue
+		}
+
+		if !strings.HasPrefix(proxyURL, "https://") && !strings.HasPrefix(proxyURL, "http://") {
+			return nil, fmt.Errorf("invalid proxy URL %q: only http and https schemes are supported", proxyURL)
+		}
+
+		list.entries = append(list.entries, proxyEntry{
+			client:          NewClient(client, strings.TrimSuffix(proxyURL, "/")),
+			fallbackOnError: fallbackOnError,
+		})
+	}
+
+	if len(list.entries) == 0 {
+		return nil, fmt.Errorf("no proxy URLs in proxy list %q", cfg.Proxies)
+	}
+
+	return list, nil
+}
+
+// IsPrivate returns whether module path matches private modules patterns.
+func (l *ProxyList) IsPrivate(modPath string) bool {
+	return l.private != "" && module.MatchPrefixPatterns(l.private, modPath)
+}
+
+// GetVersions returns a list of module versions.
+func (l *ProxyList) GetVersions(ctx context.Context, pkgUrl string) ([]string, error) {
+	return queryProxies(ctx, l, pkgUrl, func(c *Client) ([]string, error) {
+		return c.GetVersions(ctx, pkgUrl)
+	})
+}
+
+// GetLatestVersion returns information about a latest module version.
+func (l *ProxyList) GetLatestVersion(ctx context.Context, pkgUrl string) (*VersionInfo, error) {
+	return queryProxies(ctx, l, pkgUrl, func(c *Client) (*VersionInfo, error) {
+		return c.GetLatestVersion(ctx, pkgUrl)
+	})
+}
+
+// GetVersionInfo returns information about module version.
+func (l *ProxyList) GetVersionInfo(ctx context.Context, pkgUrl, version string) (*VersionInfo, error) {
+	return queryProxies(ctx, l, pkgUrl, func(c *Client) (*VersionInfo, error) {
+		return c.GetVersionInfo(ctx, pkgUrl, version)
+	})
+}
+
+// GetModuleFile returns go.mod file of specific version of a module.
+func (l *ProxyList) GetModuleFile(ctx context.Context, pkgUrl, version string) ([]byte, error) {
+	return queryProxies(ctx, l, pkgUrl, func(c *Client) ([]byte, error) {
+		return c.GetModuleFile(ctx, pkgUrl, version)
+	})
+}
+
+// GetModuleSource returns zip archive stream for that version of the given module.
+func (l *ProxyList) GetModuleSource(ctx context.Context, pkgUrl, version string) (*ArchiveReadCloser, error) {
+	return queryProxies(ctx, l, pkgUrl, func(c *Client) (*ArchiveReadCloser, error) {
+		return c.GetModuleSource(ctx, pkgUrl, version)
+	})
+}
+
+// queryProxies calls each proxy in a list until request succeeds or fallback is not allowed.
+//
+// The last error is returned if all proxies failed.
+func queryProxies[T any](ctx context.Context, l *ProxyList, pkgUrl string, fn func(c *Client) (T, error)) (T, error) {
+	var zero T
+	modPath, err := module.UnescapePath(pkgUrl)
+	if err != nil {
+		modPath = pkgUrl
+	}
+
+	if l.IsPrivate(modPath) {
+		return zero, fmt.Errorf("%w: %s", ErrPrivateModule, modPath)
+	}
+
+	err = nil
+	for _, entry := range l.entries {
+		if entry.client == nil {
+			if err == nil {
+				err = ErrProxyDisabled
+			}
+
+			return zero, err
+		}
+
+		var result T
+		result, err = fn(entry.client)
+		if err == nil {
+			return result, nil
+		}
+
+		if ctx.Err() != nil || !(entry.fallbackOnError || IsNotFound(err)) {
+			return zero, err
+		}
+	}
+
+	return zero, err
+}
@@ -0,0 +1,139 @@
+package goproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProxyList(t *testing.T) {
+	cases := map[string]struct {
+		value     string
+		expect    []proxyEntry
+		expectErr string
+	}{
+		"single proxy": {
+			value:  "https://proxy.golang.org",
+			expect: []proxyEntry{{client: &Client{url: "https://proxy.golang.org"}}},
+		},
+		"fallback separators": {
+			value: "https://a.example.com/|https://b.example.com,direct,off",
+			expect: []proxyEntry{
+				{client: &Client{url: "https://a.example.com"}, fallbackOnError: true},
+				{client: &Client{url: "https://b.example.com"}},
+				{},
+			},
+		},
+		"invalid scheme": {
+			value:     "file:///tmp/proxy",
+			expectErr: `invalid proxy URL "file:///tmp/proxy": only http and https schemes are supported`,
+		},
+		"direct only": {
+			value:     "direct",
+			expectErr: `no proxy URLs in proxy list "direct"`,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, err := NewProxyList(nil, ProxyConfig{Proxies: c.value})
+			if c.expectErr != "" {
+				require.EqualError(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expect, got.entries)
+		})
+	}
+}
+
+func TestProxyList_GetVersions(t *testing.T) {
+	newProxy := func(t *testing.T, code int) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if code != http.StatusOK {
+				http.Error(w, http.StatusText(code), code)
+				return
+			}
+
+			_, _ = w.Write([]byte(r.Host + "\n"))
+		}))
+		t.Cleanup(srv.Close)
+		return srv.URL
+	}
+
+	cases := map[string]struct {
+		proxies   func(t *testing.T) string
+		private   string
+		module    string
+		expectErr string
+		expectIs  error
+		expectOK  int
+	}{
+		"fallback on not found": {
+			module: "example.com/foo",
+			proxies: func(t *testing.T) string {
+				return newProxy(t, http.StatusGone) + "," + newProxy(t, http.StatusOK)
+			},
+			expectOK: 1,
+		},
+		"no fallback on server error": {
+			module: "example.com/foo",
+			proxies: func(t *testing.T) string {
+				return newProxy(t, http.StatusInternalServerError) + "," + newProxy(t, http.StatusOK)
+			},
+			expectErr: "Internal Server Error\n (HTTP code: 500)",
+		},
+		"fallback on any error": {
+			module: "example.com/foo",
+			proxies: func(t *testing.T) string {
+				return newProxy(t, http.StatusInternalServerError) + "|" + newProxy(t, http.StatusOK)
+			},
+			expectOK: 1,
+		},
+		"off after not found": {
+			module: "example.com/foo",
+			proxies: func(t *testing.T) string {
+				return newProxy(t, http.StatusNotFound) + ",off," + newProxy(t, http.StatusOK)
+			},
+			expectErr: "Not Found\n (HTTP code: 404)",
+		},
+		"off": {
+			module: "example.com/foo",
+			proxies: func(t *testing.T) string {
+				return "off"
+			},
+			expectIs: ErrProxyDisabled,
+		},
+		"private module": {
+			module:  "corp.example.com/foo",
+			private: "*.example.com,example.org/private",
+			proxies: func(t *testing.T) string {
+				return newProxy(t, http.StatusOK)
+			},
+			expectIs: ErrPrivateModule,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			proxies := c.proxies(t)
+			list, err := NewProxyList(http.DefaultClient, ProxyConfig{Proxies: proxies, Private: c.private})
+			require.NoError(t, err)
+
+			got, err := list.GetVersions(context.Background(), c.module)
+			switch {
+			case c.expectIs != nil:
+				require.ErrorIs(t, err, c.expectIs)
+			case c.expectErr != "":
+				require.EqualError(t, err, c.expectErr)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, []string{list.entries[c.expectOK].client.url[len("http://"):]}, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package goproxy
+
+import "golang.org/x/mod/module"
+
+// ValidationError is invalid module path or version error.
+type ValidationError struct {
+	err error
+}
+
+func (err ValidationError) Error() string {
+	return err.err.Error()
+}
+
+func (err ValidationError) Unwrap() error {
+	return err.err
+}
+
+// CheckModule checks whether module path and version are valid.
+//
+// Version is optional. Returned error is ValidationError.
+func CheckModule(modPath, version string) error {
+	var err error
+	if version == "" {
+		err = module.CheckPath(modPath)
+	} else {
+		err = module.Check(modPath, version)
+	}
+
+	if err != nil {
+		return ValidationError{err: err}
+	}
+
+	return nil
+}
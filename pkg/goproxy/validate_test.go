@@ -0,0 +1,39 @@
+package goproxy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckModule(t *testing.T) {
+	cases := map[string]struct {
+		module    string
+		version   string
+		expectErr string
+	}{
+		"valid":                 {module: "github.com/google/uuid", version: "v1.6.0"},
+		"valid without version": {module: "golang.org/x/exp"},
+		"pseudo-version":        {module: "golang.org/x/exp", version: "v0.0.0-20230321023759-10a507213a29"},
+		"empty":                 {expectErr: `malformed module path "": empty string`},
+		"no dot":                {module: "foo/bar", expectErr: `malformed module path "foo/bar": missing dot in first path element`},
+		"dot elements":          {module: "example.com/../foo", expectErr: `malformed module path "example.com/../foo": invalid path element ".."`},
+		"trailing slash":        {module: "example.com/foo/", expectErr: `malformed module path "example.com/foo/": trailing slash`},
+		"invalid chars":         {module: "example.com/foo bar", expectErr: `malformed module path "example.com/foo bar": invalid char ' '`},
+		"invalid version":       {module: "example.com/foo", version: "latest", expectErr: `example.com/foo@latest: invalid version: not a semantic version`},
+		"major version":         {module: "example.com/foo", version: "v2.0.0", expectErr: `example.com/foo@v2.0.0: invalid version: should be v0 or v1, not v2`},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			err := CheckModule(c.module, c.version)
+			if c.expectErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.EqualError(t, err, c.expectErr)
+			require.ErrorAs(t, err, new(ValidationError))
+		})
+	}
+}
@@ -0,0 +1,213 @@
+// Package playground provides a client for the playground server API.
+//
+// Unlike goplay package, which talks to the official Go Playground,
+// this client works with self-hosted instances of this project.
+package playground
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultURL is default playground server URL.
+	DefaultURL = "http://localhost:8080"
+
+	jsonContentType = "application/json"
+)
+
+// APIError is error returned by a playground server.
+type APIError struct {
+	// StatusCode is HTTP response status code.
+	StatusCode int
+
+	// Message is error message returned by a server.
+	Message string
+
+	// Diagnostics is a list of build errors mapped to program files.
+	Diagnostics []Diagnostic
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("%s (HTTP %d)", err.Message, err.StatusCode)
+}
+
+// Client is playground server API client.
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+// NewClient returns a new client for a playground server at baseURL.
+func NewClient(client *http.Client, baseURL string) *Client {
+	return &Client{
+		url:    strings.TrimSuffix(baseURL, "/"),
+		client: client,
+	}
+}
+
+// SnippetURL returns link to open a shared snippet in a browser.
+func (c *Client) SnippetURL(snippetID string) string {
+	return c.url + "/snippet/" + url.PathEscape(snippetID)
+}
+
+// Run runs a program and returns its output.
+//
+// Build errors are returned as APIError.
+func (c *Client) Run(ctx context.Context, payload FilesPayload, opts RunOptions) (*RunResponse, error) {
+	query := url.Values{}
+	setQueryParam(query, "backend", opts.Backend)
+	if opts.Vet {
+		query.Set("vet", strconv.FormatBool(opts.Vet))
+	}
+
+	rsp := new(RunResponse)
+	if err := c.post(ctx, "/api/v2/run", query, payload, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+// Format formats files and returns formatted files or text edits depending on FormatOptions.Result.
+func (c *Client) Format(ctx context.Context, files map[string]string, opts FormatOptions) (*FormatResponse, error) {
+	query := url.Values{}
+	setQueryParam(query, "backend", opts.Backend)
+	setQueryParam(query, "formatter", opts.Formatter)
+	setQueryParam(query, "style", opts.Style)
+	setQueryParam(query, "local", opts.LocalPrefix)
+	setQueryParam(query, "result", opts.Result)
+
+	rsp := new(FormatResponse)
+	if err := c.post(ctx, "/api/v2/format", query, FilesPayload{Files: files}, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+// Share saves a snippet and returns snippet ID.
+func (c *Client) Share(ctx context.Context, payload FilesPayload) (string, error) {
+	rsp := new(shareResponse)
+	if err := c.post(ctx, "/api/v2/share", nil, payload, rsp); err != nil {
+		return "", err
+	}
+
+	return rsp.SnippetID, nil
+}
+
+// GetSnippet returns shared snippet by ID.
+func (c *Client) GetSnippet(ctx context.Context, snippetID string) (*FilesPayload, error) {
+	rsp, err := c.do(ctx, http.MethodGet, "/api/v2/share/"+url.PathEscape(snippetID), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rsp.Body.Close()
+	payload := new(FilesPayload)
+	if err := decodeJSON(rsp.Body, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// Compile builds a WebAssembly program.
+//
+// Use GetArtifact to download built program.
+func (c *Client) Compile(ctx context.Context, payload FilesPayload) (*BuildResponse, error) {
+	rsp := new(BuildResponse)
+	if err := c.post(ctx, "/api/v2/compile", nil, payload, rsp); err != nil {
+		return nil, err
+	}
+
+	return rsp, nil
+}
+
+// GetArtifact returns contents of a WebAssembly program built using Compile.
+//
+// File name is a value of BuildResponse.FileName field. Caller is responsible to close the reader.
+func (c *Client) GetArtifact(ctx context.Context, fileName string) (io.ReadCloser, error) {
+	rsp, err := c.do(ctx, http.MethodGet, "/api/artifacts/"+url.PathEscape(fileName), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return rsp.Body, nil
+}
+
+func (c *Client) post(ctx context.Context, path string, query url.Values, body, out any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	rsp, err := c.do(ctx, http.MethodPost, path, query, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	defer rsp.Body.Close()
+	return decodeJSON(rsp.Body, out)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Response, error) {
+	uri := c.url + path
+	if len(query) > 0 {
+		uri += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, uri, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare request %q: %w", uri, err)
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", jsonContentType)
+	}
+
+	rsp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if rsp.StatusCode >= 400 {
+		defer rsp.Body.Close()
+		return nil, newAPIError(rsp)
+	}
+
+	return rsp, nil
+}
+
+func setQueryParam(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
+
+func newAPIError(rsp *http.Response) *APIError {
+	err := &APIError{StatusCode: rsp.StatusCode, Message: http.StatusText(rsp.StatusCode)}
+
+	var errRsp errorResponse
+	if decodeErr := json.NewDecoder(rsp.Body).Decode(&errRsp); decodeErr == nil && errRsp.Error != "" {
+		err.Message = errRsp.Error
+		err.Diagnostics = errRsp.Diagnostics
+	}
+
+	return err
+}
+
+func decodeJSON(r io.Reader, out any) error {
+	if err := json.NewDecoder(r).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
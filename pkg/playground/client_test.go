@@ -0,0 +1,213 @@
+package playground
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+func TestClient(t *testing.T) {
+	files := map[string]string{"main.go": "package main\n"}
+	formatted := map[string]string{"main.go": "package main\n\nfunc main() {}\n"}
+	edits := map[string][]TextEdit{
+		"main.go": {
+			{
+				Range:   Range{Start: Position{Line: 1}, End: Position{Line: 1}},
+				NewText: "\nfunc main() {}\n",
+			},
+		},
+	}
+	diagnostics := []Diagnostic{
+		{File: "main.go", Line: 3, Column: 6, Severity: SeverityInfo, Message: "can inline main"},
+	}
+	buildErrors := []Diagnostic{
+		{File: "main.go", Line: 4, Column: 2, Severity: SeverityError, Message: "undefined: foo"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v2/run", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "wasm-server", r.URL.Query().Get("backend"))
+		require.Equal(t, "true", r.URL.Query().Get("vet"))
+		requireBody(t, r, FilesPayload{Files: files})
+		writeJSON(w, RunResponse{Events: []*goplay.CompileEvent{{Message: "hello\n", Kind: EventKindStdout}}, Status: 3})
+	})
+	mux.HandleFunc("POST /api/v2/format", func(w http.ResponseWriter, r *http.Request) {
+		requireBody(t, r, FilesPayload{Files: files})
+		query := r.URL.Query()
+		if query.Get("result") == FormatResultEdits {
+			require.Equal(t, FormatterLocal, query.Get("formatter"))
+			require.Equal(t, StyleGofumpt, query.Get("style"))
+			require.Equal(t, "example.com/foo", query.Get("local"))
+			writeJSON(w, FormatResponse{Edits: edits})
+			return
+		}
+
+		require.Equal(t, "gotip", query.Get("backend"))
+		require.False(t, query.Has("formatter"))
+		require.False(t, query.Has("style"))
+		require.False(t, query.Has("local"))
+		require.False(t, query.Has("result"))
+		writeJSON(w, FormatResponse{Files: formatted})
+	})
+	mux.HandleFunc("POST /api/v2/share", func(w http.ResponseWriter, r *http.Request) {
+		requireBody(t, r, FilesPayload{Files: files, Vet: true})
+		writeJSON(w, shareResponse{SnippetID: "abc"})
+	})
+	mux.HandleFunc("GET /api/v2/share/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "abc" {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, errorResponse{Error: "snippet not found"})
+			return
+		}
+
+		writeJSON(w, FilesPayload{Files: files, Backend: "gotip"})
+	})
+	mux.HandleFunc("POST /api/v2/compile", func(w http.ResponseWriter, r *http.Request) {
+		var payload FilesPayload
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		if payload.Target == "js" {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, errorResponse{Error: "./main.go:4:2: undefined: foo", Diagnostics: buildErrors})
+			return
+		}
+
+		require.Equal(t, FilesPayload{Files: files, Target: "wasip1"}, payload)
+		writeJSON(w, BuildResponse{FileName: "abc.wasm", Target: "wasip1", Diagnostics: diagnostics})
+	})
+	mux.HandleFunc("GET /api/artifacts/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("name") != "abc.wasm" {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte("\x00asm"))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewClient(srv.Client(), srv.URL+"/")
+	cases := map[string]struct {
+		run       func(ctx context.Context) (any, error)
+		expect    any
+		expectErr *APIError
+	}{
+		"run": {
+			run: func(ctx context.Context) (any, error) {
+				return client.Run(ctx, FilesPayload{Files: files}, RunOptions{Backend: "wasm-server", Vet: true})
+			},
+			expect: &RunResponse{Events: []*goplay.CompileEvent{{Message: "hello\n", Kind: EventKindStdout}}, Status: 3},
+		},
+		"format": {
+			run: func(ctx context.Context) (any, error) {
+				return client.Format(ctx, files, FormatOptions{Backend: "gotip"})
+			},
+			expect: &FormatResponse{Files: formatted},
+		},
+		"format with options": {
+			run: func(ctx context.Context) (any, error) {
+				return client.Format(ctx, files, FormatOptions{
+					Formatter:   FormatterLocal,
+					Style:       StyleGofumpt,
+					LocalPrefix: "example.com/foo",
+					Result:      FormatResultEdits,
+				})
+			},
+			expect: &FormatResponse{Edits: edits},
+		},
+		"share": {
+			run: func(ctx context.Context) (any, error) {
+				return client.Share(ctx, FilesPayload{Files: files, Vet: true})
+			},
+			expect: "abc",
+		},
+		"get snippet": {
+			run: func(ctx context.Context) (any, error) {
+				return client.GetSnippet(ctx, "abc")
+			},
+			expect: &FilesPayload{Files: files, Backend: "gotip"},
+		},
+		"snippet not found": {
+			run: func(ctx context.Context) (any, error) {
+				return client.GetSnippet(ctx, "foo")
+			},
+			expectErr: &APIError{StatusCode: http.StatusNotFound, Message: "snippet not found"},
+		},
+		"compile": {
+			run: func(ctx context.Context) (any, error) {
+				return client.Compile(ctx, FilesPayload{Files: files, Target: "wasip1"})
+			},
+			expect: &BuildResponse{FileName: "abc.wasm", Target: "wasip1", Diagnostics: diagnostics},
+		},
+		"compile error": {
+			run: func(ctx context.Context) (any, error) {
+				return client.Compile(ctx, FilesPayload{Files: files, Target: "js"})
+			},
+			expectErr: &APIError{
+				StatusCode:  http.StatusBadRequest,
+				Message:     "./main.go:4:2: undefined: foo",
+				Diagnostics: buildErrors,
+			},
+		},
+		"get artifact": {
+			run: func(ctx context.Context) (any, error) {
+				rc, err := client.GetArtifact(ctx, "abc.wasm")
+				if err != nil {
+					return nil, err
+				}
+
+				defer rc.Close()
+				data, err := io.ReadAll(rc)
+				return string(data), err
+			},
+			expect: "\x00asm",
+		},
+		"artifact not found": {
+			run: func(ctx context.Context) (any, error) {
+				return client.GetArtifact(ctx, "foo.wasm")
+			},
+			expectErr: &APIError{StatusCode: http.StatusNotFound, Message: "Not Found"},
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			got, err := c.run(context.Background())
+			if c.expectErr != nil {
+				var apiErr *APIError
+				require.ErrorAs(t, err, &apiErr)
+				require.Equal(t, c.expectErr, apiErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
+
+func TestClient_SnippetURL(t *testing.T) {
+	client := NewClient(http.DefaultClient, "https://goplay.tools/")
+	require.Equal(t, "https://goplay.tools/snippet/abc", client.SnippetURL("abc"))
+}
+
+func requireBody(t *testing.T, r *http.Request, expect FilesPayload) {
+	t.Helper()
+	require.Equal(t, jsonContentType, r.Header.Get("Content-Type"))
+
+	var got FilesPayload
+	require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	require.Equal(t, expect, got)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", jsonContentType)
+	_ = json.NewEncoder(w).Encode(v)
+}
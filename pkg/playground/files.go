@@ -0,0 +1,90 @@
+package playground
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/x1unix/go-playground/pkg/goplay"
+)
+
+// ReadDir reads playground files from a directory.
+//
+// Only files accepted by goplay.ValidateFilePath are included.
+// Other files, hidden files and directories are skipped.
+func ReadDir(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(fpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fpath != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, fpath)
+		if err != nil {
+			return err
+		}
+
+		name := filepath.ToSlash(relPath)
+		if _, err := goplay.ValidateFilePath(name, true); err != nil {
+			return nil
+		}
+
+		data, err := os.ReadFile(fpath)
+		if err != nil {
+			return err
+		}
+
+		files[name] = string(data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no playground files found in %q", dir)
+	}
+
+	return files, nil
+}
+
+// WriteDir writes files to a directory.
+//
+// Returns error if any file name is not accepted by goplay.ValidateFilePath.
+func WriteDir(dir string, files map[string]string) error {
+	for name := range files {
+		if _, err := goplay.ValidateFilePath(name, true); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for name, data := range files {
+		fpath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fpath), 0755); err != nil {
+			errs = append(errs, fmt.Errorf("can't create parent directory for %q: %w", name, err))
+			continue
+		}
+
+		if err := os.WriteFile(fpath, []byte(data), 0644); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write file %q: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
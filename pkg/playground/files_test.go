@@ -0,0 +1,113 @@
+package playground
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDir(t *testing.T) {
+	cases := map[string]struct {
+		files     map[string]string
+		expect    map[string]string
+		expectErr string
+	}{
+		"read supported files": {
+			files: map[string]string{
+				"go.mod":             "module foo\n",
+				"main.go":            "package main\n",
+				"pkg/foo/foo.go":     "package foo\n",
+				"testdata/data.json": "{}",
+				"notes.txt":          "hello",
+				"README.md":          "# foo",
+				"prog.wasm":          "\x00asm",
+				".hidden.go":         "package main\n",
+				".git/config.txt":    "foo",
+			},
+			expect: map[string]string{
+				"go.mod":             "module foo\n",
+				"main.go":            "package main\n",
+				"pkg/foo/foo.go":     "package foo\n",
+				"testdata/data.json": "{}",
+				"notes.txt":          "hello",
+			},
+		},
+		"no supported files": {
+			files: map[string]string{
+				"README.md": "# foo",
+			},
+			expectErr: "no playground files found",
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range c.files {
+				fpath := filepath.Join(dir, filepath.FromSlash(name))
+				require.NoError(t, os.MkdirAll(filepath.Dir(fpath), 0755))
+				require.NoError(t, os.WriteFile(fpath, []byte(data), 0644))
+			}
+
+			got, err := ReadDir(dir)
+			if c.expectErr != "" {
+				require.ErrorContains(t, err, c.expectErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, c.expect, got)
+		})
+	}
+}
+
+func TestWriteDir(t *testing.T) {
+	cases := map[string]struct {
+		files     map[string]string
+		expectErr string
+	}{
+		"write files": {
+			files: map[string]string{
+				"main.go":        "package main\n",
+				"pkg/foo/foo.go": "package foo\n",
+				"go.mod":         "module foo\n",
+			},
+		},
+		"reject path outside directory": {
+			files: map[string]string{
+				"main.go":      "package main\n",
+				"../../foo.go": "package foo\n",
+			},
+			expectErr: `invalid file name "../../foo.go"`,
+		},
+		"reject unsupported file": {
+			files: map[string]string{
+				"run.sh": "rm -rf /",
+			},
+			expectErr: `invalid file name "run.sh"`,
+		},
+	}
+
+	for n, c := range cases {
+		t.Run(n, func(t *testing.T) {
+			dir := t.TempDir()
+			err := WriteDir(dir, c.files)
+			if c.expectErr != "" {
+				require.ErrorContains(t, err, c.expectErr)
+
+				// No files should be written if any name is invalid.
+				entries, err := os.ReadDir(dir)
+				require.NoError(t, err)
+				require.Empty(t, entries)
+				return
+			}
+
+			require.NoError(t, err)
+			got, err := ReadDir(dir)
+			require.NoError(t, err)
+			require.Equal(t, c.files, got)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package playground
+
+import "github.com/x1unix/go-playground/pkg/goplay"
+
+const (
+	// EventKindStdout is kind of program stdout output event.
+	EventKindStdout = "stdout"
+
+	// EventKindStderr is kind of program stderr output event.
+	EventKindStderr = "stderr"
+)
+
+const (
+	// FormatterUpstream formats code using Go Playground if possible.
+	FormatterUpstream = "upstream"
+
+	// FormatterLocal formats code on a playground server.
+	FormatterLocal = "local"
+)
+
+const (
+	// StyleGofmt formats code like "gofmt" does.
+	StyleGofmt = "gofmt"
+
+	// StyleGofmtSimplify formats and simplifies code like "gofmt -s" does.
+	StyleGofmtSimplify = "gofmt-s"
+
+	// StyleGoimports formats code and fixes imports like "goimports" does.
+	StyleGoimports = "goimports"
+
+	// StyleGofumpt formats code using stricter "gofumpt" rules.
+	StyleGofumpt = "gofumpt"
+)
+
+const (
+	// FormatResultFiles returns formatted files contents.
+	FormatResultFiles = "files"
+
+	// FormatResultEdits returns list of text edits to apply to each file.
+	FormatResultEdits = "edits"
+
+	// FormatResultAll returns both formatted files and text edits.
+	FormatResultAll = "all"
+)
+
+// Severity is diagnostic severity.
+type Severity string
+
+const (
+	// SeverityError is reported for compile errors.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is reported for go vet findings.
+	SeverityWarning Severity = "warning"
+
+	// SeverityInfo is reported for compiler annotations, like "-gcflags=-m" optimization decisions.
+	SeverityInfo Severity = "info"
+)
+
+// Diagnostic is a message reported by Go toolchain for a specific position in a source file.
+type Diagnostic struct {
+	// File is file path relative to the program root.
+	File string `json:"file"`
+
+	// Line is 1-based line number.
+	Line int `json:"line"`
+
+	// Column is 1-based column number in bytes.
+	//
+	// Zero value means that column is unknown.
+	Column int `json:"column,omitempty"`
+
+	// Severity is diagnostic severity.
+	Severity Severity `json:"severity"`
+
+	// Message is diagnostic message.
+	Message string `json:"message"`
+}
+
+// Position is zero-based position in a text document in LSP format.
+type Position struct {
+	Line      uint32 `json:"line"`
+	Character uint32 `json:"character"`
+}
+
+// Range is a range in a text document in LSP format.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// TextEdit is a text edit in LSP format.
+type TextEdit struct {
+	// Range is a range of text to replace.
+	Range Range `json:"range"`
+
+	// NewText is a replacement text.
+	NewText string `json:"newText"`
+}
+
+// FilesPayload is a set of program files with build and run parameters.
+type FilesPayload struct {
+	// Files is map of file names and contents.
+	Files map[string]string `json:"files"`
+
+	// CompilerOptions is a string of extra Go compiler flags.
+	CompilerOptions string `json:"compilerOptions,omitempty"`
+
+	// Backend is run backend name saved with a shared snippet.
+	Backend string `json:"backend,omitempty"`
+
+	// Vet indicates whether go vet is enabled for a shared snippet.
+	Vet bool `json:"vet,omitempty"`
+
+	// GoVersion is Go toolchain version used to build WebAssembly program.
+	GoVersion string `json:"goVersion,omitempty"`
+
+	// Target is WebAssembly build target OS ("js" or "wasip1").
+	Target string `json:"target,omitempty"`
+}
+
+// RunOptions are program run options.
+type RunOptions struct {
+	// Backend is run backend name.
+	//
+	// Current Go version on Go Playground is used if empty.
+	Backend string
+
+	// Vet enables go vet check before run.
+	Vet bool
+}
+
+// FormatOptions are code format options.
+type FormatOptions struct {
+	// Backend is Go Playground backend used to format code.
+	//
+	// Current Go version on Go Playground is used if empty.
+	Backend string
+
+	// Formatter is formatter provider (FormatterUpstream or FormatterLocal).
+	//
+	// Server default is used if empty.
+	Formatter string
+
+	// Style is formatting style.
+	//
+	// StyleGoimports is used if empty.
+	Style string
+
+	// LocalPrefix is comma-separated list of import path prefixes
+	// which are grouped after third-party imports.
+	//
+	// Server default is used if empty.
+	LocalPrefix string
+
+	// Result is format result type.
+	//
+	// FormatResultFiles is used if empty.
+	Result string
+}
+
+// FormatResponse is code format result.
+type FormatResponse struct {
+	// Files contains formatted files contents.
+	Files map[string]string `json:"files,omitempty"`
+
+	// Edits contains list of text edits per file.
+	//
+	// Empty list means that file is already formatted.
+	Edits map[string][]TextEdit `json:"edits,omitempty"`
+}
+
+// RunResponse is program run result.
+type RunResponse struct {
+	// Events is list of program output events.
+	Events []*goplay.CompileEvent `json:"events,omitempty"`
+
+	// Status is program exit code.
+	Status int `json:"status,omitempty"`
+}
+
+// BuildResponse is WebAssembly build result.
+type BuildResponse struct {
+	// FileName is artifact file name.
+	FileName string `json:"fileName"`
+
+	// Target is WebAssembly build target OS.
+	Target string `json:"target,omitempty"`
+
+	// CompilerOutput contains stderr emitted by the compiler.
+	CompilerOutput string `json:"compilerOutput,omitempty"`
+
+	// Diagnostics is a list of compiler annotations parsed from compiler output.
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+
+	// IsTest indicates whether program is a Go test.
+	IsTest bool `json:"isTest,omitempty"`
+
+	// HasBenchmark indicates whether program contains a benchmark.
+	HasBenchmark bool `json:"hasBenchmark,omitempty"`
+
+	// HasFuzz indicates whether program contains a fuzz test.
+	HasFuzz bool `json:"hasFuzz,omitempty"`
+}
+
+type shareResponse struct {
+	SnippetID string `json:"snippetID"`
+}
+
+type errorResponse struct {
+	Error       string       `json:"error"`
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+}
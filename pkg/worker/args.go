@@ -33,7 +33,7 @@ func (args Args) BindIndex(index int, dest interface{}) error {
 
 // Bind binds passed JS arguments to Go values
 //
-// Function supports *int, *bool, *string and ValueUnmarshaler values.
+// Function supports *int, *bool, *string, *[]byte and ValueUnmarshaler values.
 func (args Args) Bind(targets ...interface{}) error {
 	if len(args) != len(targets) {
 		return fmt.Errorf("function expects %d arguments, but %d were passed", len(targets), len(args))
@@ -70,6 +70,13 @@ func BindValue(val js.Value, dest interface{}) error {
 		}
 
 		*v = val.String()
+	case *[]byte:
+		if !val.InstanceOf(js.Global().Get("Uint8Array")) {
+			return fmt.Errorf("value should be Uint8Array, but got %q", valType)
+		}
+
+		*v = make([]byte, val.Length())
+		js.CopyBytesToGo(*v, val)
 	case ValueUnmarshaler:
 		return v.UnmarshalValue(val)
 	default:
@@ -45,7 +45,10 @@ interface Props extends OwnProps, StateProps {
 }
 
 const RunTargetSelectorBase: React.FC<Props> = ({ responsive, disabled, runTarget, goVersions, dispatch }) => {
-  const selectedKey = useMemo(() => keyFromOption(runTarget.target, runTarget.backend), [runTarget])
+  const selectedKey = useMemo(
+    () => keyFromOption(runTarget.target, runTarget.backend ?? runTarget.opts?.goVersion),
+    [runTarget],
+  )
 
   // FIXME: investigate what causes multiple component remount from Header
   const options = useMemo<DropdownOption[]>(() => {
@@ -93,6 +96,9 @@ const RunTargetSelectorBase: React.FC<Props> = ({ responsive, disabled, runTarge
           updateRunTarget({
             target: data!.type,
             backend: data!.backend,
+            opts: {
+              goVersion: data!.goVersion,
+            },
           })
         }}
       />
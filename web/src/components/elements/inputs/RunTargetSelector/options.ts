@@ -18,6 +18,7 @@ export enum OptionColors {
 export type DropdownOption = IDropdownOption<{
   type: TargetType
   backend?: Backend
+  goVersion?: string
   icon?: React.ComponentType
   description?: string
   iconColor?: string
@@ -39,7 +40,8 @@ export const createDropdownOptions = (
   currentVersion = environment.go.currentVersion,
   previousVersion = environment.go.currentVersion,
   wasmVersion = environment.go.currentVersion,
-) => [
+  extraWasmVersions: string[] = [],
+): DropdownOption[] => [
   {
     key: 'section-remote',
     text: 'Run on server',
@@ -93,10 +95,29 @@ export const createDropdownOptions = (
       description: 'Run program in browser as WebAssembly module.',
     },
   },
+  ...extraWasmVersions.map((goVersion) => ({
+    key: keyFromOption(TargetType.WebAssembly, goVersion),
+    text: `Go ${goVersion}`,
+    disabled: !supportsWebAssembly,
+    data: {
+      icon: SiWebassembly,
+      iconColor: OptionColors.WebAssembly,
+      type: TargetType.WebAssembly,
+      goVersion,
+      description: `Run program in browser as WebAssembly module built with Go ${goVersion}.`,
+    },
+  })),
 ]
 
 export const dropdownOptionsFromResponse = ({
   playground: { current = environment.go.currentVersion, goprev = environment.go.previousVersion },
   wasm = environment.go.currentVersion,
+  wasmVersions = [],
 }: VersionsInfo): DropdownOption[] =>
-  createDropdownOptions(removePatchVersion(current), removePatchVersion(goprev), removePatchVersion(wasm))
+  createDropdownOptions(
+    removePatchVersion(current),
+    removePatchVersion(goprev),
+    removePatchVersion(wasm),
+    // The first version is default and is already listed.
+    wasmVersions.slice(1).map(removePatchVersion),
+  )
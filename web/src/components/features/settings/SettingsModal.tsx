@@ -20,7 +20,13 @@ import type { MonacoSettings } from '~/services/config'
 import type { RenderingBackend, TerminalSettings } from '~/store/terminal'
 import { connect, type MonacoParamsChanges, type SettingsState, type StateDispatch } from '~/store'
 
-import { cursorBlinkOptions, cursorLineOptions, fontOptions, terminalBackendOptions } from './options'
+import {
+  cursorBlinkOptions,
+  cursorLineOptions,
+  fontOptions,
+  terminalBackendOptions,
+  vetAnalyzerOptions,
+} from './options'
 import { controlKeyLabel } from '~/utils/dom'
 import { Kbd } from '~/components/elements/misc/Kbd'
 
@@ -106,6 +112,16 @@ class SettingsModal extends ThemeableComponent<Props, SettingsModalState> {
     }
   }
 
+  private toggleVetAnalyzer(name: string, enabled?: boolean) {
+    const current = this.changes.settings?.vetAnalyzers ?? this.props.settings?.vetAnalyzers ?? []
+    const vetAnalyzers = current.filter((v) => v !== name)
+    if (enabled) {
+      vetAnalyzers.push(name)
+    }
+
+    this.touchSettingsProperty({ vetAnalyzers })
+  }
+
   private touchTerminalSettings(changes: Partial<TerminalSettings>) {
     if ('disableTerminalEmulation' in changes) {
       this.setState({ hideTerminalSettings: !!changes.disableTerminalEmulation })
@@ -204,6 +220,24 @@ class SettingsModal extends ThemeableComponent<Props, SettingsModalState> {
                 />
               }
             />
+            <SettingsProperty
+              key="vetAnalyzers"
+              title="Vet Checks"
+              description="Go vet analyzers used to report suspicious code in the editor."
+              control={
+                <Dropdown
+                  multiSelect
+                  placeholder="Disabled"
+                  options={vetAnalyzerOptions}
+                  defaultSelectedKeys={this.props.settings?.vetAnalyzers}
+                  onChange={(_, option) => {
+                    if (option) {
+                      this.toggleVetAnalyzer(option.key.toString(), option.selected)
+                    }
+                  }}
+                />
+              }
+            />
             <SettingsProperty
               key="enableVimMode"
               title="Enable Vim Mode"
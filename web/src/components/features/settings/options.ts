@@ -1,6 +1,7 @@
 import type { IDropdownOption } from '@fluentui/react'
 import { RenderingBackend } from '~/store/terminal/types'
 import { DEFAULT_FONT, getAvailableFonts } from '~/services/fonts'
+import { vetAnalyzers } from '~/services/config'
 
 export const cursorBlinkOptions: IDropdownOption[] = [
   { key: 'blink', text: 'Blink (default)' },
@@ -32,3 +33,8 @@ export const terminalBackendOptions: IDropdownOption[] = [
   { key: RenderingBackend.DOM, text: 'DOM' },
   { key: RenderingBackend.WebGL, text: 'WebGL (experimental)' },
 ]
+
+export const vetAnalyzerOptions: IDropdownOption[] = vetAnalyzers.map((name) => ({
+  key: name,
+  text: name,
+}))
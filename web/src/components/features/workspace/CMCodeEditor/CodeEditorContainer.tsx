@@ -121,6 +121,7 @@ export const CodeEditorContainer: React.FC<CodeEditorContainerProps> = ({ onMoun
   const workspace = useSelector((state: State) => state.workspace)
   const isReadOnly = useSelector(({ status }: State) => status?.loading || status?.running)
   const isServerRuntime = useSelector(({ runTarget }: State) => runTarget.target === TargetType.Server)
+  const buildMarkers = useSelector(({ status }: State) => status?.buildMarkers)
 
   const preferences: EditorPreferences = useMemo(
     () =>
@@ -169,7 +170,14 @@ export const CodeEditorContainer: React.FC<CodeEditorContainerProps> = ({ onMoun
       readonly={isReadOnly}
       linter={{
         delay: 300,
-        handler: (doc) => linterRef.current.check(doc, { warnAboutFakeDateTime: isServerRuntime }),
+        key: buildMarkers,
+        handler: (doc) =>
+          linterRef.current.check(doc, {
+            warnAboutFakeDateTime: isServerRuntime,
+            vetAnalyzers: settings.vetAnalyzers,
+            files: workspace.files,
+            buildMarkers,
+          }),
       }}
       autocomplete={autocompleteRef.current}
       onMount={onMount}
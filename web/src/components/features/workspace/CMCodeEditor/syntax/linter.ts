@@ -1,7 +1,12 @@
-import type { Diagnostic as CMDiagnostic } from '@codemirror/lint'
+import type { Action as CMAction, Diagnostic as CMDiagnostic } from '@codemirror/lint'
 import type { Dispatch } from 'redux'
 import type { Text } from '@codemirror/state'
-import { DiagnosticSeverity, type Diagnostic as LSPDiagnostic } from 'vscode-languageserver-protocol'
+import {
+  DiagnosticSeverity,
+  type CodeAction,
+  type Diagnostic as LSPDiagnostic,
+  type Range,
+} from 'vscode-languageserver-protocol'
 import { Syntax, type DocumentState } from '~/lib/cm-react'
 import type { Disposable } from '~/workers/types'
 import { type AnalyzerWorkerRef, spawnAnalyzerWorker } from '~/workers/analyzer'
@@ -11,6 +16,23 @@ import { getTimeNowUsageMarkers } from './time'
 
 export interface SyntaxCheckOptions {
   warnAboutFakeDateTime?: boolean
+
+  /**
+   * List of enabled vet analyzers.
+   */
+  vetAnalyzers?: string[]
+
+  /**
+   * Contents of all workspace files by path.
+   *
+   * Used to resolve quick fixes which depend on other project files.
+   */
+  files?: Record<string, string>
+
+  /**
+   * Markers reported by the last program build by file name.
+   */
+  buildMarkers?: Record<string, LSPDiagnostic[]>
 }
 
 type Severity = CMDiagnostic['severity']
@@ -30,7 +52,43 @@ const lineCharacterToOffset = (doc: Text, line: number, character: number): numb
   return safeLine.from + safeCharacter
 }
 
-const markersToDiagnostics = (doc: Text, markers: LSPDiagnostic[]): CMDiagnostic[] => {
+const documentRange = (doc: Text): Range => ({
+  start: { line: 0, character: 0 },
+  end: { line: doc.lines - 1, character: doc.line(doc.lines).length },
+})
+
+const isSameRange = (a: Range, b: Range) =>
+  a.start.line === b.start.line &&
+  a.start.character === b.start.character &&
+  a.end.line === b.end.line &&
+  a.end.character === b.end.character
+
+const isSameMarker = (a: LSPDiagnostic, b: LSPDiagnostic) => a.message === b.message && isSameRange(a.range, b.range)
+
+/**
+ * Converts quick fix to a lint action which applies fix edits to a document.
+ */
+const codeActionToAction = (fileName: string, action: CodeAction): CMAction => ({
+  name: action.title,
+  apply: (view) => {
+    const edits = action.edit?.changes?.[fileName] ?? []
+    const { doc } = view.state
+    view.dispatch({
+      changes: edits.map(({ range, newText }) => ({
+        from: lineCharacterToOffset(doc, range.start.line, range.start.character),
+        to: lineCharacterToOffset(doc, range.end.line, range.end.character),
+        insert: newText,
+      })),
+    })
+  },
+})
+
+const markersToDiagnostics = (
+  doc: Text,
+  markers: LSPDiagnostic[],
+  fileName: string,
+  codeActions: CodeAction[] = [],
+): CMDiagnostic[] => {
   return markers.map((marker): CMDiagnostic => {
     const from = lineCharacterToOffset(doc, marker.range.start.line, marker.range.start.character)
     const to = lineCharacterToOffset(doc, marker.range.end.line, marker.range.end.character)
@@ -38,15 +96,53 @@ const markersToDiagnostics = (doc: Text, markers: LSPDiagnostic[]): CMDiagnostic
     const sortedFrom = Math.min(from, to)
     const sortedTo = Math.max(from, to)
 
+    const actions = codeActions
+      .filter(({ diagnostics }) => diagnostics?.some((d) => isSameMarker(d, marker)))
+      .map((action) => codeActionToAction(fileName, action))
+
     return {
       from: sortedFrom,
       to: sortedTo,
       severity: marker.severity ? (mapSeverity[marker.severity] ?? 'error') : 'error',
       message: marker.message,
+      actions: actions.length ? actions : undefined,
     }
   })
 }
 
+/**
+ * Appends build markers which weren't already reported by the analyzer.
+ *
+ * Compiler reports only a start position, so markers are matched by a message and a start position.
+ */
+const withBuildMarkers = (markers: LSPDiagnostic[] = [], buildMarkers: LSPDiagnostic[] = []) => [
+  ...markers,
+  ...buildMarkers.filter(
+    (m) =>
+      !markers.some(
+        (other) =>
+          other.message === m.message &&
+          other.range.start.line === m.range.start.line &&
+          other.range.start.character === m.range.start.character,
+      ),
+  ),
+]
+
+/**
+ * Returns workspace files with the latest document contents.
+ *
+ * Workspace is updated with a delay, so document contents might be newer.
+ */
+const getProjectFiles = (doc: DocumentState, files?: Record<string, string>) => {
+  const result: Record<string, string> = {}
+  for (const [name, contents] of Object.entries(files ?? {})) {
+    result[stripSlash(name)] = contents
+  }
+
+  result[stripSlash(doc.path)] = doc.text.toString()
+  return result
+}
+
 export class GoSyntaxLinter implements Disposable {
   private readonly workerRef: AnalyzerWorkerRef
 
@@ -54,6 +150,21 @@ export class GoSyntaxLinter implements Disposable {
     this.workerRef = spawnAnalyzerWorker()
   }
 
+  private async getCodeActions(fileName: string, files: Record<string, string>, doc: Text): Promise<CodeAction[]> {
+    try {
+      return await this.workerRef.acquire(async (worker) => {
+        return await worker.getCodeActions({
+          fileName,
+          files,
+          range: documentRange(doc),
+        })
+      })
+    } catch (err) {
+      console.error('failed to get code actions', err)
+      return []
+    }
+  }
+
   dispose() {
     this.workerRef.dispose()
   }
@@ -63,25 +174,37 @@ export class GoSyntaxLinter implements Disposable {
       return []
     }
 
+    const fileName = stripSlash(doc.path)
+    const files = getProjectFiles(doc, opts?.files)
     const markers: LSPDiagnostic[] = opts?.warnAboutFakeDateTime ? getTimeNowUsageMarkers(doc) : []
+    let codeActions: CodeAction[] = []
 
     try {
       const response = await this.workerRef.acquire(async (worker) => {
-        return await worker.checkSyntaxErrors({
-          fileName: doc.path,
-          modelVersionId: 1,
-          contents: doc.text.toString(),
+        return await worker.checkFiles({
+          files,
+          analyzers: opts?.vetAnalyzers,
         })
       })
 
-      if (response.fileName === doc.path && response.markers) {
-        markers.push(...response.markers)
+      // Changes in a document might cause problems in other project files.
+      for (const name of Object.keys(files)) {
+        if (name !== fileName && name.endsWith('.go')) {
+          this.dispatcher(newMarkerAction(name, withBuildMarkers(response.markers[name], opts?.buildMarkers?.[name])))
+        }
+      }
+
+      const fileMarkers = response.markers[fileName]
+      if (fileMarkers?.length) {
+        markers.push(...fileMarkers)
+        codeActions = await this.getCodeActions(fileName, files, doc.text)
       }
     } catch (err) {
       console.error('failed to perform syntax check', err)
     }
 
-    this.dispatcher(newMarkerAction(stripSlash(doc.path), markers))
-    return markersToDiagnostics(doc.text, markers)
+    const allMarkers = withBuildMarkers(markers, opts?.buildMarkers?.[fileName])
+    this.dispatcher(newMarkerAction(fileName, allMarkers))
+    return markersToDiagnostics(doc.text, allMarkers, fileName, codeActions)
   }
 }
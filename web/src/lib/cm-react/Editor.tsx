@@ -1,6 +1,7 @@
 import React from 'react'
 
 import { indentWithTab } from '@codemirror/commands'
+import { forceLinting } from '@codemirror/lint'
 import { EditorState, type Extension, type StateEffect } from '@codemirror/state'
 import { EditorView, keymap, type ViewUpdate } from '@codemirror/view'
 import { vscodeKeymap } from '@replit/codemirror-vscode-keymap'
@@ -308,6 +309,10 @@ export class Editor extends React.Component<EditorProps, State> {
       currentStateData = getBufferState(this.editor.state)
     }
 
+    if (!fileChanged && prevProps.linter?.key !== this.props.linter?.key) {
+      forceLinting(this.editor)
+    }
+
     const { effects, isChanged } = checkBufferStateChanges({
       props: this.props,
       buffState: currentStateData,
@@ -14,6 +14,13 @@ export interface LinterConfig {
    * Function to handle lint requests.
    */
   handler: (doc: DocumentState) => readonly Diagnostic[] | Promise<readonly Diagnostic[]>
+
+  /**
+   * Changing the key triggers re-check of a current document.
+   *
+   * Used to show diagnostics from external sources without waiting for a document change.
+   */
+  key?: unknown
 }
 
 const DEFAULT_LINT_INTERVAL = 300
@@ -8,9 +8,31 @@ import {
   type ShareResponse,
   type VersionsInfo,
   type FilesPayload,
+  type ModuleVersions,
+  type LatestModuleVersion,
+  type BuildTarget,
+  type BuildDiagnostic,
 } from './models'
 import type { IAPIClient } from './interface'
 
+/**
+ * Error returned by API server.
+ */
+export class APIError extends Error {
+  constructor(
+    message: string,
+    readonly status: number,
+
+    /**
+     * Build errors mapped to program files.
+     */
+    readonly diagnostics?: BuildDiagnostic[],
+  ) {
+    super(message)
+    this.name = 'APIError'
+  }
+}
+
 export class Client implements IAPIClient {
   constructor(private readonly baseUrl: string) {}
 
@@ -26,8 +48,13 @@ export class Client implements IAPIClient {
    *
    * WASM file can be downloaded using {@link getArtifact} call.
    */
-  async build(files: Record<string, string>, compilerOptions?: string): Promise<BuildResponse> {
-    return await this.post<BuildResponse>(`/v2/compile`, { files, compilerOptions })
+  async build(
+    files: Record<string, string>,
+    compilerOptions?: string,
+    goVersion?: string,
+    target?: BuildTarget,
+  ): Promise<BuildResponse> {
+    return await this.post<BuildResponse>(`/v2/compile`, { files, compilerOptions, goVersion, target })
   }
 
   /**
@@ -83,6 +110,20 @@ export class Client implements IAPIClient {
     return await this.get<VersionsInfo>('/backends/info')
   }
 
+  /**
+   * Returns list of released versions of a Go module.
+   */
+  async getModuleVersions(modPath: string): Promise<ModuleVersions> {
+    return await this.get<ModuleVersions>(`/v2/modules/${encodeModulePath(modPath)}/versions`)
+  }
+
+  /**
+   * Returns latest version of a Go module.
+   */
+  async getLatestModuleVersion(modPath: string): Promise<LatestModuleVersion> {
+    return await this.get<LatestModuleVersion>(`/v2/modules/${encodeModulePath(modPath)}/latest`)
+  }
+
   /**
    * Returns important announcement message to be displayed at header banner.
    */
@@ -122,7 +163,7 @@ export class Client implements IAPIClient {
       throw new Error(`${rsp.status} ${rsp.statusText}`)
     }
 
-    let errBody: { error: string }
+    let errBody: { error: string; diagnostics?: BuildDiagnostic[] }
     try {
       errBody = await rsp.json()
     } catch (_) {
@@ -132,6 +173,8 @@ export class Client implements IAPIClient {
       }
     }
 
-    throw new Error(errBody.error)
+    throw new APIError(errBody.error, rsp.status, errBody.diagnostics)
   }
 }
+
+const encodeModulePath = (modPath: string) => modPath.split('/').map(encodeURIComponent).join('/')
@@ -6,6 +6,9 @@ import type {
   VersionsInfo,
   FilesPayload,
   AnnouncementMessage,
+  ModuleVersions,
+  LatestModuleVersion,
+  BuildTarget,
 } from './models'
 
 export interface IAPIClient {
@@ -15,7 +18,12 @@ export interface IAPIClient {
 
   format: (files: Record<string, string>) => Promise<FilesPayload>
 
-  build: (files: Record<string, string>, compilerOptions?: string) => Promise<BuildResponse>
+  build: (
+    files: Record<string, string>,
+    compilerOptions?: string,
+    goVersion?: string,
+    target?: BuildTarget,
+  ) => Promise<BuildResponse>
 
   getArtifact: (fileName: string) => Promise<Response>
 
@@ -25,5 +33,9 @@ export interface IAPIClient {
 
   getBackendVersions: () => Promise<VersionsInfo>
 
+  getModuleVersions: (modPath: string) => Promise<ModuleVersions>
+
+  getLatestModuleVersion: (modPath: string) => Promise<LatestModuleVersion>
+
   getAnnouncementMessage: () => Promise<AnnouncementMessage | null>
 }
@@ -1,3 +1,4 @@
 export * from './run'
 export * from './version'
 export * from './announcement'
+export * from './modules'
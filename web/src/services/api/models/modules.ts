@@ -0,0 +1,14 @@
+export interface ModuleVersions {
+  module: string
+
+  /**
+   * Released module versions sorted from the newest to the oldest.
+   */
+  versions: string[]
+}
+
+export interface LatestModuleVersion {
+  module: string
+  version: string
+  time: string
+}
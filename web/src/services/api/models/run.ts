@@ -41,9 +41,35 @@ export interface FilesPayload {
   files: Record<string, string>
 }
 
+export type BuildTarget = 'js' | 'wasip1'
+
+export type DiagnosticSeverity = 'error' | 'warning' | 'info'
+
+export interface BuildDiagnostic {
+  /**
+   * File path relative to the workspace root.
+   */
+  file: string
+
+  /**
+   * 1-based line number.
+   */
+  line: number
+
+  /**
+   * 1-based column number. Omitted if unknown.
+   */
+  column?: number
+
+  severity: DiagnosticSeverity
+  message: string
+}
+
 export interface BuildResponse {
   fileName: string
+  target?: BuildTarget
   compilerOutput?: string
+  diagnostics?: BuildDiagnostic[]
   isTest?: boolean
   hasBenchmark?: boolean
   hasFuzz?: boolean
@@ -10,4 +10,11 @@ export interface VersionsInfo {
   }
 
   wasm: string
+
+  /**
+   * Go versions available to build WebAssembly programs.
+   *
+   * The first version is default.
+   */
+  wasmVersions?: string[]
 }
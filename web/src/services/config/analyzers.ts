@@ -0,0 +1,42 @@
+/**
+ * Names of vet analyzers supported by the code analyzer worker.
+ *
+ * Keep in sync with "internal/analyzer/check/vet.go".
+ */
+export const vetAnalyzers = [
+  'appends',
+  'assign',
+  'atomic',
+  'bools',
+  'composite',
+  'copylock',
+  'defers',
+  'errorsas',
+  'hostport',
+  'httpresponse',
+  'ifaceassert',
+  'loopclosure',
+  'lostcancel',
+  'nilfunc',
+  'printf',
+  'shadow',
+  'shift',
+  'sigchanyzer',
+  'slog',
+  'stdmethods',
+  'stringintconv',
+  'structtag',
+  'testinggoroutine',
+  'tests',
+  'timeformat',
+  'unmarshal',
+  'unreachable',
+  'unsafeptr',
+  'unusedresult',
+  'waitgroup',
+]
+
+/**
+ * Vet analyzers enabled by default. Same set as "go vet" command runs.
+ */
+export const defaultVetAnalyzers = vetAnalyzers.filter((name) => name !== 'shadow')
@@ -6,6 +6,7 @@ import { supportsPreferColorScheme } from '~/utils/theme'
 
 import { type RunTargetConfig, TargetType, defaultRunTarget } from './target'
 import { type MonacoSettings, defaultMonacoSettings } from './monaco'
+import { defaultVetAnalyzers } from './analyzers'
 
 const DARK_THEME_KEY = 'ui.darkTheme.enabled'
 const USE_SYSTEM_THEME_KEY = 'ui.darkTheme.useSystem'
@@ -16,6 +17,7 @@ const AUTOFORMAT_KEY = 'go.build.autoFormat'
 const MONACO_SETTINGS = 'ms.monaco.settings'
 const PANEL_SETTINGS = 'ui.layout.panel'
 const GOPROXY_URL = 'go.env.GOPROXY'
+const VET_ANALYZERS_KEY = 'go.vet.analyzers'
 const LAST_DISMISSED_ANNOUNCEMENT = 'ui.announcements.lastDismissed'
 
 const setThemeStyles = (isDark: boolean) => loadTheme(isDark ? DarkTheme : LightTheme)
@@ -65,6 +67,19 @@ const Config = {
     this.setString(GOPROXY_URL, newVal)
   },
 
+  get vetAnalyzers(): string[] {
+    const val = this.getString<string | null>(VET_ANALYZERS_KEY, null)
+    if (val === null) {
+      return defaultVetAnalyzers
+    }
+
+    return val.split(',').filter((name) => name.length > 0)
+  },
+
+  set vetAnalyzers(names: string[]) {
+    this.setString(VET_ANALYZERS_KEY, names.join(','))
+  },
+
   get lastDismissedAnnouncement() {
     return this.getString<string | null>(LAST_DISMISSED_ANNOUNCEMENT, null)
   },
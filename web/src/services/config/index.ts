@@ -1,3 +1,4 @@
 export { default, type IConfig } from './config'
 export * from './target'
 export * from './monaco'
+export * from './analyzers'
@@ -2,6 +2,13 @@ import { type Backend } from '~/services/api'
 
 export interface RunTargetOptions {
   compilerOptions?: string
+
+  /**
+   * Go version used to build WebAssembly program.
+   *
+   * Default server version is used if empty.
+   */
+  goVersion?: string
 }
 
 /**
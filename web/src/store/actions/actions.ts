@@ -6,6 +6,7 @@ export enum ActionType {
   MONACO_SETTINGS_CHANGE = 'MONACO_SETTINGS_CHANGE',
   UI_STATE_CHANGE = 'UI_STATE_CHANGE',
   MARKER_CHANGE = 'MARKER_CHANGE',
+  BUILD_MARKERS_CHANGE = 'BUILD_MARKERS_CHANGE',
   CURSOR_POSITION_CHANGE = 'CURSOR_POSITION_CHANGE',
   PANEL_STATE_CHANGE = 'PANEL_STATE_CHANGE',
   SETTINGS_CHANGE = 'SETTINGS_CHANGE',
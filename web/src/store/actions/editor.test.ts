@@ -0,0 +1,52 @@
+import { assert, describe, test } from 'vitest'
+import { DiagnosticSeverity } from 'vscode-languageserver-protocol'
+
+import { buildDiagnosticsToMarkers } from './editor'
+
+describe('buildDiagnosticsToMarkers', () => {
+  test('groups diagnostics by file', () => {
+    const markers = buildDiagnosticsToMarkers([
+      { file: 'main.go', line: 4, column: 2, severity: 'error', message: 'undefined: foo' },
+      { file: 'pkg/util.go', line: 3, severity: 'warning', message: 'unreachable code' },
+      { file: 'main.go', line: 10, column: 6, severity: 'info', message: 'can inline bar' },
+    ])
+
+    assert.deepEqual(markers, {
+      'main.go': [
+        {
+          severity: DiagnosticSeverity.Error,
+          message: 'undefined: foo',
+          source: 'go build',
+          range: {
+            start: { line: 3, character: 1 },
+            end: { line: 3, character: 1 },
+          },
+        },
+        {
+          severity: DiagnosticSeverity.Information,
+          message: 'can inline bar',
+          source: 'go build',
+          range: {
+            start: { line: 9, character: 5 },
+            end: { line: 9, character: 5 },
+          },
+        },
+      ],
+      'pkg/util.go': [
+        {
+          severity: DiagnosticSeverity.Warning,
+          message: 'unreachable code',
+          source: 'go build',
+          range: {
+            start: { line: 2, character: 0 },
+            end: { line: 2, character: Number.MAX_SAFE_INTEGER },
+          },
+        },
+      ],
+    })
+  })
+
+  test('returns empty map for empty list', () => {
+    assert.deepEqual(buildDiagnosticsToMarkers([]), {})
+  })
+})
@@ -1,4 +1,5 @@
-import type { Diagnostic } from 'vscode-languageserver-protocol'
+import { DiagnosticSeverity, type Diagnostic } from 'vscode-languageserver-protocol'
+import type { BuildDiagnostic, DiagnosticSeverity as BuildDiagnosticSeverity } from '~/services/api'
 import type { Position } from '../state'
 
 import { ActionType } from './actions'
@@ -16,6 +17,45 @@ export interface MarkerChangePayload {
   markers?: Diagnostic[] | null
 }
 
+const buildSeverities: Record<BuildDiagnosticSeverity, DiagnosticSeverity> = {
+  error: DiagnosticSeverity.Error,
+  warning: DiagnosticSeverity.Warning,
+  info: DiagnosticSeverity.Information,
+}
+
+/**
+ * Converts diagnostics reported by Go toolchain to editor markers grouped by file name.
+ *
+ * Diagnostics without a column mark the whole line.
+ */
+export const buildDiagnosticsToMarkers = (diagnostics: BuildDiagnostic[]): Record<string, Diagnostic[]> => {
+  const markers: Record<string, Diagnostic[]> = {}
+  for (const { file, line, column, severity, message } of diagnostics) {
+    const start = { line: line - 1, character: column ? column - 1 : 0 }
+    const end = column ? start : { line: start.line, character: Number.MAX_SAFE_INTEGER }
+
+    markers[file] ??= []
+    markers[file].push({
+      severity: buildSeverities[severity] ?? DiagnosticSeverity.Error,
+      message,
+      source: 'go build',
+      range: { start, end },
+    })
+  }
+
+  return markers
+}
+
+/**
+ * Replaces editor markers reported by the last program build.
+ */
+export const newBuildMarkersAction = (diagnostics?: BuildDiagnostic[] | null) => ({
+  type: ActionType.BUILD_MARKERS_CHANGE,
+  payload: buildDiagnosticsToMarkers(diagnostics ?? []),
+})
+
+export type BuildMarkersChangePayload = Record<string, Diagnostic[]>
+
 export const newCursorPositionAction = (position: Position) => ({
   type: ActionType.CURSOR_POSITION_CHANGE,
   payload: {
@@ -2,12 +2,13 @@ import { TargetType } from '~/services/config'
 import { SECOND, setTimeoutNanos } from '~/utils/duration'
 import { createStdio, GoProcess } from '~/workers/go/client'
 import { buildGoTestFlags, requiresWasmEnvironment } from '~/lib/sourceutil'
-import client, { type EvalEvent, EvalEventKind } from '~/services/api'
+import client, { APIError, type EvalEvent, EvalEventKind } from '~/services/api'
 import { isProjectRequiresGoMod } from '~/services/examples'
 
 import type { DispatchFn, StateProvider } from '../../helpers'
 import { newRemoveNotificationAction, NotificationIDs } from '../../notifications'
 import {
+  newBuildMarkersAction,
   newErrorAction,
   newLoadingAction,
   newProgramFinishAction,
@@ -100,6 +101,7 @@ export const runFileDispatcher: Dispatcher = async (dispatch: DispatchFn, getSta
     }
 
     dispatch(newLoadingAction())
+    dispatch(newBuildMarkersAction())
     if (settings.autoFormat) {
       const rsp = await client.format(files, backend)
       files = rsp.files
@@ -125,7 +127,8 @@ export const runFileDispatcher: Dispatcher = async (dispatch: DispatchFn, getSta
       }
       case TargetType.WebAssembly: {
         const compilerOptions = opts?.compilerOptions?.trim() || undefined
-        const buildResponse = await client.build(files, compilerOptions)
+        const buildResponse = await client.build(files, compilerOptions, opts?.goVersion)
+        dispatch(newBuildMarkersAction(buildResponse.diagnostics))
         const hasCompilerOutput = Boolean(buildResponse.compilerOutput?.trim().length)
 
         if (hasCompilerOutput) {
@@ -176,6 +179,11 @@ export const runFileDispatcher: Dispatcher = async (dispatch: DispatchFn, getSta
         dispatch(newErrorAction(`AppError: Unknown Go runtime type "${runTarget}"`))
     }
   } catch (err: any) {
+    if (err instanceof APIError && err.diagnostics?.length) {
+      // Highlight build errors in the editor.
+      dispatch(newBuildMarkersAction(err.diagnostics))
+    }
+
     dispatch(newErrorAction(err.message))
   }
 }
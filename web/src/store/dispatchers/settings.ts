@@ -38,6 +38,10 @@ export const newSettingsChangeDispatcher =
       config.enableVimMode = !!changes.enableVimMode
     }
 
+    if ('vetAnalyzers' in changes) {
+      config.vetAnalyzers = changes.vetAnalyzers ?? []
+    }
+
     if ('autoSave' in changes) {
       config.autoSave = !!changes.autoSave
 
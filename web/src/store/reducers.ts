@@ -10,7 +10,7 @@ import notificationReducers from './notifications/reducers'
 import { initialTerminalState } from './terminal/state'
 import { reducers as terminalReducers } from './terminal/reducers'
 
-import { type FilePayload, WorkspaceAction } from '~/store/workspace/actions'
+import { type FilePayload, type FileUpdatePayload, WorkspaceAction } from '~/store/workspace/actions'
 import { initialWorkspaceState } from '~/store/workspace/state'
 import { reducers as workspaceReducers } from '~/store/workspace/reducers'
 
@@ -21,6 +21,7 @@ import {
   type MonacoParamsChanges,
   type CursorPositionChangePayload,
   type MarkerChangePayload,
+  type BuildMarkersChangePayload,
 } from './actions'
 import { mapByAction } from './helpers'
 
@@ -34,6 +35,7 @@ const initialSettingsState: SettingsState = {
   useSystemTheme: config.useSystemTheme,
   enableVimMode: config.enableVimMode,
   goProxyUrl: config.goProxyUrl,
+  vetAnalyzers: config.vetAnalyzers,
 }
 
 const reducers = {
@@ -64,15 +66,27 @@ const reducers = {
         lastError: null,
       }),
       [WorkspaceAction.REMOVE_FILE]: (
-        { markers, ...state }: StatusState,
+        { markers, buildMarkers, ...state }: StatusState,
         { payload: { filename } }: Action<FilePayload>,
       ) => {
         const { [filename]: _, ...newMarkers } = markers ?? {}
+        const { [filename]: __, ...newBuildMarkers } = buildMarkers ?? {}
         return {
           ...state,
           markers: newMarkers,
+          buildMarkers: newBuildMarkers,
         }
       },
+      [WorkspaceAction.UPDATE_FILE]: (s: StatusState, { payload: { filename } }: Action<FileUpdatePayload>) => {
+        if (!s.buildMarkers?.[filename]) {
+          return s
+        }
+
+        // Build markers positions are no longer valid after file change.
+        const { [filename]: _, ...buildMarkers } = s.buildMarkers
+        return { ...s, buildMarkers }
+      },
+      [WorkspaceAction.UPDATE_FILES]: (s: StatusState) => ({ ...s, buildMarkers: undefined }),
       [ActionType.ERROR]: (s: StatusState, a: Action<string>) => ({
         ...s,
         loading: false,
@@ -91,6 +105,7 @@ const reducers = {
         running: true,
         dirty: true,
         events: [],
+        buildMarkers: s.buildMarkers,
       }),
       [ActionType.EVAL_EVENT]: (s: StatusState, a: Action<EvalEvent>) => {
         return {
@@ -99,6 +114,7 @@ const reducers = {
           dirty: true,
           running: s.running,
           events: s.events ? s.events.concat(a.payload) : [a.payload],
+          buildMarkers: s.buildMarkers,
         }
       },
       [ActionType.EVAL_FINISH]: (s: StatusState, _: Action) => ({
@@ -122,6 +138,10 @@ const reducers = {
           [payload.fileName]: payload.markers?.length ? payload.markers : null,
         },
       }),
+      [ActionType.BUILD_MARKERS_CHANGE]: (s: StatusState, { payload }: Action<BuildMarkersChangePayload>) => ({
+        ...s,
+        buildMarkers: payload,
+      }),
       [ActionType.CURSOR_POSITION_CHANGE]: (s: StatusState, { payload }: Action<CursorPositionChangePayload>) => ({
         ...s,
         cursorPosition: payload.position,
@@ -25,6 +25,13 @@ export interface StatusState {
   lastError?: string | null
   events?: EvalEvent[]
   markers?: Record<string, Diagnostic[] | null>
+
+  /**
+   * Markers reported by the last program build.
+   *
+   * Markers of a file are discarded once the file is changed.
+   */
+  buildMarkers?: Record<string, Diagnostic[]>
   cursorPosition?: Position
 }
 
@@ -35,6 +42,7 @@ export interface SettingsState {
   autoFormat: boolean
   enableVimMode: boolean
   goProxyUrl: string
+  vetAnalyzers: string[]
 }
 
 export interface PanelState {
@@ -1,27 +1,5 @@
 import * as Comlink from 'comlink'
-import { type WrappedGoModule, startAnalyzer } from './bootstrap'
-import type { AnalyzeRequest, AnalyzeResponse } from './types'
+import { startAnalyzer } from './bootstrap'
+import { WorkerHandler } from './handler'
 
-// TODO: refactor this together with the Go worker API
-
-export class WorkerHandler {
-  private mod?: WrappedGoModule
-  private readonly initPromise = startAnalyzer()
-
-  private async getModule() {
-    this.mod ??= await this.initPromise
-    return this.mod
-  }
-
-  async checkSyntaxErrors({ fileName, modelVersionId, contents }: AnalyzeRequest): Promise<AnalyzeResponse> {
-    const mod = await this.getModule()
-    const { markers } = await mod.analyzeCode(contents)
-    return {
-      fileName,
-      modelVersionId,
-      markers,
-    }
-  }
-}
-
-Comlink.expose(new WorkerHandler())
+Comlink.expose(new WorkerHandler(startAnalyzer()))
@@ -1,4 +1,4 @@
-import type { Diagnostic } from 'vscode-languageserver-protocol'
+import type { CodeAction, Diagnostic, Range } from 'vscode-languageserver-protocol'
 import '~/lib/go/wasm_exec.js'
 import { getWasmUrl } from '~/services/api/resources'
 import { instantiateStreaming } from '~/lib/go/common'
@@ -8,6 +8,11 @@ type CallArgs = [...any[], JSONCallback]
 
 interface GoModule {
   analyzeCode: (code: string, cb: JSONCallback) => void
+  analyzeFiles: (files: Record<string, string>, cb: JSONCallback) => void
+  fileCodeActions: (files: Record<string, string>, fileName: string, range: Range, cb: JSONCallback) => void
+  loadExportData: (archive: Uint8Array, cb: JSONCallback) => void
+  loadPackageIndex: (index: string, cb: JSONCallback) => void
+  setAnalyzers: (names: string, cb: JSONCallback) => void
   exit: () => void
 }
 
@@ -16,8 +21,18 @@ interface AnalyzeResult {
   markers: Diagnostic[] | null
 }
 
+interface AnalyzeFilesResult {
+  hasErrors: boolean
+  markers: Record<string, Diagnostic[]>
+}
+
 export interface WrappedGoModule {
   analyzeCode: (code: string) => Promise<AnalyzeResult>
+  analyzeFiles: (files: Record<string, string>) => Promise<AnalyzeFilesResult>
+  fileCodeActions: (files: Record<string, string>, fileName: string, range: Range) => Promise<CodeAction[] | null>
+  loadExportData: (archive: Uint8Array) => Promise<void>
+  loadPackageIndex: (index: string) => Promise<void>
+  setAnalyzers: (names: string) => Promise<void>
   exit: () => Promise<void>
 }
 
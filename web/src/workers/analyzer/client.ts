@@ -1,5 +1,5 @@
 import type { Remote } from 'comlink'
-import type { WorkerHandler } from './analyzer.worker'
+import type { WorkerHandler } from './handler'
 import { WorkerRef } from '../types'
 
 export type AnalyzerWorker = Remote<WorkerHandler>
@@ -0,0 +1,95 @@
+import { afterEach, assert, describe, test, vi } from 'vitest'
+import { DiagnosticSeverity, type Diagnostic } from 'vscode-languageserver-protocol'
+
+import type { WrappedGoModule } from './bootstrap'
+import { WorkerHandler } from './handler'
+
+const typeError: Diagnostic = {
+  severity: DiagnosticSeverity.Error,
+  message: 'undefined: foo',
+  range: {
+    start: { line: 5, character: 13 },
+    end: { line: 5, character: 16 },
+  },
+}
+
+/**
+ * Returns a module stub which reports type errors only after export data is loaded, like the Go analyzer does.
+ */
+const newModuleStub = () => {
+  let exportDataLoaded = false
+  return {
+    analyzeFiles: vi.fn(async (files: Record<string, string>) => ({
+      hasErrors: exportDataLoaded,
+      markers: exportDataLoaded ? { 'main.go': [typeError] } : {},
+    })),
+    loadExportData: vi.fn(async (archive: Uint8Array) => {
+      exportDataLoaded = true
+    }),
+    loadPackageIndex: vi.fn(async (index: string) => {}),
+    setAnalyzers: vi.fn(async (names: string) => {}),
+    exit: vi.fn(async () => {}),
+  }
+}
+
+const stubFetch = (ok: boolean) => {
+  const fetchMock = vi.fn(async (url: string) =>
+    ok ? new Response(url.endsWith('.json') ? '{}' : new Uint8Array([1, 2, 3])) : new Response(null, { status: 404 }),
+  )
+
+  vi.stubGlobal('fetch', fetchMock)
+  return fetchMock
+}
+
+const request = {
+  files: {
+    'go.mod': 'module example.com/foo\n',
+    'main.go': 'package main\n\nimport "fmt"\n\nfunc main() {\n\tfmt.Println(foo.Bar())\n}\n',
+  },
+}
+
+describe('WorkerHandler', () => {
+  afterEach(() => {
+    vi.unstubAllGlobals()
+    vi.restoreAllMocks()
+  })
+
+  test('loads export data before the first check', async () => {
+    const fetchMock = stubFetch(true)
+    const mod = newModuleStub()
+    const handler = new WorkerHandler(Promise.resolve(mod as WrappedGoModule))
+
+    const rsp = await handler.checkFiles(request)
+    assert.deepEqual(rsp.markers, { 'main.go': [typeError] })
+    assert.deepEqual(
+      fetchMock.mock.calls.map(([url]) => url),
+      ['/data/go-exports.tar.gz', '/data/go-index.json'],
+    )
+    assert.deepEqual(mod.loadExportData.mock.calls[0], [new Uint8Array([1, 2, 3])])
+    assert.deepEqual(mod.loadPackageIndex.mock.calls[0], ['{}'])
+  })
+
+  test('reports syntax errors if export data is unavailable', async () => {
+    stubFetch(false)
+    vi.spyOn(console, 'error').mockImplementation(() => {})
+    const mod = newModuleStub()
+    const handler = new WorkerHandler(Promise.resolve(mod as WrappedGoModule))
+
+    const rsp = await handler.checkFiles(request)
+    assert.deepEqual(rsp.markers, {})
+    assert.equal(mod.loadExportData.mock.calls.length, 0)
+    assert.deepEqual(mod.analyzeFiles.mock.calls, [[request.files]])
+  })
+
+  test('applies changed analyzers list', async () => {
+    stubFetch(true)
+    const mod = newModuleStub()
+    const handler = new WorkerHandler(Promise.resolve(mod as WrappedGoModule))
+
+    await handler.checkFiles(request)
+    await handler.checkFiles({ ...request, analyzers: ['printf', 'shadow'] })
+    await handler.checkFiles({ ...request, analyzers: ['printf', 'shadow'] })
+    await handler.checkFiles({ ...request, analyzers: [] })
+    assert.deepEqual(mod.setAnalyzers.mock.calls, [['printf,shadow'], ['']])
+  })
+})
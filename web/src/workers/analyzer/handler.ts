@@ -0,0 +1,76 @@
+import type { WrappedGoModule } from './bootstrap'
+import type { AnalyzeRequest, AnalyzeResponse, CodeActionsRequest, CodeActionsResponse } from './types'
+
+// Generated by "make go-exports" and "make go-index".
+const exportDataUrl = '/data/go-exports.tar.gz'
+const packageIndexUrl = '/data/go-index.json'
+
+const fetchData = async (url: string) => {
+  const rsp = await fetch(url)
+  if (!rsp.ok) {
+    throw new Error(`Failed to fetch ${url}: ${rsp.status} ${rsp.statusText}`)
+  }
+
+  return rsp
+}
+
+/**
+ * Loads standard library export data and packages index into the analyzer.
+ *
+ * Analyzer reports only syntax errors until export data is loaded.
+ */
+export const loadTypeInfo = async (mod: WrappedGoModule) => {
+  const [exportData, packageIndex] = await Promise.all([
+    fetchData(exportDataUrl).then(async (rsp) => await rsp.arrayBuffer()),
+    fetchData(packageIndexUrl).then(async (rsp) => await rsp.text()),
+  ])
+
+  await mod.loadExportData(new Uint8Array(exportData))
+  await mod.loadPackageIndex(packageIndex)
+}
+
+export class WorkerHandler {
+  private mod?: WrappedGoModule
+  private analyzers?: string
+  private readonly initPromise: Promise<WrappedGoModule>
+
+  constructor(modPromise: Promise<WrappedGoModule>) {
+    this.initPromise = modPromise.then(async (mod) => {
+      try {
+        await loadTypeInfo(mod)
+      } catch (err) {
+        console.error('analyzer: failed to load type information, type checking is disabled', err)
+      }
+
+      return mod
+    })
+  }
+
+  private async getModule() {
+    this.mod ??= await this.initPromise
+    return this.mod
+  }
+
+  private async setAnalyzers(mod: WrappedGoModule, names?: string[]) {
+    const list = names?.join(',')
+    if (list === undefined || list === this.analyzers) {
+      return
+    }
+
+    await mod.setAnalyzers(list)
+    this.analyzers = list
+  }
+
+  async checkFiles({ files, analyzers }: AnalyzeRequest): Promise<AnalyzeResponse> {
+    const mod = await this.getModule()
+    await this.setAnalyzers(mod, analyzers)
+    const { markers } = await mod.analyzeFiles(files)
+    return { markers }
+  }
+
+  async getCodeActions({ fileName, files, range }: CodeActionsRequest): Promise<CodeActionsResponse> {
+    const mod = await this.getModule()
+    const actions = await mod.fileCodeActions(files, fileName, range)
+    return actions ?? []
+  }
+}
@@ -1,13 +1,38 @@
-import type { Diagnostic } from 'vscode-languageserver-protocol'
+import type { CodeAction, Diagnostic, Range } from 'vscode-languageserver-protocol'
 
 export interface AnalyzeRequest {
-  fileName: string
-  contents: string
-  modelVersionId: number
+  /**
+   * Contents of all project files by path.
+   */
+  files: Record<string, string>
+
+  /**
+   * List of enabled vet analyzers.
+   *
+   * Analyzer keeps previous list if not set.
+   */
+  analyzers?: string[]
 }
 
 export interface AnalyzeResponse {
+  /**
+   * List of problems by file path.
+   */
+  markers: Record<string, Diagnostic[]>
+}
+
+export interface CodeActionsRequest {
   fileName: string
-  modelVersionId: number
-  markers: Diagnostic[] | null
+
+  /**
+   * Contents of all project files by path.
+   */
+  files: Record<string, string>
+
+  /**
+   * Range of problems to fix.
+   */
+  range: Range
 }
+
+export type CodeActionsResponse = CodeAction[]
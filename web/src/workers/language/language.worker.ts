@@ -16,6 +16,13 @@ import type { SymbolIndexItem } from '~/services/storage/types'
 
 const completionVersionKey = 'completionItems.v2'
 
+/**
+ * Supported Go index file format version.
+ *
+ * @see internal/pkgindex/index/types.go
+ */
+const goIndexFileVersion = 3
+
 const TTL_DAYS = 7
 
 const getExpireTime = () => addDays(new Date(), TTL_DAYS)
@@ -186,7 +193,7 @@ export class WorkerHandler {
         }
 
         const data: GoIndexFile = await rsp.json()
-        if (data.version !== 2) {
+        if (data.version !== goIndexFileVersion) {
           console.warn(`unsupported symbol index version: ${data.version}, skip update.`)
           return
         }
@@ -21,6 +21,26 @@ export interface Symbols {
   packages: SymbolSource[]
 }
 
+/**
+ * Type methods and struct fields.
+ *
+ * @see internal/pkgindex/index/types.go
+ */
+export interface Members {
+  names: string[]
+  docs: string[]
+  details: string[]
+  signatures: string[]
+  insertTexts: string[]
+  insertTextRules: InsertTextFormat[]
+  kinds: CompletionItemKind[]
+
+  /**
+   * Index of receiver type in symbols list.
+   */
+  types: number[]
+}
+
 /**
  * @see internal/pkgindex/index/types.go
  */
@@ -55,4 +75,9 @@ export interface GoIndexFile {
    * List of symbols of each package.
    */
   symbols: Symbols
+
+  /**
+   * List of methods and fields of package types.
+   */
+  members: Members
 }